@@ -0,0 +1,21 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/madkins23/go-slog/bench/tests"
+	"github.com/madkins23/go-slog/creator/madkinsflashbinary"
+	"github.com/madkins23/go-slog/handlers/flash"
+)
+
+// BenchmarkMadkinsFlashBinaryCBOR runs benchmarks for the madkins/flash handler with CBOR output.
+func BenchmarkMadkinsFlashBinaryCBOR(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashbinary.Creator(flash.FormatCBOR))
+	tests.Run(b, slogSuite)
+}
+
+// BenchmarkMadkinsFlashBinaryMsgPack runs benchmarks for the madkins/flash handler with MessagePack output.
+func BenchmarkMadkinsFlashBinaryMsgPack(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashbinary.Creator(flash.FormatMsgPack))
+	tests.Run(b, slogSuite)
+}
@@ -0,0 +1,33 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/madkins23/go-slog/bench/tests"
+	"github.com/madkins23/go-slog/creator/madkinsflashdedup"
+	"github.com/madkins23/go-slog/handlers/flash"
+)
+
+// BenchmarkMadkinsFlashDedupAppend runs benchmarks for the madkins/flash handler in DedupAppend mode.
+func BenchmarkMadkinsFlashDedupAppend(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashdedup.Creator(flash.DedupAppend))
+	tests.Run(b, slogSuite)
+}
+
+// BenchmarkMadkinsFlashDedupIgnore runs benchmarks for the madkins/flash handler in DedupIgnore mode.
+func BenchmarkMadkinsFlashDedupIgnore(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashdedup.Creator(flash.DedupIgnore))
+	tests.Run(b, slogSuite)
+}
+
+// BenchmarkMadkinsFlashDedupIncrement runs benchmarks for the madkins/flash handler in DedupIncrement mode.
+func BenchmarkMadkinsFlashDedupIncrement(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashdedup.Creator(flash.DedupIncrement))
+	tests.Run(b, slogSuite)
+}
+
+// BenchmarkMadkinsFlashDedupOverwrite runs benchmarks for the madkins/flash handler in DedupOverwrite mode.
+func BenchmarkMadkinsFlashDedupOverwrite(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashdedup.Creator(flash.DedupOverwrite))
+	tests.Run(b, slogSuite)
+}
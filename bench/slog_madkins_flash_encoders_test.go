@@ -0,0 +1,14 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/madkins23/go-slog/bench/tests"
+	"github.com/madkins23/go-slog/creator/madkinsflashencoders"
+)
+
+// BenchmarkMadkinsFlashEncoders runs benchmarks for the madkins/flash handler with typed encoders.
+func BenchmarkMadkinsFlashEncoders(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashencoders.Creator())
+	tests.Run(b, slogSuite)
+}
@@ -0,0 +1,30 @@
+package bench
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/madkins23/go-slog/bench/tests"
+	"github.com/madkins23/go-slog/creator/madkinsflashfile"
+	"github.com/madkins23/go-slog/writer"
+)
+
+// BenchmarkMadkinsFlashFile runs benchmarks for the madkins/flash JSON handler
+// writing to a rotating file.
+func BenchmarkMadkinsFlashFile(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinsflashfile.Creator())
+	slogSuite.SetWriterFn(func(b *testing.B) (io.Writer, func()) {
+		rotator, err := writer.NewRotator(filepath.Join(b.TempDir(), "bench.log"),
+			&writer.RotateOptions{MaxSize: 64 << 20, MaxBackups: 2})
+		if err != nil {
+			b.Fatalf("Create rotator: %s", err)
+		}
+		return rotator, func() {
+			if err := rotator.Close(); err != nil {
+				b.Errorf("Close rotator: %s", err)
+			}
+		}
+	})
+	tests.Run(b, slogSuite)
+}
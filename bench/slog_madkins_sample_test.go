@@ -0,0 +1,14 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/madkins23/go-slog/bench/tests"
+	"github.com/madkins23/go-slog/creator/madkinssample"
+)
+
+// BenchmarkMadkinsSample runs benchmarks for the madkins/sample handler wrapping madkins/flash.
+func BenchmarkMadkinsSample(b *testing.B) {
+	slogSuite := tests.NewSlogBenchmarkSuite(madkinssample.Creator())
+	tests.Run(b, slogSuite)
+}
@@ -133,6 +133,22 @@ func (suite *SlogBenchmarkSuite) BenchmarkKeyValues() *Benchmark {
 	}
 }
 
+// BenchmarkTypedValues logs a message with attributes of types that are often logged
+// as slog.KindAny values: net.IP, *url.URL, a UUID-like [16]byte, an error chain, and []string.
+func (suite *SlogBenchmarkSuite) BenchmarkTypedValues() *Benchmark {
+	return &Benchmark{
+		Options: infra.SimpleOptions(),
+		BenchmarkFn: func(logger *slog.Logger) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, message, typedAttributes...)
+		},
+		VerifyFn: verify(
+			finder("TypedValues", expectedBasic()),
+			fields("TypedValues", "IP", "URL", "UUID", "Chain", "Strings"),
+			noDuplicates("TypedValues"),
+		),
+	}
+}
+
 // -----------------------------------------------------------------------------
 
 // BenchmarkWithAttrsSimple logs a simple message to a logger created from
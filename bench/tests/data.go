@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"math"
 	"math/rand"
+	"net"
+	"net/url"
 	"strings"
 	"time"
 
@@ -134,6 +136,25 @@ func withValuesMap() map[string]any {
 
 // -----------------------------------------------------------------------------
 
+// Typed values that are often logged as slog.KindAny values.
+var (
+	valIP      = net.IPv4(192, 168, 17, 23)
+	valURL     = &url.URL{Scheme: "https", Host: "example.com", Path: "/api/v1/things", RawQuery: "id=17"}
+	valUUID    = [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	valChain   = fmt.Errorf("request failed: %w", fmt.Errorf("connect: %w", valError))
+	valStrings = []string{"alpha", "beta", "gamma", "delta"}
+)
+
+var typedAttributes = []slog.Attr{
+	slog.Any("IP", valIP),
+	slog.Any("URL", valURL),
+	slog.Any("UUID", valUUID),
+	slog.Any("Chain", valChain),
+	slog.Any("Strings", valStrings),
+}
+
+// -----------------------------------------------------------------------------
+
 var bigGroup slog.Attr
 
 // BigGroup returns a nested group structure as an attribute.
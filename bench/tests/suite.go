@@ -26,10 +26,15 @@ type SlogBenchmarkSuite struct {
 	infra.Creator
 	*warning.Manager
 
-	b  *testing.B
-	mu sync.RWMutex
+	b        *testing.B
+	mu       sync.RWMutex
+	writerFn WriterFn
 }
 
+// WriterFn returns an io.Writer for benchmark output along with a function to close it.
+// This is called once for each benchmark run.
+type WriterFn func(b *testing.B) (io.Writer, func())
+
 // NewSlogBenchmarkSuite creates a new benchmark test suite for the specified Creator.
 // The handler string must match the suffix of the name of the enclosing test.
 // This is:
@@ -59,6 +64,14 @@ func (suite *SlogBenchmarkSuite) SetB(b *testing.B) {
 	suite.b = b
 }
 
+// SetWriterFn configures the suite to write benchmark output to the io.Writer returned by
+// the specified function instead of throwing it away.
+// This makes the cost of the output path (e.g. writing to disk) part of the results.
+// Output is still counted to check the number of log records written.
+func (suite *SlogBenchmarkSuite) SetWriterFn(fn WriterFn) {
+	suite.writerFn = fn
+}
+
 // logger for testing with handler tweaks if HandlerFn is specified in the benchmark.
 func (suite *SlogBenchmarkSuite) logger(b *Benchmark, w io.Writer) *slog.Logger {
 	if b.HandlerFn != nil {
@@ -90,7 +103,7 @@ func Run(b *testing.B, suite *SlogBenchmarkSuite) {
 	handler := data.HandlerTag(strings.TrimPrefix(functionName, benchmarkMethodPrefix))
 	fmt.Printf("# Handler[%s]=\"%s\"\n", handler, suite.Creator.Name())
 
-	stdoutLogger := suite.NewLogger(os.Stdout, infra.SimpleOptions())
+	stdoutLogger := suite.NewLogger(suite.DecodeWriter(os.Stdout), infra.SimpleOptions())
 	suite.SetB(b)
 	suiteType := reflect.TypeOf(suite)
 	// For each method name...
@@ -134,8 +147,12 @@ func Run(b *testing.B, suite *SlogBenchmarkSuite) {
 
 			// If the `Benchmark` has a verify function to test the log output:
 			if benchmark.VerifyFn != nil {
-				// Verify the output with the function.
-				if err := benchmark.VerifyFn(buffer.Bytes(), nil, suite.Manager); err != nil {
+				// Output that isn't JSON is decoded for verification only.
+				captured, err := suite.Decode(buffer.Bytes())
+				if err != nil {
+					suite.AddWarning(warning.TestError, err.Error(), buffer.String())
+				} else if err = benchmark.VerifyFn(captured, nil, suite.Manager); err != nil {
+					// Verify the output with the function.
 					slog.Warn("Verification Error", "err", err)
 				}
 			}
@@ -148,7 +165,8 @@ func Run(b *testing.B, suite *SlogBenchmarkSuite) {
 			// TODO: If I could call the following I could haz results now?
 			//       testing.Benchmark(func(b *testing.B) {
 			b.Run(method.Name, func(b *testing.B) {
-				var count test.CountWriter
+				// Output that isn't JSON has no line endings so every write is counted.
+				count := test.CountWriter{EveryWrite: suite.HasDecodeFn()}
 				function := benchmark.BenchmarkFn
 				// Capture warnings from a single run.
 				if test.DebugLevel() > 0 {
@@ -159,7 +177,13 @@ func Run(b *testing.B, suite *SlogBenchmarkSuite) {
 				// NOTE: the creation of the logger,
 				//       which may involve Handler.WithAttrs() and/or Handler.WithGroup(),
 				//       is NOT counted towards results.
-				logger := suite.logger(benchmark, &count)
+				var writer io.Writer = &count
+				if suite.writerFn != nil {
+					w, closeFn := suite.writerFn(b)
+					defer closeFn()
+					writer = io.MultiWriter(w, &count)
+				}
+				logger := suite.logger(benchmark, writer)
 				// Now move on to the actual test.
 				b.ReportAllocs()
 				b.SetBytes(bytesPerOp)
@@ -0,0 +1,31 @@
+package madkinsctxattr
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/ctxattr"
+
+// Creator returns a Creator object for the [madkins/ctxattr] handler wrapping slog.JSONHandler.
+// The madkins/flash and madkins/sloggy handlers support context attributes natively,
+// so the wrapper is tested around a handler that doesn't.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/ctxattr^ is a wrapper around the
+		[^slog/JSONHandler^ handler](/go-slog/handler/SlogJSON.html)
+		that adds attributes attached to the context to each log record.
+		The verification and benchmark suites don't attach context attributes,
+		so this shows that the wrapper doesn't change handler behavior otherwise.`,
+		map[string]string{
+			"madkins/ctxattr":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/ctxattr",
+			"slog/JSONHandler": "https://pkg.go.dev/log/slog#JSONHandler",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return ctxattr.NewHandler(slog.NewJSONHandler(w, options), nil)
+}
@@ -0,0 +1,37 @@
+package madkinsfanout
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/fanout"
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/handlers/sloggy"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/fanout"
+
+// Creator returns a Creator object for the [madkins/fanout] handler.
+// The first branch is a madkins/flash handler writing to the test output.
+// A second branch with a madkins/sloggy handler writes to io.Discard
+// so that records, attributes, and groups must survive being sent to multiple handlers.
+// The fanout package tests check that the output of both branches is the same.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/fanout^ sends each log record to multiple handlers.
+		This test configuration sends records to the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		as well as a [^madkins/sloggy^ handler](/go-slog/handler/MadkinsSloggy.html)
+		with output discarded.`,
+		map[string]string{
+			"madkins/fanout": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/fanout",
+			"madkins/flash":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"madkins/sloggy": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/sloggy",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return fanout.NewHandler(nil,
+		fanout.Branch{Handler: flash.NewHandler(w, options, nil)},
+		fanout.Branch{Handler: sloggy.NewHandler(io.Discard, options)})
+}
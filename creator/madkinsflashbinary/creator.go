@@ -0,0 +1,51 @@
+package madkinsflashbinary
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/internal/bincode"
+)
+
+const BaseName = "madkins/flash-binary"
+
+// Name returns the creator name for the specified binary format.
+func Name(format flash.Format) string {
+	return BaseName + "/" + strings.TrimPrefix(format.String(), "Format")
+}
+
+// Creator returns a Creator object for the [madkins/flash] handler
+// configured for the specified binary (FormatCBOR or FormatMsgPack) output.
+// The binary output is converted into JSON only when it is verified.
+func Creator(format flash.Format) infra.Creator {
+	creator := infra.NewCreator(Name(format), handlerFn(format), nil,
+		`^madkins/flash-binary^ is the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		configured via ^flash.Extras^ to generate compact binary (CBOR or MessagePack) records.
+		Benchmarks measure the binary records as written,
+		which are only converted into JSON to verify the output.`,
+		map[string]string{
+			"madkins/flash": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"flash.Extras":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Extras",
+		})
+	creator.SetDecodeFn(decodeFn(format))
+	return creator
+}
+
+func handlerFn(format flash.Format) infra.CreateHandlerFn {
+	return func(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+		return flash.NewHandler(w, options, &flash.Extras{Format: format})
+	}
+}
+
+func decodeFn(format flash.Format) infra.DecodeFn {
+	decode := bincode.CBOR
+	if format == flash.FormatMsgPack {
+		decode = bincode.MsgPack
+	}
+	return func(data []byte) ([]byte, error) {
+		return bincode.Lines(decode, data)
+	}
+}
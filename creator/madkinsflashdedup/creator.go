@@ -0,0 +1,37 @@
+package madkinsflashdedup
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const BaseName = "madkins/flash-dedup"
+
+// Name returns the creator name for the specified deduplication mode.
+func Name(mode flash.DedupMode) string {
+	return BaseName + "/" + strings.TrimPrefix(mode.String(), "Dedup")
+}
+
+// Creator returns a Creator object for the [madkins/flash] handler
+// configured to deduplicate keys using the specified mode.
+func Creator(mode flash.DedupMode) infra.Creator {
+	return infra.NewCreator(Name(mode), handlerFn(mode), nil,
+		`^madkins/flash-dedup^ is the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		configured via ^flash.Extras^ to deduplicate keys natively:
+		overwriting, ignoring, appending, or incrementing as with ^veqryn/dedup^.`,
+		map[string]string{
+			"madkins/flash":   "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"flash.DedupMode": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#DedupMode",
+			"veqryn/dedup":    "https://github.com/veqryn/slog-dedup",
+		})
+}
+
+func handlerFn(mode flash.DedupMode) infra.CreateHandlerFn {
+	return func(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+		return flash.NewHandler(w, options, &flash.Extras{Dedup: mode})
+	}
+}
@@ -0,0 +1,38 @@
+package madkinsflashencoders
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/flash-encoders"
+
+// Creator returns a Creator object for the [madkins/flash] handler
+// configured with the built-in typed encoders from flash.NewEncoders plus flash.AppendUUID.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/flash-encoders^ is the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		configured via ^flash.Extras^ with a registry of typed encoders
+		for common ^slog.KindAny^ values (IP addresses, URLs, errors, string slices, and UUIDs).
+		Comparison of the TypedValues benchmark with ^madkins/flash^
+		shows the gain over the ^json.Marshal^ fallback.`,
+		map[string]string{
+			"madkins/flash":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"flash.Extras":   "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Extras",
+			"flash.Encoders": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Encoders",
+		})
+}
+
+// encoders is shared by all handlers as the registry is safe for concurrent use.
+var encoders = func() *flash.Encoders {
+	encoders := flash.NewEncoders()
+	flash.RegisterEncoder(encoders, flash.AppendUUID)
+	return encoders
+}()
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return flash.NewHandler(w, options, &flash.Extras{Encoders: encoders})
+}
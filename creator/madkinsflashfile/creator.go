@@ -0,0 +1,33 @@
+package madkinsflashfile
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/flash-file"
+
+// Creator returns a Creator object for the [madkins/flash] handler
+// for benchmarks that write output to a file via writer.Rotator.
+// The handler itself is configured exactly like madkins/flash,
+// the file is provided by the benchmark via SlogBenchmarkSuite.SetWriterFn.
+// It is only used for benchmarks as verification would duplicate that of madkins/flash.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/flash-file^ is the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		benchmarked while writing to a file via a rotating file ^writer.Rotator^
+		instead of throwing output away.
+		Comparison with ^madkins/flash^ shows the cost of the disk path.
+		There are no verification results as the handler is the same as ^madkins/flash^.`,
+		map[string]string{
+			"madkins/flash":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"writer.Rotator": "https://pkg.go.dev/github.com/madkins23/go-slog/writer#Rotator",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return flash.NewHandler(w, options, nil)
+}
@@ -0,0 +1,30 @@
+package madkinsflashtext
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/internal/logfmt"
+)
+
+const Name = "madkins/flash-text"
+
+// Creator returns a Creator object for the [madkins/flash] handler configured for text output.
+// The text (logfmt) output is converted back into JSON so that it can be verified.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/flash-text^ is the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		configured via ^flash.Extras^ to generate human-readable ^key=value^ (logfmt) text.
+		The text is converted back into JSON for testing,
+		so benchmark results would include the conversion and are not provided.`,
+		map[string]string{
+			"madkins/flash": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+			"flash.Extras":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Extras",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return flash.NewHandler(logfmt.NewWriter(w), options, &flash.Extras{Format: flash.FormatText})
+}
@@ -0,0 +1,30 @@
+package madkinsoteltrace
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/handlers/oteltrace"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/oteltrace"
+
+// Creator returns a Creator object for the [madkins/oteltrace] handler wrapping the madkins/flash handler.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/oteltrace^ is a wrapper around the
+		[^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html)
+		that adds OpenTelemetry trace and span IDs to log records.
+		The verification and benchmark suites don't log with a span in the context,
+		so this shows that the wrapper doesn't change handler behavior otherwise.`,
+		map[string]string{
+			"madkins/oteltrace": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/oteltrace",
+			"madkins/flash":     "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return oteltrace.NewHandler(flash.NewHandler(w, options, nil), &oteltrace.Options{NativeContext: true})
+}
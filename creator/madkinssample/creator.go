@@ -0,0 +1,43 @@
+package madkinssample
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/handlers/sample"
+	"github.com/madkins23/go-slog/infra"
+)
+
+const Name = "madkins/sample"
+
+// Creator returns a Creator object for the [madkins/sample] handler wrapping the madkins/flash handler.
+// The sampling policy is configured so that nothing is actually dropped:
+// the benchmark suite requires every record to be written and
+// the point is to measure the overhead of the sampling bookkeeping.
+func Creator() infra.Creator {
+	return infra.NewCreator(Name, handlerFn, nil,
+		`^madkins/sample^ is a sampling and rate-limiting wrapper
+		around the [^madkins/flash^ handler](/go-slog/handler/MadkinsFlash.html).
+		It is configured with counting and token bucket policies
+		that are generous enough that no records are dropped,
+		so the difference from ^madkins/flash^ is the cost of the wrapper.`,
+		map[string]string{
+			"madkins/sample": "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/sample",
+			"madkins/flash":  "https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash",
+		})
+}
+
+func handlerFn(w io.Writer, options *slog.HandlerOptions) slog.Handler {
+	return sample.NewHandler(flash.NewHandler(w, options, nil), &sample.Options{
+		Default: sample.Policy{
+			Window:     time.Second,
+			First:      math.MaxUint32,
+			Thereafter: 1,
+			Rate:       math.MaxUint32,
+			Burst:      math.MaxUint32,
+		},
+	})
+}
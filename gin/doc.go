@@ -14,6 +14,16 @@
 // These objects will parse the Gin-internal logging formats and
 // use log/slog to do the actual logging, so the log output will all look the same.
 //
+// Log records are assembled from lines regardless of how they are split across Write calls.
+// Multi-line records such as Gin warnings are logged as a single record and
+// panic recovery records from gin.Recovery are logged as a single record
+// with the panic value, request dump (in debug mode), and stack trace as attributes:
+//
+//	panic="test panic" request="GET /panic HTTP/1.1" stack="/app/main.go:23 (0x7a3e2f)\n\thandler: panic(\"test panic\")\n..."
+//
+// The io.Writer objects provided by NewWriter also implement io.Closer.
+// Call Close to log any remaining partial record before the program exits.
+//
 // The io.Writer objects provided by NewWriter can further parse the "standard" Gin traffic lines containing
 // messages of the following format:
 //
@@ -28,5 +38,31 @@
 //
 //	code=200 elapsed=5.529751605s client=::1 method=GET url=/chart.svg?tag=With_Attrs_Attributes&item=MemBytes
 //
+// The "[GIN-debug]" records can also be parsed by setting Options.Debug.Parse to true.
+// Route registration records such as:
+//
+//	[GIN-debug] GET    /chart.svg                --> main.chartFunction (3 handlers)
+//
+// will result in the following embedded group:
+//
+//	msg="Gin Route" gin.method=GET gin.path=/chart.svg gin.handler=main.chartFunction gin.handler_count=3
+//
+// Listening records return the protocol and address,
+// "Running in ... mode" warnings return the mode, all "[WARNING]" records return
+// the warning field set to true, and the continuation lines
+// of multi-line warnings are returned as the detail field.
+// See gin.ParseRoute and gin.ParseDebug for details.
+//
 // Further options can be found in the code documentation for gin.Options.
+//
+// Alternatively, gin.Middleware returns request logging middleware that
+// logs each request directly to a slog.Logger instead of gin.Logger():
+//
+//	router := gin.New()
+//	router.Use(ginslog.Middleware(&ginslog.MiddlewareOptions{SkipPaths: []string{"/health"}}), gin.Recovery())
+//
+// This avoids formatting and then parsing a traffic line for each request
+// and logs additional fields (path, query, route, size, request_id, errors)
+// with the elapsed time as a duration and the log level chosen by HTTP status code.
+// Further options can be found in the code documentation for gin.MiddlewareOptions.
 package gin
@@ -0,0 +1,186 @@
+package gin
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultRequestIDHeader is the default header from which the request ID is taken.
+	DefaultRequestIDHeader = "X-Request-ID"
+
+	// DefaultRequestMessage is the default message for request log records.
+	DefaultRequestMessage = "Gin Request"
+)
+
+// Additional Field values logged by Middleware.
+// The Code, Elapsed, Client, and Method fields are shared with Gin traffic parsing.
+const (
+	Path      Field = "path"
+	Query     Field = "query"
+	Route     Field = "route"
+	Size      Field = "size"
+	RequestID Field = "request_id"
+	Errors    Field = "errors"
+)
+
+// ----------------------------------------------------------------------------
+
+// MiddlewareOptions for Middleware.
+type MiddlewareOptions struct {
+	// Logger is used to log requests.
+	// If not set slog.Default() is used at the time each request is logged.
+	Logger *slog.Logger
+
+	// Message to be used for each request log record.
+	// When no message is provided the default value of DefaultRequestMessage (= "Gin Request") is used.
+	Message string
+
+	// Level returns the log level for a request given the HTTP status code.
+	// If not set the DefaultLevel function is used.
+	Level func(status int) slog.Level
+
+	// SkipPaths lists request paths that are not logged (e.g. health checks).
+	// Paths are matched exactly against the request URL path.
+	SkipPaths []string
+
+	// Skip returns true for requests that are not logged.
+	// It is called after the request has been handled so the response status is available.
+	Skip func(c *gin.Context) bool
+
+	// RequestIDHeader names the header containing the request ID.
+	// The request headers are checked first, then the response headers
+	// in case the ID was generated by another middleware.
+	// When no header name is provided the value of DefaultRequestIDHeader (= "X-Request-ID") is used.
+	RequestIDHeader string
+
+	// Embed request data at the top level of the log record when true.
+	Embed bool
+
+	// Group provides a group name under which request data will be gathered.
+	// When no group name is provided the value of DefaultTrafficGroup (= "gin") is used.
+	// Only used if Embed is false.
+	Group string
+}
+
+// DefaultLevel returns slog.LevelError for server errors (5xx),
+// slog.LevelWarn for client errors (4xx), and slog.LevelInfo otherwise.
+func DefaultLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// ----------------------------------------------------------------------------
+
+// Middleware returns a gin.HandlerFunc that logs each request directly to a slog.Logger.
+// This is an alternative to gin.Logger() (optionally redirected via NewWriter)
+// that avoids formatting and then parsing a traffic line for each request.
+// Use it in place of gin.Logger() (i.e. with gin.New() rather than gin.Default()):
+//
+//	router := gin.New()
+//	router.Use(ginslog.Middleware(nil), gin.Recovery())
+//
+// Each request is logged with the fields:
+//
+//	code, elapsed, client, method, path, query, route, size, request_id, errors
+//
+// where elapsed is a time.Duration, route is the route template (e.g. "/user/:id"),
+// size is the number of bytes in the response body, and errors is a list of strings from gin.Context.Errors.
+// The query, route, request_id, and errors fields are only logged if not empty.
+//
+// The options argument may be nil in which case default values are used.
+func Middleware(options *MiddlewareOptions) gin.HandlerFunc {
+	m := &middleware{}
+	if options != nil {
+		m.MiddlewareOptions = *options
+	}
+	if m.Message == "" {
+		m.Message = DefaultRequestMessage
+	}
+	if m.Level == nil {
+		m.Level = DefaultLevel
+	}
+	if m.RequestIDHeader == "" {
+		m.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if m.Group == "" && !m.Embed {
+		m.Group = DefaultTrafficGroup
+	}
+	if len(m.SkipPaths) > 0 {
+		m.skipPaths = make(map[string]bool, len(m.SkipPaths))
+		for _, path := range m.SkipPaths {
+			m.skipPaths[path] = true
+		}
+	}
+	return m.handle
+}
+
+// ----------------------------------------------------------------------------
+
+// middleware holds the configuration for the function returned by Middleware.
+type middleware struct {
+	MiddlewareOptions
+	skipPaths map[string]bool
+}
+
+// handle a single request, logging it after the rest of the chain is done.
+func (m *middleware) handle(c *gin.Context) {
+	start := time.Now()
+	// Capture path and query before the handlers have a chance to change them.
+	path := c.Request.URL.Path
+	query := c.Request.URL.RawQuery
+
+	c.Next()
+
+	if m.skipPaths[path] || (m.Skip != nil && m.Skip(c)) {
+		return
+	}
+	logger := m.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ctx := c.Request.Context()
+	status := c.Writer.Status()
+	level := m.Level(status)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 10)
+	attrs = append(attrs,
+		slog.Int(string(Code), status),
+		slog.Duration(string(Elapsed), time.Since(start)),
+		slog.String(string(Client), c.ClientIP()),
+		slog.String(string(Method), c.Request.Method),
+		slog.String(string(Path), path))
+	if query != "" {
+		attrs = append(attrs, slog.String(string(Query), query))
+	}
+	if route := c.FullPath(); route != "" {
+		attrs = append(attrs, slog.String(string(Route), route))
+	}
+	// Size is -1 if nothing was written.
+	attrs = append(attrs, slog.Int(string(Size), max(c.Writer.Size(), 0)))
+	requestID := c.GetHeader(m.RequestIDHeader)
+	if requestID == "" {
+		requestID = c.Writer.Header().Get(m.RequestIDHeader)
+	}
+	if requestID != "" {
+		attrs = append(attrs, slog.String(string(RequestID), requestID))
+	}
+	if len(c.Errors) > 0 {
+		attrs = append(attrs, slog.Any(string(Errors), c.Errors.Errors()))
+	}
+	if !m.Embed {
+		attrs = []slog.Attr{{Key: m.Group, Value: slog.GroupValue(attrs...)}}
+	}
+	logger.LogAttrs(ctx, level, m.Message, attrs...)
+}
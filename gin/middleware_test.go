@@ -0,0 +1,139 @@
+package gin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRouter returns a gin.Engine using Middleware with the specified options
+// and a buffer containing the JSON log output.
+func testRouter(t *testing.T, options *MiddlewareOptions) (*gin.Engine, *bytes.Buffer) {
+	mode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() { gin.SetMode(mode) })
+	buffer := &bytes.Buffer{}
+	if options == nil {
+		options = &MiddlewareOptions{}
+	}
+	options.Logger = slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	router := gin.New()
+	router.Use(Middleware(options))
+	router.GET("/user/:id", func(c *gin.Context) {
+		c.Header(DefaultRequestIDHeader, "generated-id")
+		c.String(http.StatusOK, "user "+c.Param("id"))
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("first"))
+		_ = c.Error(errors.New("second"))
+		c.Status(http.StatusInternalServerError)
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router, buffer
+}
+
+// serve a request and return the log record as a map, or nil if there was no log output.
+func serve(t *testing.T, router *gin.Engine, buffer *bytes.Buffer, request *http.Request) map[string]any {
+	buffer.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	if buffer.Len() < 1 {
+		return nil
+	}
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	return record
+}
+
+func TestMiddleware(t *testing.T) {
+	router, buffer := testRouter(t, nil)
+	request := httptest.NewRequest(http.MethodGet, "/user/17?verbose=true", nil)
+	request.RemoteAddr = "10.1.2.3:4567"
+	record := serve(t, router, buffer, request)
+	require.NotNil(t, record)
+	assert.Equal(t, "INFO", record[slog.LevelKey])
+	assert.Equal(t, DefaultRequestMessage, record[slog.MessageKey])
+	group, ok := record[DefaultTrafficGroup].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(http.StatusOK), group[string(Code)])
+	assert.IsType(t, float64(0), group[string(Elapsed)])
+	assert.Equal(t, "10.1.2.3", group[string(Client)])
+	assert.Equal(t, "GET", group[string(Method)])
+	assert.Equal(t, "/user/17", group[string(Path)])
+	assert.Equal(t, "verbose=true", group[string(Query)])
+	assert.Equal(t, "/user/:id", group[string(Route)])
+	assert.Equal(t, float64(len("user 17")), group[string(Size)])
+	assert.Equal(t, "generated-id", group[string(RequestID)])
+	assert.NotContains(t, group, string(Errors))
+}
+
+func TestMiddleware_Errors(t *testing.T) {
+	router, buffer := testRouter(t, &MiddlewareOptions{Embed: true, Message: "request"})
+	request := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	request.Header.Set(DefaultRequestIDHeader, "request-id")
+	record := serve(t, router, buffer, request)
+	require.NotNil(t, record)
+	assert.Equal(t, "ERROR", record[slog.LevelKey])
+	assert.Equal(t, "request", record[slog.MessageKey])
+	assert.Equal(t, float64(http.StatusInternalServerError), record[string(Code)])
+	assert.Equal(t, "request-id", record[string(RequestID)])
+	assert.Equal(t, float64(0), record[string(Size)])
+	assert.Equal(t, []any{"first", "second"}, record[string(Errors)])
+	assert.NotContains(t, record, string(Query))
+}
+
+func TestMiddleware_NotFound(t *testing.T) {
+	router, buffer := testRouter(t, &MiddlewareOptions{Group: "http"})
+	record := serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	require.NotNil(t, record)
+	assert.Equal(t, "WARN", record[slog.LevelKey])
+	group, ok := record["http"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(http.StatusNotFound), group[string(Code)])
+	assert.NotContains(t, group, string(Route))
+}
+
+func TestMiddleware_Level(t *testing.T) {
+	router, buffer := testRouter(t, &MiddlewareOptions{
+		Level: func(status int) slog.Level {
+			if status == http.StatusNoContent {
+				return slog.LevelDebug
+			}
+			return slog.LevelInfo
+		},
+	})
+	record := serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.NotNil(t, record)
+	assert.Equal(t, "DEBUG", record[slog.LevelKey])
+	record = serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.NotNil(t, record)
+	assert.Equal(t, "INFO", record[slog.LevelKey])
+}
+
+func TestMiddleware_Skip(t *testing.T) {
+	router, buffer := testRouter(t, &MiddlewareOptions{
+		SkipPaths: []string{"/health"},
+		Skip: func(c *gin.Context) bool {
+			return c.Writer.Status() == http.StatusNotFound
+		},
+	})
+	assert.Nil(t, serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/health", nil)))
+	assert.Nil(t, serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/missing", nil)))
+	assert.NotNil(t, serve(t, router, buffer, httptest.NewRequest(http.MethodGet, "/user/1", nil)))
+}
+
+func TestDefaultLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, DefaultLevel(http.StatusOK))
+	assert.Equal(t, slog.LevelInfo, DefaultLevel(http.StatusFound))
+	assert.Equal(t, slog.LevelWarn, DefaultLevel(http.StatusBadRequest))
+	assert.Equal(t, slog.LevelError, DefaultLevel(http.StatusServiceUnavailable))
+}
@@ -19,9 +19,27 @@ const (
 	Url     Field = "url"
 )
 
+// Field values that may be parsed from a Gin debug record.
+// Route records also use the Method and Path fields.
+const (
+	Handler      Field = "handler"
+	HandlerCount Field = "handler_count"
+	Protocol     Field = "protocol"
+	Address      Field = "address"
+	Mode         Field = "mode"
+	Detail       Field = "detail"
+	Warning      Field = "warning"
+)
+
 var (
 	ptnCode  = regexp.MustCompile(`^\s*(\d+)\s*$`)
 	ptnSplit = regexp.MustCompile(`\s+`)
+
+	ptnRoute     = regexp.MustCompile(`^(\S+)\s+(\S+)\s+-->\s+(\S+)\s+\((\d+) handlers\)$`)
+	ptnListening = regexp.MustCompile(`^(Listening and serving (HTTPS?)) on (?:listener what's bind with address@)?(.+)$`)
+	ptnPort      = regexp.MustCompile(`^Environment variable PORT(?:="(.*)"| is undefined\. Using port (\S+) by default)$`)
+	ptnMode      = regexp.MustCompile(`^Running in "([^"]+)" mode`)
+	ptnWarning   = regexp.MustCompile(`^\[WARNING]\s*`)
 )
 
 // Parse a Gin traffic record (specified as message) to return an array of slog.Attr items.
@@ -57,3 +75,78 @@ func Parse(message string) ([]any, error) {
 	}
 	return result, nil
 }
+
+// ParseRoute parses a Gin debug route registration record (specified as message)
+// to return an array of slog.Attr items for the Method, Path, Handler, and HandlerCount fields.
+func ParseRoute(message string) ([]any, error) {
+	// Example line:
+	//  GET    /chart.svg                --> main.chartFunction (3 handlers)
+	matches := ptnRoute.FindStringSubmatch(message)
+	if len(matches) != 5 {
+		return nil, fmt.Errorf("not a route: '%s'", message)
+	}
+	count, err := strconv.ParseInt(matches[4], 10, 64)
+	if err != nil {
+		// This should never happen so no test code coverage here.
+		return nil, fmt.Errorf("parse int from '%s': %w", matches[4], err)
+	}
+	return []any{
+		slog.String(string(Method), matches[1]),
+		slog.String(string(Path), matches[2]),
+		slog.String(string(Handler), matches[3]),
+		slog.Int64(string(HandlerCount), count),
+	}, nil
+}
+
+// ParseDebug parses other Gin debug records (specified as message, without the [GIN-debug] prefix)
+// to return a replacement message and an array of slog.Attr items.
+// Records have the following forms:
+//
+//	Listening and serving HTTP on :8080
+//	Environment variable PORT is undefined. Using port :8080 by default
+//	[WARNING] Running in "debug" mode. Switch to "release" mode in production.
+//	 - using env:	export GIN_MODE=release
+//	 - using code:	gin.SetMode(gin.ReleaseMode)
+//	[WARNING] Creating an Engine instance with the Logger and Recovery middleware already attached.
+//
+// Listening and port records have the address removed from the message
+// and returned in the Address field (as well as the Protocol field for listening records).
+// Running records return the Mode field.
+// Records with a [WARNING] prefix have it removed from the message and return the Warning field set to true.
+// For any multi-line record the message is the first line and the remaining lines
+// are returned in the Detail field.
+// An error is returned for a single line record that is not one of these forms.
+func ParseDebug(message string) (string, []any, error) {
+	var result []any
+	warning := false
+	if match := ptnWarning.FindString(message); match != "" {
+		message = message[len(match):]
+		warning = true
+	}
+	message, detail, multiLine := strings.Cut(message, "\n")
+	if matches := ptnListening.FindStringSubmatch(message); len(matches) == 4 {
+		message = matches[1]
+		result = append(result,
+			slog.String(string(Protocol), matches[2]),
+			slog.String(string(Address), matches[3]))
+	} else if matches := ptnPort.FindStringSubmatch(message); len(matches) == 3 {
+		// Gin uses the PORT value with a colon prefix or :8080 if it is undefined.
+		message = "Environment variable PORT"
+		address := matches[2]
+		if address == "" {
+			address = ":" + matches[1]
+		}
+		result = append(result, slog.String(string(Address), address))
+	} else if matches := ptnMode.FindStringSubmatch(message); len(matches) == 2 {
+		result = append(result, slog.String(string(Mode), matches[1]))
+	} else if !multiLine && !warning {
+		return message, nil, fmt.Errorf("unknown debug record: '%s'", message)
+	}
+	if warning {
+		result = append(result, slog.Bool(string(Warning), true))
+	}
+	if detail = strings.TrimRight(detail, "\n"); detail != "" {
+		result = append(result, slog.String(string(Detail), detail))
+	}
+	return message, result, nil
+}
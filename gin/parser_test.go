@@ -44,3 +44,89 @@ func TestParse_Error_CodeNotNum(t *testing.T) {
 	assert.ErrorContains(t, err, "2XX")
 	assert.Nil(t, args)
 }
+
+func TestParseRoute(t *testing.T) {
+	args, err := ParseRoute(`GET    /go-slog/chart/:tag/:item --> main.barChart (3 handlers)`)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{
+		slog.String(string(Method), "GET"),
+		slog.String(string(Path), "/go-slog/chart/:tag/:item"),
+		slog.String(string(Handler), "main.barChart"),
+		slog.Int64(string(HandlerCount), 3),
+	}, args)
+}
+
+func TestParseRoute_Error(t *testing.T) {
+	args, err := ParseRoute(`Listening and serving HTTP on :8080`)
+	assert.ErrorContains(t, err, "not a route")
+	assert.Nil(t, args)
+}
+
+func TestParseDebug(t *testing.T) {
+	for _, test := range []struct {
+		message string
+		result  string
+		args    []any
+	}{
+		{
+			message: "Listening and serving HTTP on :8080",
+			result:  "Listening and serving HTTP",
+			args:    []any{slog.String(string(Protocol), "HTTP"), slog.String(string(Address), ":8080")},
+		}, {
+			message: "Listening and serving HTTP on unix:/tmp/gin.sock",
+			result:  "Listening and serving HTTP",
+			args:    []any{slog.String(string(Protocol), "HTTP"), slog.String(string(Address), "unix:/tmp/gin.sock")},
+		}, {
+			message: "Listening and serving HTTP on listener what's bind with address@127.0.0.1:8080",
+			result:  "Listening and serving HTTP",
+			args:    []any{slog.String(string(Protocol), "HTTP"), slog.String(string(Address), "127.0.0.1:8080")},
+		}, {
+			message: "Environment variable PORT is undefined. Using port :8080 by default",
+			result:  "Environment variable PORT",
+			args:    []any{slog.String(string(Address), ":8080")},
+		}, {
+			message: `Environment variable PORT="9090"`,
+			result:  "Environment variable PORT",
+			args:    []any{slog.String(string(Address), ":9090")},
+		}, {
+			message: "Running in \"release\" mode.\n - more\n\n",
+			result:  `Running in "release" mode.`,
+			args:    []any{slog.String(string(Mode), "release"), slog.String(string(Detail), " - more")},
+		}, {
+			message: "[WARNING] Running in \"debug\" mode. Switch to \"release\" mode in production.\n" +
+				" - using env:\texport GIN_MODE=release\n - using code:\tgin.SetMode(gin.ReleaseMode)\n\n",
+			result: `Running in "debug" mode. Switch to "release" mode in production.`,
+			args: []any{
+				slog.String(string(Mode), "debug"),
+				slog.Bool(string(Warning), true),
+				slog.String(string(Detail), " - using env:\texport GIN_MODE=release\n - using code:\tgin.SetMode(gin.ReleaseMode)"),
+			},
+		}, {
+			message: "[WARNING] Creating an Engine instance with the Logger and Recovery middleware already attached.\n",
+			result:  "Creating an Engine instance with the Logger and Recovery middleware already attached.",
+			args:    []any{slog.Bool(string(Warning), true)},
+		}, {
+			message: "[WARNING] Headers were already written. Wanted to override status code 200 with 404",
+			result:  "Headers were already written. Wanted to override status code 200 with 404",
+			args:    []any{slog.Bool(string(Warning), true)},
+		}, {
+			message: "Loaded HTML Templates (1): \n\t- index.html\n",
+			result:  "Loaded HTML Templates (1): ",
+			args:    []any{slog.String(string(Detail), "\t- index.html")},
+		},
+	} {
+		t.Run(test.message, func(t *testing.T) {
+			result, args, err := ParseDebug(test.message)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, test.args, args)
+		})
+	}
+}
+
+func TestParseDebug_Error(t *testing.T) {
+	result, args, err := ParseDebug(`redirecting request 301: /a/ --> /a`)
+	assert.ErrorContains(t, err, "unknown debug record")
+	assert.Equal(t, `redirecting request 301: /a/ --> /a`, result)
+	assert.Nil(t, args)
+}
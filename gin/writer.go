@@ -1,11 +1,14 @@
 package gin
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
@@ -15,6 +18,20 @@ const (
 	// DefaultTrafficMessage to be logged when the original log message is
 	// a Gin traffic data line which has been parsed under other field names.
 	DefaultTrafficMessage = "Gin Traffic"
+
+	// DefaultRouteMessage to be logged when the original log message is
+	// a Gin debug route registration line which has been parsed under other field names.
+	DefaultRouteMessage = "Gin Route"
+
+	// maxLineLength limits the length of a line held until its terminating newline is written.
+	maxLineLength = 64 * 1024
+)
+
+// Field values for panic recovery records.
+const (
+	Panic   Field = "panic"
+	Request Field = "request"
+	Stack   Field = "stack"
 )
 
 // ----------------------------------------------------------------------------
@@ -27,6 +44,9 @@ type Options struct {
 
 	// Traffic collects options related to parsing Gin traffic data records.
 	Traffic Traffic
+
+	// Debug collects options related to parsing Gin debug records.
+	Debug Debug
 }
 
 // Traffic collects options related to parsing Gin traffic data records.
@@ -59,17 +79,61 @@ type Traffic struct {
 	Group string
 }
 
+// Debug collects options related to parsing Gin debug records.
+// Records have the form:
+//
+//	[GIN-debug] GET    /chart.svg                --> main.chartFunction (3 handlers)
+//	[GIN-debug] Listening and serving HTTP on :8080
+//	[GIN-debug] [WARNING] Running in "debug" mode. Switch to "release" mode in production.
+//	 - using env:	export GIN_MODE=release
+//	 - using code:	gin.SetMode(gin.ReleaseMode)
+//
+// See ParseRoute and ParseDebug for the fields parsed from each form.
+// Records that can't be parsed are logged as if Parse were false.
+type Debug struct {
+	// Parse Gin debug records when true.
+	// Nothing else in this struct matters if this is false.
+	Parse bool
+
+	// Embed parsed debug data at the top level of the log message when true.
+	// Only used if Parse is true.
+	Embed bool
+
+	// RouteMessage to be used when route registration data has been parsed from the original message.
+	// When no message is provided the default value of DefaultRouteMessage (= "Gin Route") is used.
+	// Only used if Parse is true.
+	RouteMessage string
+
+	// Group provides a group name under which debug data will be gathered.
+	// When no group name is provided the value of DefaultTrafficGroup (= "gin") is used.
+	// Only used if Parse is true and Embed is false.
+	Group string
+}
+
 // ----------------------------------------------------------------------------
 
-// NewWriter returns an io.Writer object with the specified zerolog.Level.
+// NewWriter returns an io.WriteCloser object with the specified slog.Level.
 // There are two gin output streams: gin.DefaultWriter and gin.DefaultErrorWriter.
 // These streams are used by gin internal Code outside the request middleware loop.
-// Create a separate Writer object with a different zerolog.Level for each stream
-// or create a single object for both streams (untested but should work).
+// Create a separate Writer object with a different slog.Level for each stream
+// or create a single object for both streams.
 //
 // The options argument holds settings for the underlying writer object.
 // See the documentation for Options.
-func NewWriter(options *Options) io.Writer {
+//
+// Records are assembled from lines which may be split across or combined within Write calls.
+// A line is only processed when its terminating newline has been written
+// or when it is longer than 64 KB, in which case it is split at that point.
+// A record starts with a line beginning with a bracketed prefix (e.g. "[GIN]")
+// or a log.Logger timestamp and includes any following lines that do not,
+// such as the continuation lines of multi-line Gin warnings.
+// A record ends at the start of the next record or at the end of a Write call
+// that ends with a newline.
+// Multi-line Gin panic recovery records are logged as a single record
+// with the panic value, request dump, and stack trace as attributes.
+//
+// Call Close to log any remaining partial record.
+func NewWriter(options *Options) io.WriteCloser {
 	w := &writer{
 		Options: *options,
 	}
@@ -88,6 +152,14 @@ func NewWriter(options *Options) io.Writer {
 			w.Traffic.Message = DefaultTrafficMessage
 		}
 	}
+	if w.Debug.Parse {
+		if w.Debug.Group == "" && !w.Debug.Embed {
+			w.Debug.Group = DefaultTrafficGroup
+		}
+		if w.Debug.RouteMessage == "" {
+			w.Debug.RouteMessage = DefaultRouteMessage
+		}
+	}
 	return w
 }
 
@@ -96,6 +168,14 @@ func NewWriter(options *Options) io.Writer {
 // writer object returned by NewWriter function.
 type writer struct {
 	Options
+
+	mutex sync.Mutex
+	// partial holds the start of a line that has not yet been terminated.
+	partial []byte
+	// lines holds the lines of the current record.
+	lines []string
+	// recovery is true if the current record is a panic recovery record.
+	recovery bool
 }
 
 var (
@@ -105,30 +185,168 @@ var (
 		"INFO":    slog.LevelInfo,
 		"WARNING": slog.LevelWarn,
 	}
+	ptnANSI        = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 	ptnGIN, _      = regexp.Compile(`^\s*\[GIN]\s*`)
 	ptnGINdebug, _ = regexp.Compile(`^\s*\[GIN-debug]\s*`)
+	ptnLogDate     = regexp.MustCompile(`^\d+/\d+/\d+ \d+:\d+:\d+ `)
 	ptnLogLevel, _ = regexp.Compile(`^\s*\[(DEBUG|ERROR|INFO|WARNING|.*)]\s*`)
+	ptnRecovery    = regexp.MustCompile(`^\[Recovery]\s*(?:\d+/\d+/\d+\s*-\s*\d+:\d+:\d+\s*)?(.*?):?\s*$`)
+	ptnStackFrame  = regexp.MustCompile(`^\S.*:\d+ \(0x[0-9a-f]+\)$`)
 	ptnTimePrefix  = regexp.MustCompile(`^\s*\d+/\d+/\d+\s*-\s*\d+:\d+:\d+\s*\|\s*(.+)$`)
 )
 
 // Write a block of data to the (supposedly) stream object.
-// The data will be parsed, if possible, and converted into a slog record.
-// For the moment we're assuming that there is a single Write() call for each log record.
-// TODO: Fix code to handle partial/multiple Write() calls per log record.
+// Complete records will be parsed, if possible, and converted into slog records.
+// Any incomplete line is held until the rest of it is written.
 func (w *writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var errs []error
+	data := p
+	if len(w.partial) > 0 {
+		data = append(w.partial, p...)
+		w.partial = nil
+	}
+	for {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 {
+			break
+		}
+		if err := w.addLine(string(data[:index])); err != nil {
+			errs = append(errs, err)
+		}
+		data = data[index+1:]
+	}
+	if len(data) > maxLineLength {
+		// Don't hold an unterminated line without limit, log it as if it were complete.
+		if err := w.addLine(string(data)); err != nil {
+			errs = append(errs, err)
+		}
+		data = nil
+	}
+	if len(data) > 0 {
+		w.partial = bytes.Clone(data)
+	} else if err := w.flush(); err != nil {
+		errs = append(errs, err)
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// Close logs any remaining partial record.
+// The writer may still be used afterwards.
+func (w *writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var err error
+	if len(w.partial) > 0 {
+		err = w.addLine(string(w.partial))
+		w.partial = nil
+	}
+	return errors.Join(err, w.flush())
+}
+
+// addLine adds a complete line to the current record,
+// first logging the current record if the line starts a new one.
+func (w *writer) addLine(line string) error {
+	line = strings.TrimRight(ptnANSI.ReplaceAllString(line, ""), "\r")
+	var err error
+	if !w.recovery && (strings.HasPrefix(line, "[") || ptnLogDate.MatchString(line)) {
+		// Start of a new record.
+		err = w.flush()
+	}
+	if len(w.lines) < 1 {
+		if line == "" {
+			// Skip blank lines between records.
+			return err
+		}
+		// Remove log.Logger timestamp (e.g. from gin.Recovery).
+		line = line[len(ptnLogDate.FindString(line)):]
+		w.recovery = ptnRecovery.MatchString(line)
+	}
+	w.lines = append(w.lines, line)
+	return err
+}
+
+// flush logs the current record, if any.
+func (w *writer) flush() error {
+	lines := w.lines
+	recovery := w.recovery
+	w.lines = nil
+	w.recovery = false
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) < 1 {
+		return nil
+	}
+	if recovery {
+		w.logRecovery(lines)
+		return nil
+	}
+	return w.log(strings.Join(lines, "\n"))
+}
+
+// logRecovery logs a panic recovery record from gin.Recovery.
+// The record has the form:
+//
+//	[Recovery] 2024/01/26 - 13:21:32 panic recovered:
+//	GET / HTTP/1.1
+//	Host: localhost:8080
+//
+//	runtime error: index out of range [1] with length 0
+//	/home/user/app/main.go:23 (0x7a3e2f)
+//		handler: return items[1]
+//	...
+//
+// where the request dump lines are only present when Gin is in debug mode.
+func (w *writer) logRecovery(lines []string) {
+	msg := ptnRecovery.FindStringSubmatch(lines[0])[1]
+	lines = lines[1:]
+	stack := len(lines)
+	for i, line := range lines {
+		if ptnStackFrame.MatchString(line) {
+			stack = i
+			break
+		}
+	}
+	before := lines[:stack]
+	for len(before) > 0 && before[len(before)-1] == "" {
+		before = before[:len(before)-1]
+	}
+	var args []any
+	if len(before) > 0 {
+		args = append(args, slog.String(string(Panic), before[len(before)-1]))
+		request := before[:len(before)-1]
+		for len(request) > 0 && request[len(request)-1] == "" {
+			request = request[:len(request)-1]
+		}
+		if len(request) > 0 {
+			args = append(args, slog.String(string(Request), strings.Join(request, "\n")))
+		}
+	}
+	args = append(args, slog.String(string(Stack), strings.Join(lines[stack:], "\n")))
+	slog.Error(msg, args...)
+}
+
+// log a single record which may contain multiple lines.
+func (w *writer) log(msg string) error {
 	level := w.Level
-	msg := strings.TrimRight(string(p), "\n")
+	debug := false
+	// debugMsg is the message after the [GIN-debug] prefix, including any [WARNING] prefix.
+	var debugMsg string
 	for x := 0; x < 10; x++ { // Don't use infinite for loop for safety
 		// Pull off prefix sequences that represent log information.
 		if match := ptnGIN.FindString(msg); match != "" {
 			msg = msg[len(match):]
 		} else if match := ptnGINdebug.FindString(msg); match != "" {
 			level = slog.LevelDebug
+			debug = true
 			msg = msg[len(match):]
+			debugMsg = msg
 		} else if matches := ptnLogLevel.FindStringSubmatch(msg); len(matches) > 1 {
 			var ok bool
 			if level, ok = logLevels[matches[1]]; !ok {
-				return 0, fmt.Errorf("no level %s", matches[1])
+				return fmt.Errorf("no level %s", matches[1])
 			}
 			msg = msg[len(matches[0]):]
 		} else {
@@ -145,7 +363,20 @@ func (w *writer) Write(p []byte) (int, error) {
 
 	var args []any
 	var err error
-	if w.Traffic.Parse {
+	if debug && w.Debug.Parse {
+		// Attempt to parse known Gin debug data out of the message.
+		//  GET    /chart.svg                --> main.chartFunction (3 handlers)
+		if args, err = ParseRoute(msg); err == nil {
+			msg = w.Debug.RouteMessage
+		} else if msg, args, err = ParseDebug(debugMsg); err != nil {
+			// The error isn't really an error, it just couldn't parse.
+			slog.Debug("gin debug parse", "err", err)
+			// Use the pre-existing log message in variable msg.
+		}
+		if len(args) > 0 && !w.Debug.Embed {
+			args = []any{slog.Group(w.Debug.Group, args...)}
+		}
+	} else if w.Traffic.Parse {
 		// Attempt to parse Gin traffic data out of the message.
 		//  200 |  5.529751605s |             ::1 | GET      "/chart.svg?tag=With_Attrs_Attributes&item=MemBytes"
 		if args, err = Parse(msg); err != nil {
@@ -173,8 +404,8 @@ func (w *writer) Write(p []byte) (int, error) {
 		slog.Warn(msg, args...)
 	default:
 		// Shouldn't ever happen so no test code coverage here.
-		return 0, fmt.Errorf("unknown log level %s", w.Level)
+		return fmt.Errorf("unknown log level %s", w.Level)
 	}
 
-	return len(p), nil
+	return nil
 }
@@ -3,8 +3,13 @@ package gin
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +24,8 @@ const ginTraffic = `200 |  5.529751605s |             ::1 | GET      "/chart.svg
 
 type WriterTestSuite struct {
 	suite.Suite
+	// level for captured log output, defaults to slog.LevelInfo.
+	level slog.Leveler
 }
 
 func TestWriterSuite(t *testing.T) {
@@ -49,7 +56,7 @@ func (suite *WriterTestSuite) GinStartupTest() {
 func (suite *WriterTestSuite) TestDefault() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultWriter.Write([]byte("TestDefault"))
+			_, err := gin.DefaultWriter.Write([]byte("TestDefault\n"))
 			require.NoError(t, err)
 		}, func(t *testing.T, record map[string]interface{}) {
 			assert.Equal(t, "INFO", record[slog.LevelKey])
@@ -63,7 +70,7 @@ func (suite *WriterTestSuite) TestDefault() {
 func (suite *WriterTestSuite) TestDefaultDebug() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("[DEBUG] TestDefaultDebug"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("[DEBUG] TestDefaultDebug\n"))
 			require.NoError(t, err)
 		},
 		nil,
@@ -75,7 +82,7 @@ func (suite *WriterTestSuite) TestDefaultDebug() {
 func (suite *WriterTestSuite) TestDefaultGin() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("[GIN] TestDefaultGin"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("[GIN] TestDefaultGin\n"))
 			require.NoError(t, err)
 		},
 		func(t *testing.T, record map[string]interface{}) {
@@ -90,7 +97,7 @@ func (suite *WriterTestSuite) TestDefaultGin() {
 func (suite *WriterTestSuite) TestDefaultBadLevel() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("[BAD] TestDefaultBadLevel"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("[BAD] TestDefaultBadLevel\n"))
 			require.ErrorContains(t, err, "no level BAD")
 		},
 		nil,
@@ -102,7 +109,7 @@ func (suite *WriterTestSuite) TestDefaultBadLevel() {
 func (suite *WriterTestSuite) TestDefaultWarning() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("[WARNING] TestDefaultWarning"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("[WARNING] TestDefaultWarning\n"))
 			require.NoError(t, err)
 		},
 		func(t *testing.T, record map[string]interface{}) {
@@ -117,7 +124,7 @@ func (suite *WriterTestSuite) TestDefaultWarning() {
 func (suite *WriterTestSuite) TestError() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("TestError"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("TestError\n"))
 			require.NoError(t, err)
 		},
 		func(t *testing.T, record map[string]interface{}) {
@@ -132,7 +139,7 @@ func (suite *WriterTestSuite) TestError() {
 func (suite *WriterTestSuite) TestErrorWarning() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultErrorWriter.Write([]byte("[WARNING] TestErrorWarning"))
+			_, err := gin.DefaultErrorWriter.Write([]byte("[WARNING] TestErrorWarning\n"))
 			require.NoError(t, err)
 		},
 		func(t *testing.T, record map[string]interface{}) {
@@ -147,7 +154,7 @@ func (suite *WriterTestSuite) TestErrorWarning() {
 func (suite *WriterTestSuite) TestTrafficIgnore() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultWriter.Write([]byte(ginLine))
+			_, err := gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 			require.NoError(t, err)
 		}, func(t *testing.T, record map[string]interface{}) {
 			assert.Equal(t, "INFO", record[slog.LevelKey])
@@ -161,7 +168,7 @@ func (suite *WriterTestSuite) TestTrafficIgnore() {
 func (suite *WriterTestSuite) TestTrafficEmbed() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultWriter.Write([]byte(ginLine))
+			_, err := gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 			require.NoError(t, err)
 		}, func(t *testing.T, record map[string]interface{}) {
 			assert.Equal(t, "INFO", record[slog.LevelKey])
@@ -176,7 +183,7 @@ func (suite *WriterTestSuite) TestTrafficEmbed() {
 func (suite *WriterTestSuite) TestTrafficGroup() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultWriter.Write([]byte(ginLine))
+			_, err := gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 			require.NoError(t, err)
 		}, func(t *testing.T, record map[string]interface{}) {
 			assert.Equal(t, "INFO", record[slog.LevelKey])
@@ -192,7 +199,7 @@ func (suite *WriterTestSuite) TestTrafficGroup() {
 func (suite *WriterTestSuite) TestTrafficGroupName() {
 	suite.testLog(
 		func(t *testing.T) {
-			_, err := gin.DefaultWriter.Write([]byte(ginLine))
+			_, err := gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 			require.NoError(t, err)
 		}, func(t *testing.T, record map[string]interface{}) {
 			assert.Equal(t, "INFO", record[slog.LevelKey])
@@ -205,12 +212,254 @@ func (suite *WriterTestSuite) TestTrafficGroupName() {
 	)
 }
 
+// ----------------------------------------------------------------------------
+
+func (suite *WriterTestSuite) TestPartial() {
+	suite.testRecords(
+		func(t *testing.T) {
+			for _, part := range []string{"[WARNING] Test", "Par", "tial\n[GIN] Second"} {
+				_, err := gin.DefaultWriter.Write([]byte(part))
+				require.NoError(t, err)
+			}
+			require.NoError(t, gin.DefaultWriter.(io.Closer).Close())
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 2)
+			assert.Equal(t, "WARN", records[0][slog.LevelKey])
+			assert.Equal(t, "TestPartial", records[0][slog.MessageKey])
+			assert.Equal(t, "INFO", records[1][slog.LevelKey])
+			assert.Equal(t, "Second", records[1][slog.MessageKey])
+		},
+		Options{})
+}
+
+func (suite *WriterTestSuite) TestPartialMax() {
+	long := strings.Repeat("x", maxLineLength)
+	suite.testRecords(
+		func(t *testing.T) {
+			for _, part := range []string{"[GIN] ", long[6:], "y", "z\n"} {
+				_, err := gin.DefaultWriter.Write([]byte(part))
+				require.NoError(t, err)
+			}
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 2)
+			assert.Equal(t, long[6:]+"y", records[0][slog.MessageKey])
+			assert.Equal(t, "z", records[1][slog.MessageKey])
+		},
+		Options{})
+}
+
+func (suite *WriterTestSuite) TestMultiple() {
+	suite.testRecords(
+		func(t *testing.T) {
+			_, err := gin.DefaultWriter.Write([]byte(
+				ginLine + "\n[WARNING] First line\n - continued\nand continued\n\n[GIN] Last\n"))
+			require.NoError(t, err)
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 3)
+			assert.Equal(t, DefaultTrafficMessage, records[0][slog.MessageKey])
+			assert.Equal(t, "WARN", records[1][slog.LevelKey])
+			assert.Equal(t, "First line\n - continued\nand continued", records[1][slog.MessageKey])
+			assert.Equal(t, "Last", records[2][slog.MessageKey])
+		},
+		Options{Traffic: Traffic{Parse: true}})
+}
+
+func (suite *WriterTestSuite) TestConcurrent() {
+	const writers, lines = 10, 100
+	suite.testRecords(
+		func(t *testing.T) {
+			var wg sync.WaitGroup
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < lines; j++ {
+						_, err := gin.DefaultWriter.Write([]byte(ginLine + "\n"))
+						assert.NoError(t, err)
+					}
+				}()
+			}
+			wg.Wait()
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, writers*lines)
+			for _, record := range records {
+				checkTraffic(t, record)
+			}
+		},
+		Options{Traffic: Traffic{Parse: true, Embed: true}})
+}
+
+func (suite *WriterTestSuite) TestRecovery() {
+	suite.testRecords(
+		func(t *testing.T) {
+			router := gin.New()
+			router.Use(gin.Recovery())
+			router.GET("/panic", func(c *gin.Context) {
+				panic("test panic")
+			})
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/panic", nil))
+			assert.Equal(t, http.StatusInternalServerError, response.Code)
+		}, func(t *testing.T, records []map[string]any) {
+			var record map[string]any
+			for _, r := range records {
+				if r[slog.LevelKey] == "ERROR" {
+					require.Nil(t, record, "multiple error records")
+					record = r
+				}
+			}
+			require.NotNil(t, record)
+			assert.Equal(t, "panic recovered", record[slog.MessageKey])
+			assert.Equal(t, "test panic", record[string(Panic)])
+			assert.Contains(t, record[string(Request)], "GET /panic HTTP/1.1")
+			stack, ok := record[string(Stack)].(string)
+			require.True(t, ok)
+			assert.Contains(t, stack, "writer_test.go:")
+			assert.Contains(t, stack, `panic("test panic")`)
+			assert.NotContains(t, stack, "\x1b")
+		},
+		Options{})
+}
+
+func (suite *WriterTestSuite) TestRecoveryRelease() {
+	suite.testRecords(
+		func(t *testing.T) {
+			_, err := gin.DefaultErrorWriter.Write([]byte("\n\n\x1b[31m2024/01/26 13:21:32 [Recovery] 2024/01/26 - 13:21:32 panic recovered:\n" +
+				"runtime error: index out of range [1] with length 0\n" +
+				"/home/user/app/main.go:23 (0x7a3e2f)\n" +
+				"\thandler: return items[1]\n" +
+				"/home/user/go/pkg/mod/github.com/gin-gonic/gin@v1.10.0/context.go:185 (0x6f1a0a)\n" +
+				"\t(*Context).Next: c.handlers[c.index](c)\n\x1b[0m\n"))
+			require.NoError(t, err)
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 1)
+			assert.Equal(t, "ERROR", records[0][slog.LevelKey])
+			assert.Equal(t, "panic recovered", records[0][slog.MessageKey])
+			assert.Equal(t, "runtime error: index out of range [1] with length 0", records[0][string(Panic)])
+			assert.NotContains(t, records[0], string(Request))
+			assert.Equal(t, "/home/user/app/main.go:23 (0x7a3e2f)\n"+
+				"\thandler: return items[1]\n"+
+				"/home/user/go/pkg/mod/github.com/gin-gonic/gin@v1.10.0/context.go:185 (0x6f1a0a)\n"+
+				"\t(*Context).Next: c.handlers[c.index](c)", records[0][string(Stack)])
+		},
+		Options{})
+}
+
+// ----------------------------------------------------------------------------
+
+func (suite *WriterTestSuite) TestDebugRoutes() {
+	suite.testDebug(
+		func(t *testing.T) {
+			router := gin.New()
+			router.GET("/user/:id", func(c *gin.Context) {})
+			router.POST("/user", gin.Recovery(), func(c *gin.Context) {})
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 3)
+			assert.Equal(t, "WARN", records[0][slog.LevelKey])
+			assert.Equal(t, `Running in "debug" mode. Switch to "release" mode in production.`, records[0][slog.MessageKey])
+			group, ok := records[0][DefaultTrafficGroup].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "debug", group[string(Mode)])
+			assert.Equal(t, " - using env:\texport GIN_MODE=release\n - using code:\tgin.SetMode(gin.ReleaseMode)",
+				group[string(Detail)])
+			for i, route := range []struct {
+				method, path string
+				count        float64
+			}{
+				{"GET", "/user/:id", 1},
+				{"POST", "/user", 2},
+			} {
+				record := records[i+1]
+				assert.Equal(t, "DEBUG", record[slog.LevelKey])
+				assert.Equal(t, DefaultRouteMessage, record[slog.MessageKey])
+				group, ok := record[DefaultTrafficGroup].(map[string]any)
+				require.True(t, ok)
+				assert.Equal(t, route.method, group[string(Method)])
+				assert.Equal(t, route.path, group[string(Path)])
+				assert.Contains(t, group[string(Handler)], "TestDebugRoutes")
+				assert.Equal(t, route.count, group[string(HandlerCount)])
+			}
+		},
+		Options{Debug: Debug{Parse: true}})
+}
+
+func (suite *WriterTestSuite) TestDebugEmbed() {
+	suite.testDebug(
+		func(t *testing.T) {
+			_, err := gin.DefaultWriter.Write([]byte(
+				"[GIN-debug] GET    /chart.svg                --> main.chartFunction (3 handlers)\n" +
+					"[GIN-debug] Listening and serving HTTPS on localhost:8443\n" +
+					"[GIN-debug] [WARNING] You trusted all proxies, this is NOT safe. We recommend you to set a value.\n" +
+					"Please check https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies for details.\n" +
+					"[GIN-debug] Something else\n" +
+					"[GIN-debug] [WARNING] Creating an Engine instance with the Logger and Recovery middleware already attached.\n"))
+			require.NoError(t, err)
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 6)
+			assert.Equal(t, "Route Registered", records[0][slog.MessageKey])
+			assert.Equal(t, "GET", records[0][string(Method)])
+			assert.Equal(t, "/chart.svg", records[0][string(Path)])
+			assert.Equal(t, "main.chartFunction", records[0][string(Handler)])
+			assert.Equal(t, float64(3), records[0][string(HandlerCount)])
+			assert.Equal(t, "DEBUG", records[1][slog.LevelKey])
+			assert.Equal(t, "Listening and serving HTTPS", records[1][slog.MessageKey])
+			assert.Equal(t, "HTTPS", records[1][string(Protocol)])
+			assert.Equal(t, "localhost:8443", records[1][string(Address)])
+			assert.Equal(t, "WARN", records[2][slog.LevelKey])
+			assert.Equal(t, "You trusted all proxies, this is NOT safe. We recommend you to set a value.", records[2][slog.MessageKey])
+			assert.Contains(t, records[2][string(Detail)], "Please check")
+			assert.Equal(t, true, records[2][string(Warning)])
+			assert.Equal(t, "gin debug parse", records[3][slog.MessageKey])
+			assert.Equal(t, "Something else", records[4][slog.MessageKey])
+			assert.NotContains(t, records[4], string(Detail))
+			assert.NotContains(t, records[4], string(Warning))
+			assert.Equal(t, "WARN", records[5][slog.LevelKey])
+			assert.Equal(t, "Creating an Engine instance with the Logger and Recovery middleware already attached.", records[5][slog.MessageKey])
+			assert.Equal(t, true, records[5][string(Warning)])
+		},
+		Options{Debug: Debug{Parse: true, Embed: true, RouteMessage: "Route Registered"}})
+}
+
+func (suite *WriterTestSuite) TestDebugIgnore() {
+	suite.testDebug(
+		func(t *testing.T) {
+			_, err := gin.DefaultWriter.Write([]byte(
+				"[GIN-debug] GET    /chart.svg                --> main.chartFunction (3 handlers)\n"))
+			require.NoError(t, err)
+		}, func(t *testing.T, records []map[string]any) {
+			require.Len(t, records, 1)
+			assert.Equal(t, "GET    /chart.svg                --> main.chartFunction (3 handlers)", records[0][slog.MessageKey])
+			assert.NotContains(t, records[0], DefaultTrafficGroup)
+		},
+		Options{})
+}
+
 //////////////////////////////////////////////////////////////////////////
 
+// testDebug is testRecords with debug level log output.
+func (suite *WriterTestSuite) testDebug(
+	test func(t *testing.T),
+	check func(t *testing.T, records []map[string]any),
+	options Options,
+) {
+	suite.level = slog.LevelDebug
+	defer func() { suite.level = nil }()
+	suite.testRecords(test, check, options)
+}
+
+func (suite *WriterTestSuite) testRecords(
+	test func(t *testing.T),
+	check func(t *testing.T, records []map[string]any),
+	options Options,
+) {
+	suite.testLog(test, nil, options, check)
+}
+
 func (suite *WriterTestSuite) testLog(
 	test func(t *testing.T),
 	check func(t *testing.T, record map[string]interface{}),
 	options Options,
+	checkAll ...func(t *testing.T, records []map[string]any),
 ) {
 	gin.DefaultWriter = NewWriter(&options)
 	options.Level = slog.LevelError
@@ -224,7 +473,7 @@ func (suite *WriterTestSuite) testLog(
 	sLog := slog.Default()
 	defer slog.SetDefault(sLog)
 	buffer := &bytes.Buffer{}
-	slog.SetDefault(slog.New(slog.NewJSONHandler(buffer, nil)))
+	slog.SetDefault(slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: suite.level})))
 
 	// Execute test.
 	test(suite.T())
@@ -234,6 +483,16 @@ func (suite *WriterTestSuite) testLog(
 		suite.Require().NoError(json.Unmarshal(buffer.Bytes(), &record))
 		check(suite.T(), record)
 	}
+	for _, checkRecords := range checkAll {
+		var records []map[string]any
+		decoder := json.NewDecoder(buffer)
+		for decoder.More() {
+			var record map[string]any
+			suite.Require().NoError(decoder.Decode(&record))
+			records = append(records, record)
+		}
+		checkRecords(suite.T(), records)
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////
@@ -256,7 +515,7 @@ func ExampleWriter() {
 		gin.DefaultErrorWriter = os.Stderr
 	}()
 	_ = gin.New()
-	_, _ = gin.DefaultWriter.Write([]byte(ginLine))
+	_, _ = gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 	// Output:
 	// <*> WRN Running in "debug" mode. Switch to "release" mode in production.
 	//  - using env:	export GIN_MODE=release
@@ -288,7 +547,7 @@ func ExampleWriter_embedTraffic() {
 		gin.DefaultErrorWriter = os.Stderr
 	}()
 	_ = gin.New()
-	_, _ = gin.DefaultWriter.Write([]byte(ginLine))
+	_, _ = gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 	// Output:
 	// <*> WRN Running in "debug" mode. Switch to "release" mode in production.
 	//  - using env:	export GIN_MODE=release
@@ -319,7 +578,7 @@ func ExampleWriter_groupTraffic() {
 		gin.DefaultErrorWriter = os.Stderr
 	}()
 	_ = gin.New()
-	_, _ = gin.DefaultWriter.Write([]byte(ginLine))
+	_, _ = gin.DefaultWriter.Write([]byte(ginLine + "\n"))
 	// Output:
 	// <*> WRN Running in "debug" mode. Switch to "release" mode in production.
 	//  - using env:	export GIN_MODE=release
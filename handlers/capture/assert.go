@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertCount asserts that there are the specified number of records.
+func (rs Records) AssertCount(t assert.TestingT, count int, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if len(rs) != count {
+		return assert.Fail(t,
+			fmt.Sprintf("Expected %d log records, found %d: %v", count, len(rs), rs.Messages()),
+			msgAndArgs...)
+	}
+	return true
+}
+
+// AssertEmpty asserts that there are no records.
+func (rs Records) AssertEmpty(t assert.TestingT, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	return rs.AssertCount(t, 0, msgAndArgs...)
+}
+
+// AssertNotEmpty asserts that there is at least one record.
+func (rs Records) AssertNotEmpty(t assert.TestingT, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if len(rs) < 1 {
+		return assert.Fail(t, "Expected log records, found none", msgAndArgs...)
+	}
+	return true
+}
+
+// AssertLogged asserts that there is at least one record with the specified level and message.
+func (rs Records) AssertLogged(t assert.TestingT, level slog.Level, message string, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if len(rs.Level(level).Message(message)) < 1 {
+		return assert.Fail(t,
+			fmt.Sprintf("Expected log record %s %q, found: %v", level, message, rs.Messages()),
+			msgAndArgs...)
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
+
+// AssertAttr asserts that the record has an attribute at the specified path with the specified value.
+// The value may be a slog.Value or any value acceptable to slog.AnyValue.
+func (r Record) AssertAttr(t assert.TestingT, path string, value any, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	actual, found := r.Value(path)
+	if !found {
+		return assert.Fail(t, fmt.Sprintf("Attribute %q not found in %v", path, r.Attrs), msgAndArgs...)
+	}
+	if !equalValue(actual, value) {
+		return assert.Fail(t,
+			fmt.Sprintf("Attribute %q not equal:\nexpected: %v\nactual  : %v", path, value, actual),
+			msgAndArgs...)
+	}
+	return true
+}
+
+// AssertHasAttr asserts that the record has an attribute at the specified path.
+func (r Record) AssertHasAttr(t assert.TestingT, path string, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if !r.HasAttr(path) {
+		return assert.Fail(t, fmt.Sprintf("Attribute %q not found in %v", path, r.Attrs), msgAndArgs...)
+	}
+	return true
+}
+
+// AssertNoAttr asserts that the record has no attribute at the specified path.
+func (r Record) AssertNoAttr(t assert.TestingT, path string, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if r.HasAttr(path) {
+		return assert.Fail(t, fmt.Sprintf("Unexpected attribute %q found in %v", path, r.Attrs), msgAndArgs...)
+	}
+	return true
+}
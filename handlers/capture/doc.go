@@ -0,0 +1,23 @@
+// Package capture provides a slog.Handler that records log records in memory for unit tests.
+//
+// Records are stored fully resolved:
+// LogValuer values are resolved, WithAttrs attributes and WithGroup groups are applied,
+// empty attributes and groups are removed, and groups with empty names are inlined.
+// This allows application tests to inspect log output without
+// depending on the format of any particular encoder.
+//
+// # Queries
+//
+// The Handler method Records returns a [capture.Records] slice which provides
+// chainable query methods to select records by level, message, or attribute.
+// Attributes are addressed by dotted paths consisting of group names followed by the key,
+// for example "G1.G2.key".
+//
+// # Assertions
+//
+// The Records and Record types also provide testify-style assertion methods
+// which accept an assert.TestingT (such as *testing.T) and
+// optional message and arguments, and return true if the assertion succeeded.
+//
+// [capture.Records]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/capture#Records
+package capture
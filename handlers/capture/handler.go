@@ -0,0 +1,218 @@
+package capture
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler is a slog.Handler that stores resolved log records in memory.
+// Records are shared by the handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type Handler struct {
+	options  *slog.HandlerOptions
+	recorder *recorder
+
+	// frames holds attributes for the top level and each open group.
+	// The first frame has no name and represents the top level.
+	frames []frame
+}
+
+// frame holds the attributes added by WithAttrs within a single group.
+type frame struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// recorder holds records for a handler and all handlers derived from it.
+type recorder struct {
+	mutex   sync.Mutex
+	records Records
+}
+
+// NewHandler returns a new capture handler.
+// If the options argument is nil it will be set to a level of slog.LevelInfo and nothing else.
+// The options ReplaceAttr function, if any, is applied to attributes but
+// not to the basic fields (time, level, message, and source) which are stored separately.
+func NewHandler(options *slog.HandlerOptions) *Handler {
+	return &Handler{
+		options:  fixOptions(options),
+		recorder: &recorder{},
+		frames:   []frame{{}},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.options.Level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	rec := Record{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+	}
+	if h.options.AddSource && record.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{record.PC})
+		f, _ := fs.Next()
+		rec.Source = &slog.Source{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		}
+	}
+
+	// Record attributes belong to the innermost group.
+	last := len(h.frames) - 1
+	attrs := make([]slog.Attr, len(h.frames[last].attrs), len(h.frames[last].attrs)+record.NumAttrs())
+	copy(attrs, h.frames[last].attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = h.resolve(attrs, h.groupNames(), attr)
+		return true
+	})
+
+	// Wrap each group around the attributes within it, working outwards.
+	for i := last; i > 0; i-- {
+		outer := make([]slog.Attr, len(h.frames[i-1].attrs), len(h.frames[i-1].attrs)+1)
+		copy(outer, h.frames[i-1].attrs)
+		if len(attrs) > 0 {
+			outer = append(outer, slog.Attr{Key: h.frames[i].name, Value: slog.GroupValue(attrs...)})
+		}
+		attrs = outer
+	}
+	rec.Attrs = attrs
+
+	h.recorder.mutex.Lock()
+	defer h.recorder.mutex.Unlock()
+	h.recorder.records = append(h.recorder.records, rec)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	hdlr := h.clone()
+	last := len(hdlr.frames) - 1
+	frm := &hdlr.frames[last]
+	added := make([]slog.Attr, len(frm.attrs), len(frm.attrs)+len(attrs))
+	copy(added, frm.attrs)
+	groups := h.groupNames()
+	for _, attr := range attrs {
+		added = h.resolve(added, groups, attr)
+	}
+	frm.attrs = added
+	return hdlr
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		// Groups with empty names are to be inlined.
+		return h
+	}
+	hdlr := h.clone()
+	hdlr.frames = append(hdlr.frames, frame{name: name})
+	return hdlr
+}
+
+// -----------------------------------------------------------------------------
+
+// Records returns a copy of the records captured so far.
+func (h *Handler) Records() Records {
+	h.recorder.mutex.Lock()
+	defer h.recorder.mutex.Unlock()
+	records := make(Records, len(h.recorder.records))
+	copy(records, h.recorder.records)
+	return records
+}
+
+// Len returns the number of records captured so far.
+func (h *Handler) Len() int {
+	h.recorder.mutex.Lock()
+	defer h.recorder.mutex.Unlock()
+	return len(h.recorder.records)
+}
+
+// Reset removes all captured records.
+func (h *Handler) Reset() {
+	h.recorder.mutex.Lock()
+	defer h.recorder.mutex.Unlock()
+	h.recorder.records = nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (h *Handler) clone() *Handler {
+	frames := make([]frame, len(h.frames), len(h.frames)+1)
+	copy(frames, h.frames)
+	return &Handler{
+		options:  h.options,
+		recorder: h.recorder,
+		frames:   frames,
+	}
+}
+
+// groupNames returns the names of the currently open groups for use with ReplaceAttr.
+func (h *Handler) groupNames() []string {
+	if len(h.frames) < 2 || h.options.ReplaceAttr == nil {
+		return nil
+	}
+	groups := make([]string, 0, len(h.frames)-1)
+	for _, frm := range h.frames[1:] {
+		groups = append(groups, frm.name)
+	}
+	return groups
+}
+
+// resolve appends the fully resolved attribute to the array.
+// Empty attributes and groups are dropped and groups with empty keys are inlined.
+func (h *Handler) resolve(attrs []slog.Attr, groups []string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		if h.options.ReplaceAttr != nil {
+			attr = h.options.ReplaceAttr(groups, attr)
+			attr.Value = attr.Value.Resolve()
+		}
+		if attr.Equal(slog.Attr{}) {
+			return attrs
+		}
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		var subGroups []string
+		if attr.Key != "" && h.options.ReplaceAttr != nil {
+			subGroups = append(append(make([]string, 0, len(groups)+1), groups...), attr.Key)
+		} else {
+			subGroups = groups
+		}
+		var members []slog.Attr
+		for _, member := range attr.Value.Group() {
+			members = h.resolve(members, subGroups, member)
+		}
+		if len(members) == 0 {
+			return attrs
+		}
+		if attr.Key == "" {
+			return append(attrs, members...)
+		}
+		return append(attrs, slog.Attr{Key: attr.Key, Value: slog.GroupValue(members...)})
+	}
+	return append(attrs, attr)
+}
+
+// fixOptions makes certain that a slog.HandlerOptions object has been properly created and
+// configured with default values.
+func fixOptions(options *slog.HandlerOptions) *slog.HandlerOptions {
+	if options == nil {
+		options = &slog.HandlerOptions{}
+	}
+	if options.Level == nil {
+		options.Level = slog.LevelInfo
+	}
+	return options
+}
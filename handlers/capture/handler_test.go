@@ -0,0 +1,192 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// mockT records assertion failures instead of failing the test.
+type mockT struct {
+	failed bool
+	output string
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.failed = true
+	m.output = fmt.Sprintf(format, args...)
+}
+
+// -----------------------------------------------------------------------------
+
+func TestSlogTest(t *testing.T) {
+	hdlr := NewHandler(nil)
+	require.NoError(t, slogtest.TestHandler(hdlr, func() []map[string]any {
+		var results []map[string]any
+		for _, r := range hdlr.Records() {
+			m := r.Map()
+			if !r.Time.IsZero() {
+				m[slog.TimeKey] = r.Time
+			}
+			m[slog.LevelKey] = r.Level
+			m[slog.MessageKey] = r.Message
+			results = append(results, m)
+		}
+		return results
+	}))
+}
+
+func TestHandler(t *testing.T) {
+	hdlr := NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true})
+	logger := slog.New(hdlr)
+	logger.Debug(test.Message, "alpha", 1)
+	logger.With("bravo", "two").WithGroup("G1").With("charlie", 3.0).WithGroup("G2").
+		Info(test.Message, "delta", true, slog.Group("G3", "echo", time.Second))
+	records := hdlr.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, 2, hdlr.Len())
+	assert.Equal(t, slog.LevelDebug, records[0].Level)
+	assert.Equal(t, test.Message, records[0].Message)
+	require.NotNil(t, records[0].Source)
+	assert.Contains(t, records[0].Source.Function, "TestHandler")
+	assert.Equal(t, map[string]any{
+		"bravo": "two",
+		"G1": map[string]any{
+			"charlie": 3.0,
+			"G2": map[string]any{
+				"delta": true,
+				"G3":    map[string]any{"echo": time.Second},
+			},
+		},
+	}, records[1].Map())
+	value, found := records[1].Value("G1.G2.G3.echo")
+	require.True(t, found)
+	assert.Equal(t, time.Second, value.Duration())
+	_, found = records[1].Value("G1.G2.missing")
+	assert.False(t, found)
+	hdlr.Reset()
+	assert.Equal(t, 0, hdlr.Len())
+}
+
+// valuer is a slog.LogValuer that resolves to a group.
+type valuer struct{}
+
+func (v valuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("resolved", "yes"))
+}
+
+func TestHandler_Resolve(t *testing.T) {
+	hdlr := NewHandler(nil)
+	logger := slog.New(hdlr)
+	logger.WithGroup("empty").Info(test.Message, "alpha", 1,
+		slog.Group("", "inline", 1), slog.Group("none"), slog.Attr{},
+		"valuer", valuer{})
+	logger.WithGroup("outer").WithGroup("empty").Info(test.Message)
+	records := hdlr.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, map[string]any{
+		"empty": map[string]any{
+			"alpha":  int64(1),
+			"inline": int64(1),
+			"valuer": map[string]any{"resolved": "yes"},
+		},
+	}, records[0].Map())
+	assert.Empty(t, records[1].Attrs)
+}
+
+func TestHandler_Siblings(t *testing.T) {
+	hdlr := NewHandler(nil)
+	parent := slog.New(hdlr).With("parent", 1)
+	parent.With("alpha", 1).Info("a")
+	parent.With("bravo", 2).Info("b")
+	records := hdlr.Records()
+	assert.Equal(t, map[string]any{"parent": int64(1), "alpha": int64(1)}, records[0].Map())
+	assert.Equal(t, map[string]any{"parent": int64(1), "bravo": int64(2)}, records[1].Map())
+}
+
+func TestHandler_ReplaceAttr(t *testing.T) {
+	hdlr := NewHandler(&slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "secret" {
+				assert.Equal(t, []string{"G1", "G2"}, groups)
+				return slog.String(a.Key, "***")
+			}
+			return a
+		},
+	})
+	slog.New(hdlr).WithGroup("G1").Info(test.Message, slog.Group("G2", "secret", "password"))
+	records := hdlr.Records()
+	require.Len(t, records, 1)
+	records[0].AssertAttr(t, "G1.G2.secret", "***")
+}
+
+func TestQuery(t *testing.T) {
+	hdlr := NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(hdlr)
+	logger.Debug("one", "count", 1)
+	logger.Info("two", "count", 2)
+	logger.WithGroup("G").Warn("three", "count", 3)
+	logger.Error("four error", "count", 4)
+	records := hdlr.Records()
+	assert.Equal(t, []string{"two"}, records.Level(slog.LevelInfo).Messages())
+	assert.Equal(t, []string{"three", "four error"}, records.MinLevel(slog.LevelWarn).Messages())
+	assert.Equal(t, []string{"one"}, records.Message("one").Messages())
+	assert.Equal(t, []string{"four error"}, records.MessageContains("error").Messages())
+	assert.Equal(t, []string{"three"}, records.HasAttr("G.count").Messages())
+	assert.Equal(t, []string{"two"}, records.Attr("count", 2).Messages())
+	assert.Equal(t, []string{"two"}, records.Attr("count", int64(2)).Messages())
+	assert.Equal(t, []string{"two"}, records.Attr("count", slog.IntValue(2)).Messages())
+	assert.Empty(t, records.Attr("count", "2"))
+	first, ok := records.MinLevel(slog.LevelInfo).First()
+	require.True(t, ok)
+	assert.Equal(t, "two", first.Message)
+	last, ok := records.Last()
+	require.True(t, ok)
+	assert.Equal(t, "four error", last.Message)
+	_, ok = records.Message("none").First()
+	assert.False(t, ok)
+}
+
+func TestAssertions(t *testing.T) {
+	hdlr := NewHandler(nil)
+	logger := slog.New(hdlr)
+	logger.Info(test.Message, "alpha", []string{"x", "y"}, slog.Group("G", "bravo", 2))
+	records := hdlr.Records()
+	assert.True(t, records.AssertCount(t, 1))
+	assert.True(t, records.AssertNotEmpty(t))
+	assert.True(t, records.Message("none").AssertEmpty(t))
+	assert.True(t, records.AssertLogged(t, slog.LevelInfo, test.Message))
+	assert.True(t, records[0].AssertAttr(t, "alpha", []string{"x", "y"}))
+	assert.True(t, records[0].AssertHasAttr(t, "G.bravo"))
+	assert.True(t, records[0].AssertNoAttr(t, "G.charlie"))
+
+	for name, fn := range map[string]func(m *mockT) bool{
+		"count":    func(m *mockT) bool { return records.AssertCount(m, 2) },
+		"empty":    func(m *mockT) bool { return records.AssertEmpty(m) },
+		"notEmpty": func(m *mockT) bool { return records.Message("none").AssertNotEmpty(m) },
+		"logged":   func(m *mockT) bool { return records.AssertLogged(m, slog.LevelWarn, test.Message) },
+		"attr":     func(m *mockT) bool { return records[0].AssertAttr(m, "G.bravo", 3) },
+		"missing":  func(m *mockT) bool { return records[0].AssertAttr(m, "G.charlie", 3) },
+		"hasAttr":  func(m *mockT) bool { return records[0].AssertHasAttr(m, "charlie") },
+		"noAttr":   func(m *mockT) bool { return records[0].AssertNoAttr(m, "alpha") },
+	} {
+		m := &mockT{}
+		assert.False(t, fn(m), name)
+		assert.True(t, m.failed, name)
+		assert.NotEmpty(t, m.output, name)
+	}
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	hdlr := NewHandler(nil)
+	assert.False(t, hdlr.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, hdlr.Enabled(context.Background(), slog.LevelInfo))
+}
@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Records is a list of captured records with query methods.
+// Query methods return new lists and can be chained.
+type Records []Record
+
+// Level returns the records with the specified level.
+func (rs Records) Level(level slog.Level) Records {
+	return rs.Filter(func(r Record) bool { return r.Level == level })
+}
+
+// MinLevel returns the records with the specified level or higher.
+func (rs Records) MinLevel(level slog.Level) Records {
+	return rs.Filter(func(r Record) bool { return r.Level >= level })
+}
+
+// Message returns the records with the specified message.
+func (rs Records) Message(message string) Records {
+	return rs.Filter(func(r Record) bool { return r.Message == message })
+}
+
+// MessageContains returns the records with messages containing the specified string.
+func (rs Records) MessageContains(substr string) Records {
+	return rs.Filter(func(r Record) bool { return strings.Contains(r.Message, substr) })
+}
+
+// HasAttr returns the records with an attribute at the specified path.
+func (rs Records) HasAttr(path string) Records {
+	return rs.Filter(func(r Record) bool { return r.HasAttr(path) })
+}
+
+// Attr returns the records with an attribute at the specified path with the specified value.
+// The value may be a slog.Value or any value acceptable to slog.AnyValue.
+// Numbers are compared by slog.Kind so int and int64 values are equivalent.
+func (rs Records) Attr(path string, value any) Records {
+	return rs.Filter(func(r Record) bool {
+		v, found := r.Value(path)
+		return found && equalValue(v, value)
+	})
+}
+
+// Filter returns the records for which the specified function returns true.
+func (rs Records) Filter(fn func(r Record) bool) Records {
+	var result Records
+	for _, r := range rs {
+		if fn(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// First returns the first record, if any.
+func (rs Records) First() (Record, bool) {
+	if len(rs) < 1 {
+		return Record{}, false
+	}
+	return rs[0], true
+}
+
+// Last returns the last record, if any.
+func (rs Records) Last() (Record, bool) {
+	if len(rs) < 1 {
+		return Record{}, false
+	}
+	return rs[len(rs)-1], true
+}
+
+// Messages returns the messages of the records in order.
+func (rs Records) Messages() []string {
+	result := make([]string, len(rs))
+	for i, r := range rs {
+		result[i] = r.Message
+	}
+	return result
+}
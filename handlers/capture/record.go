@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Record is a resolved log record.
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+
+	// Source is only set if the handler options AddSource field is true.
+	Source *slog.Source
+
+	// Attrs contains the resolved attributes, including those added via WithAttrs.
+	// Attributes logged within groups are contained in group attributes.
+	Attrs []slog.Attr
+}
+
+// Attr returns the attribute at the specified path.
+// The path consists of group names followed by the attribute key, separated by dots.
+// If there are duplicate attributes the first one is returned.
+func (r Record) Attr(path string) (slog.Attr, bool) {
+	return findAttr(r.Attrs, strings.Split(path, "."))
+}
+
+// Value returns the value of the attribute at the specified path.
+func (r Record) Value(path string) (slog.Value, bool) {
+	attr, found := r.Attr(path)
+	return attr.Value, found
+}
+
+// HasAttr returns true if there is an attribute at the specified path.
+func (r Record) HasAttr(path string) bool {
+	_, found := r.Attr(path)
+	return found
+}
+
+// Map returns the attributes as a map from keys to values.
+// Groups are represented by nested maps.
+// Values are the result of slog.Value.Any(), so numbers keep their Go types.
+// If there are duplicate attributes the last one is kept.
+func (r Record) Map() map[string]any {
+	return attrMap(r.Attrs)
+}
+
+// -----------------------------------------------------------------------------
+
+func findAttr(attrs []slog.Attr, path []string) (slog.Attr, bool) {
+	for _, attr := range attrs {
+		if attr.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return attr, true
+		}
+		if attr.Value.Kind() == slog.KindGroup {
+			// There may be more than one group with the same name.
+			if found, ok := findAttr(attr.Value.Group(), path[1:]); ok {
+				return found, true
+			}
+		}
+	}
+	return slog.Attr{}, false
+}
+
+func attrMap(attrs []slog.Attr) map[string]any {
+	result := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			result[attr.Key] = attrMap(attr.Value.Group())
+		} else {
+			result[attr.Key] = attr.Value.Any()
+		}
+	}
+	return result
+}
+
+// equalValue compares a slog.Value against an expected value.
+// The expected value may be a slog.Value or any value acceptable to slog.AnyValue.
+func equalValue(value slog.Value, expected any) bool {
+	want, ok := expected.(slog.Value)
+	if !ok {
+		want = slog.AnyValue(expected)
+	}
+	if value.Kind() == slog.KindAny && want.Kind() == slog.KindAny {
+		// Value.Equal can panic on values that are not comparable.
+		return reflect.DeepEqual(value.Any(), want.Any())
+	}
+	return value.Equal(want)
+}
@@ -0,0 +1,74 @@
+package ctxattr
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextKey is the unexported type of the context key for attributes.
+type contextKey struct{}
+
+// With returns a copy of the context with the specified attributes added to
+// any attributes already attached to the context.
+// An attribute with the same key as an existing attribute replaces it.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) < 1 {
+		return ctx
+	}
+	existing := Attrs(ctx)
+	// Always copy so that contexts derived from the same parent don't share attributes.
+	merged := make([]slog.Attr, len(existing), len(existing)+len(attrs))
+	copy(merged, existing)
+next:
+	for _, attr := range attrs {
+		for i := range merged {
+			if merged[i].Key == attr.Key {
+				merged[i] = attr
+				continue next
+			}
+		}
+		merged = append(merged, attr)
+	}
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// Attrs returns the attributes attached to the context, if any.
+// The returned slice must not be modified.
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	if attrs, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		return attrs
+	}
+	return nil
+}
+
+// without returns a copy of the context with no attributes.
+func without(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, []slog.Attr(nil))
+}
+
+// -----------------------------------------------------------------------------
+
+// Options configures the placement of context attributes in log records.
+type Options struct {
+	// Group is the name of the group under which context attributes are placed.
+	// If not set context attributes are placed at the top level of the log record.
+	Group string
+}
+
+// Resolve returns the attributes attached to the context arranged per the options,
+// ready to be added to the top level of a log record.
+// If options is nil context attributes are placed at the top level.
+// Returns nil if there are no context attributes.
+func Resolve(ctx context.Context, options *Options) []slog.Attr {
+	attrs := Attrs(ctx)
+	if len(attrs) < 1 {
+		return nil
+	}
+	if options == nil || options.Group == "" {
+		return attrs
+	}
+	return []slog.Attr{{Key: options.Group, Value: slog.GroupValue(attrs...)}}
+}
@@ -0,0 +1,25 @@
+// Package ctxattr attaches slog.Attr values to a context.Context so that
+// they are added to every log record handled with that context.
+//
+// Typical usage is to add a trace ID, request ID, or tenant to the request context
+// in HTTP middleware and then log with the slog.Logger methods that take a context:
+//
+//	ctx = ctxattr.With(ctx, slog.String("requestID", id))
+//	...
+//	logger.InfoContext(ctx, "request handled")
+//
+// The flash and sloggy handlers add context attributes natively.
+// Other handlers can be wrapped with [ctxattr.Handler].
+//
+// # Placement
+//
+// Context attributes are placed at the top level of the log record by default
+// or under a named group if [ctxattr.Options].Group is set.
+// In either case they are never placed in groups opened by slog.Logger.WithGroup,
+// which is the most common problem with hand-written context middleware.
+// Context attributes follow the basic fields (time, level, message, source)
+// and precede any attributes added via slog.Logger.With.
+//
+// [ctxattr.Handler]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/ctxattr#Handler
+// [ctxattr.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/ctxattr#Options
+package ctxattr
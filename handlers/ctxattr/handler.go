@@ -0,0 +1,94 @@
+package ctxattr
+
+import (
+	"context"
+	"log/slog"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler wraps another slog.Handler and adds context attributes to each log record.
+//
+// In order to place context attributes outside of any groups opened via WithGroup
+// the handler records WithAttrs and WithGroup calls and replays them
+// on top of the context attributes when a record has context attributes.
+// This is slower than the native support in the flash and sloggy handlers
+// but works with any slog.Handler.
+// Context attributes are removed from the context passed to the next handler
+// so that they are not added again by handlers with native support.
+type Handler struct {
+	root    slog.Handler
+	next    slog.Handler
+	options *Options
+	steps   []step
+}
+
+// step records a single WithAttrs or WithGroup call.
+type step struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewHandler returns a new handler that adds context attributes to records
+// before passing them to the next handler.
+// If the options argument is nil context attributes are placed at the top level.
+func NewHandler(next slog.Handler, options *Options) *Handler {
+	if options == nil {
+		options = &Options{}
+	}
+	return &Handler{
+		root:    next,
+		next:    next,
+		options: options,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := Resolve(ctx, h.options)
+	if len(attrs) < 1 {
+		return h.next.Handle(ctx, record)
+	}
+	hdlr := h.root.WithAttrs(attrs)
+	for _, s := range h.steps {
+		if s.group != "" {
+			hdlr = hdlr.WithGroup(s.group)
+		} else {
+			hdlr = hdlr.WithAttrs(s.attrs)
+		}
+	}
+	// Handlers with native support for context attributes must not add them again.
+	return hdlr.Handle(without(ctx), record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) < 1 {
+		return h
+	}
+	return h.with(step{attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(step{group: name}, h.next.WithGroup(name))
+}
+
+// with returns a new handler with the specified step appended.
+func (h *Handler) with(s step, next slog.Handler) *Handler {
+	steps := make([]step, len(h.steps), len(h.steps)+1)
+	copy(steps, h.steps)
+	return &Handler{
+		root:    h.root,
+		next:    next,
+		options: h.options,
+		steps:   append(steps, s),
+	}
+}
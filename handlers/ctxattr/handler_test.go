@@ -0,0 +1,101 @@
+package ctxattr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+func TestWith(t *testing.T) {
+	assert.Nil(t, Attrs(context.Background()))
+	// A nil context is possible via slog.Logger.LogAttrs.
+	//goland:noinspection GoRedundantConversion
+	assert.Nil(t, Attrs(context.Context(nil)))
+	parent := With(context.Background(), slog.String("requestID", "r-1"))
+	child := With(parent, slog.Int("tenant", 7), slog.String("requestID", "r-2"))
+	sibling := With(parent, slog.Bool("admin", true))
+	assert.Equal(t, []slog.Attr{slog.String("requestID", "r-1")}, Attrs(parent))
+	assert.Equal(t, []slog.Attr{slog.String("requestID", "r-2"), slog.Int("tenant", 7)}, Attrs(child))
+	assert.Equal(t, []slog.Attr{slog.String("requestID", "r-1"), slog.Bool("admin", true)}, Attrs(sibling))
+	assert.Equal(t, parent, With(parent))
+}
+
+func TestResolve(t *testing.T) {
+	assert.Nil(t, Resolve(context.Background(), nil))
+	ctx := With(context.Background(), slog.String("requestID", "r-1"))
+	assert.Equal(t, []slog.Attr{slog.String("requestID", "r-1")}, Resolve(ctx, nil))
+	assert.Equal(t, []slog.Attr{slog.String("requestID", "r-1")}, Resolve(ctx, &Options{}))
+	assert.Equal(t, []slog.Attr{slog.Group("ctx", slog.String("requestID", "r-1"))},
+		Resolve(ctx, &Options{Group: "ctx"}))
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := With(context.Background(), slog.String("requestID", "r-1"))
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), nil)).
+		With("first", "one").
+		WithGroup("group").
+		With("second", 2)
+	logger.InfoContext(ctx, test.Message, "third", "3")
+	logMap := parseLine(t, buf.Bytes())
+	assert.Equal(t, "r-1", logMap["requestID"])
+	assert.Equal(t, "one", logMap["first"])
+	assert.Equal(t, map[string]any{"second": float64(2), "third": "3"}, logMap["group"])
+	// Context attributes precede With attributes.
+	assert.Less(t, strings.Index(buf.String(), "requestID"), strings.Index(buf.String(), "first"))
+	// Without context attributes.
+	buf.Reset()
+	logger.Info(test.Message)
+	assert.NotContains(t, buf.String(), "requestID")
+	// Under a named group.
+	buf.Reset()
+	slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{Group: "ctx"})).
+		WithGroup("group").InfoContext(ctx, test.Message, "third", "3")
+	logMap = parseLine(t, buf.Bytes())
+	assert.Equal(t, map[string]any{"requestID": "r-1"}, logMap["ctx"])
+	assert.Equal(t, map[string]any{"third": "3"}, logMap["group"])
+}
+
+// nativeHandler checks that context attributes are not passed to handlers with native support.
+type nativeHandler struct {
+	slog.Handler
+	t *testing.T
+}
+
+func (h *nativeHandler) Handle(ctx context.Context, record slog.Record) error {
+	assert.Empty(h.t, Attrs(ctx))
+	return h.Handler.Handle(ctx, record)
+}
+
+func TestHandler_native(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := With(context.Background(), slog.String("requestID", "r-1"))
+	slog.New(NewHandler(&nativeHandler{Handler: slog.NewJSONHandler(&buf, nil), t: t}, nil)).InfoContext(ctx, test.Message)
+	assert.Equal(t, 1, strings.Count(buf.String(), "requestID"))
+}
+
+func TestHandler_slogtest(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, slogtest.TestHandler(NewHandler(slog.NewJSONHandler(&buf, nil), nil), func() []map[string]any {
+		var results []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte{'\n'}) {
+			results = append(results, parseLine(t, line))
+		}
+		return results
+	}))
+}
+
+func parseLine(t *testing.T, line []byte) map[string]any {
+	var logMap map[string]any
+	require.NoError(t, json.Unmarshal(line, &logMap))
+	return logMap
+}
@@ -1,6 +1,13 @@
 // Package handlers provides several usable slog.Handler implementations.
 //
+//   - capture: slog.Handler records resolved log records in memory for unit tests
+//   - ctxattr: slog.Handler wrapper adds attributes attached to the context to each log record
+//   - fanout: slog.Handler sends each log record to multiple handlers
 //   - flash: feature-complete, reasonably performant slog.Handler
+//   - flight: slog.Handler wrapper buffers low-level log records and writes them on error
+//   - oteltrace: slog.Handler wrapper adds OpenTelemetry trace correlation attributes
+//   - sample: slog.Handler wrapper that samples and rate-limits log records
 //   - sloggy: feature-complete slog.Handler, not as fast as flash
+//   - syslog: slog.Handler renders log records as RFC 5424 (or RFC 3164) syslog messages
 //   - trace: slog.Handler prints trace of interface calls for debugging
 package handlers
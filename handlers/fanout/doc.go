@@ -0,0 +1,12 @@
+// Package fanout provides a slog.Handler that sends each log record to multiple handlers.
+//
+// Each [fanout.Branch] holds a handler and an optional level filter
+// in addition to whatever level the branch handler applies itself.
+// WithAttrs and WithGroup calls are propagated to every branch.
+// Each branch receives its own clone of the record.
+//
+// Errors returned by branch handlers are managed by a selectable [fanout.ErrorPolicy].
+//
+// [fanout.Branch]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/fanout#Branch
+// [fanout.ErrorPolicy]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/fanout#ErrorPolicy
+package fanout
@@ -0,0 +1,82 @@
+// Code generated by "enumer -type=ErrorPolicy"; DO NOT EDIT.
+
+package fanout
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _ErrorPolicyName = "ErrorJoinErrorFailFastErrorBestEffort"
+
+var _ErrorPolicyIndex = [...]uint8{0, 9, 22, 37}
+
+const _ErrorPolicyLowerName = "errorjoinerrorfailfasterrorbesteffort"
+
+func (i ErrorPolicy) String() string {
+	if i >= ErrorPolicy(len(_ErrorPolicyIndex)-1) {
+		return fmt.Sprintf("ErrorPolicy(%d)", i)
+	}
+	return _ErrorPolicyName[_ErrorPolicyIndex[i]:_ErrorPolicyIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _ErrorPolicyNoOp() {
+	var x [1]struct{}
+	_ = x[ErrorJoin-(0)]
+	_ = x[ErrorFailFast-(1)]
+	_ = x[ErrorBestEffort-(2)]
+}
+
+var _ErrorPolicyValues = []ErrorPolicy{ErrorJoin, ErrorFailFast, ErrorBestEffort}
+
+var _ErrorPolicyNameToValueMap = map[string]ErrorPolicy{
+	_ErrorPolicyName[0:9]:        ErrorJoin,
+	_ErrorPolicyLowerName[0:9]:   ErrorJoin,
+	_ErrorPolicyName[9:22]:       ErrorFailFast,
+	_ErrorPolicyLowerName[9:22]:  ErrorFailFast,
+	_ErrorPolicyName[22:37]:      ErrorBestEffort,
+	_ErrorPolicyLowerName[22:37]: ErrorBestEffort,
+}
+
+var _ErrorPolicyNames = []string{
+	_ErrorPolicyName[0:9],
+	_ErrorPolicyName[9:22],
+	_ErrorPolicyName[22:37],
+}
+
+// ErrorPolicyString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func ErrorPolicyString(s string) (ErrorPolicy, error) {
+	if val, ok := _ErrorPolicyNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _ErrorPolicyNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to ErrorPolicy values", s)
+}
+
+// ErrorPolicyValues returns all values of the enum
+func ErrorPolicyValues() []ErrorPolicy {
+	return _ErrorPolicyValues
+}
+
+// ErrorPolicyStrings returns a slice of all String values of the enum
+func ErrorPolicyStrings() []string {
+	strs := make([]string, len(_ErrorPolicyNames))
+	copy(strs, _ErrorPolicyNames)
+	return strs
+}
+
+// IsAErrorPolicy returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i ErrorPolicy) IsAErrorPolicy() bool {
+	for _, v := range _ErrorPolicyValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var _ slog.Handler = &Handler{}
+
+// ErrorPolicy specifies how errors from branch handlers are returned.
+//
+//go:generate go run github.com/dmarkham/enumer -type=ErrorPolicy
+type ErrorPolicy uint8
+
+const (
+	// ErrorJoin sends the record to all branches and returns all errors via errors.Join.
+	ErrorJoin ErrorPolicy = iota
+	// ErrorFailFast returns the first error without sending the record to any further branches.
+	ErrorFailFast
+	// ErrorBestEffort sends the record to all branches and never returns an error.
+	// Errors are only reported via Options.OnError, if configured.
+	ErrorBestEffort
+)
+
+// Branch is a single destination for log records.
+type Branch struct {
+	// Handler receives log records for the branch.
+	Handler slog.Handler
+
+	// Level is a minimum level for the branch.
+	// This is applied in addition to any level filtering by the branch handler.
+	// If not set the branch handler's Enabled method is the only filter.
+	Level slog.Leveler
+}
+
+func (b *Branch) enabled(ctx context.Context, level slog.Level) bool {
+	if b.Level != nil && level < b.Level.Level() {
+		return false
+	}
+	return b.Handler.Enabled(ctx, level)
+}
+
+// Options configures a fanout.Handler.
+type Options struct {
+	// ErrorPolicy specifies how branch handler errors are returned.
+	// If not set defaults to ErrorJoin.
+	ErrorPolicy ErrorPolicy
+
+	// OnError is called with the branch index and error for every branch handler error,
+	// regardless of ErrorPolicy.
+	OnError func(branch int, err error)
+}
+
+// Handler sends each log record to multiple branch handlers.
+type Handler struct {
+	options  *Options
+	branches []Branch
+}
+
+// NewHandler returns a new fanout handler for the specified branches.
+// If the options argument is nil the defaults are used.
+func NewHandler(options *Options, branches ...Branch) *Handler {
+	if options == nil {
+		options = &Options{}
+	}
+	return &Handler{
+		options:  options,
+		branches: append([]Branch(nil), branches...),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+// Enabled returns true if any branch is enabled for the level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	for i := range h.branches {
+		if h.branches[i].enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for i := range h.branches {
+		branch := &h.branches[i]
+		if !branch.enabled(ctx, record.Level) {
+			continue
+		}
+		// Each branch gets its own record so that added attributes aren't shared.
+		if err := branch.Handler.Handle(ctx, record.Clone()); err != nil {
+			err = fmt.Errorf("branch %d: %w", i, err)
+			if h.options.OnError != nil {
+				h.options.OnError(i, err)
+			}
+			switch h.options.ErrorPolicy {
+			case ErrorFailFast:
+				return err
+			case ErrorBestEffort:
+			default:
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	hdlr := &Handler{
+		options:  h.options,
+		branches: make([]Branch, len(h.branches)),
+	}
+	for i, branch := range h.branches {
+		branch.Handler = branch.Handler.WithAttrs(attrs)
+		hdlr.branches[i] = branch
+	}
+	return hdlr
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		// Groups with empty names are to be inlined.
+		return h
+	}
+	hdlr := &Handler{
+		options:  h.options,
+		branches: make([]Branch, len(h.branches)),
+	}
+	for i, branch := range h.branches {
+		branch.Handler = branch.Handler.WithGroup(name)
+		hdlr.branches[i] = branch
+	}
+	return hdlr
+}
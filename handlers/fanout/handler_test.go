@@ -0,0 +1,154 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/capture"
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/handlers/sloggy"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+var errBranch = errors.New("branch failed")
+
+// errorHandler is a slog.Handler that counts calls and always fails.
+type errorHandler struct {
+	calls *int
+}
+
+func (eh errorHandler) Enabled(_ context.Context, _ slog.Level) bool { return true }
+func (eh errorHandler) Handle(_ context.Context, _ slog.Record) error {
+	*eh.calls++
+	return errBranch
+}
+func (eh errorHandler) WithAttrs(_ []slog.Attr) slog.Handler { return eh }
+func (eh errorHandler) WithGroup(_ string) slog.Handler      { return eh }
+
+// -----------------------------------------------------------------------------
+
+func TestHandler(t *testing.T) {
+	alpha := capture.NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug})
+	bravo := capture.NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(NewHandler(nil, Branch{Handler: alpha}, Branch{Handler: bravo}))
+	logger.With("alpha", 1).WithGroup("G1").With("bravo", 2).WithGroup("G2").
+		Info(test.Message, "charlie", 3)
+	for _, hdlr := range []*capture.Handler{alpha, bravo} {
+		records := hdlr.Records()
+		require.True(t, records.AssertCount(t, 1))
+		assert.Equal(t, map[string]any{
+			"alpha": int64(1),
+			"G1": map[string]any{
+				"bravo": int64(2),
+				"G2":    map[string]any{"charlie": int64(3)},
+			},
+		}, records[0].Map())
+	}
+}
+
+// TestHandler_slogtest checks that both branches of the configuration
+// used by the madkins/fanout creator produce the same records.
+func TestHandler_slogtest(t *testing.T) {
+	var first, second bytes.Buffer
+	hdlr := NewHandler(nil,
+		Branch{Handler: flash.NewHandler(&first, nil, nil)},
+		Branch{Handler: sloggy.NewHandler(&second, nil)})
+	require.NoError(t, slogtest.TestHandler(hdlr, func() []map[string]any {
+		return parseLines(t, &first)
+	}))
+	assert.Equal(t, parseLines(t, &first), parseLines(t, &second))
+}
+
+func TestHandler_Levels(t *testing.T) {
+	console := capture.NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug})
+	file := capture.NewHandler(&slog.HandlerOptions{Level: slog.LevelDebug})
+	errs := capture.NewHandler(nil)
+	hdlr := NewHandler(nil,
+		Branch{Handler: console},
+		Branch{Handler: file, Level: slog.LevelInfo},
+		Branch{Handler: errs, Level: slog.LevelError})
+	assert.True(t, hdlr.Enabled(context.Background(), slog.LevelDebug))
+	logger := slog.New(hdlr)
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Error("error")
+	assert.Equal(t, []string{"debug", "info", "error"}, console.Records().Messages())
+	assert.Equal(t, []string{"info", "error"}, file.Records().Messages())
+	assert.Equal(t, []string{"error"}, errs.Records().Messages())
+
+	quiet := NewHandler(nil, Branch{Handler: errs, Level: slog.LevelError})
+	assert.False(t, quiet.Enabled(context.Background(), slog.LevelWarn))
+}
+
+func TestHandler_Clone(t *testing.T) {
+	alpha := capture.NewHandler(nil)
+	bravo := capture.NewHandler(nil)
+	record := slog.NewRecord(test.Now, slog.LevelInfo, test.Message, 0)
+	record.AddAttrs(slog.Int("count", 1))
+	require.NoError(t, NewHandler(nil, Branch{Handler: alpha}, Branch{Handler: bravo}).
+		Handle(context.Background(), record))
+	record.AddAttrs(slog.Int("count", 2))
+	alpha.Records()[0].AssertAttr(t, "count", 1)
+	bravo.Records()[0].AssertAttr(t, "count", 1)
+	assert.Len(t, bravo.Records()[0].Attrs, 1)
+}
+
+func TestHandler_ErrorPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy ErrorPolicy
+		calls  int
+		errors int
+	}{
+		{ErrorJoin, 2, 2},
+		{ErrorFailFast, 1, 1},
+		{ErrorBestEffort, 2, 0},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			var calls, reported int
+			capt := capture.NewHandler(nil)
+			hdlr := NewHandler(&Options{
+				ErrorPolicy: tc.policy,
+				OnError:     func(_ int, err error) { reported++ },
+			}, Branch{Handler: errorHandler{&calls}}, Branch{Handler: capt}, Branch{Handler: errorHandler{&calls}})
+			err := hdlr.Handle(context.Background(), slog.NewRecord(test.Now, slog.LevelInfo, test.Message, 0))
+			assert.Equal(t, tc.calls, calls)
+			assert.Equal(t, tc.calls, reported)
+			if tc.errors > 0 {
+				require.ErrorIs(t, err, errBranch)
+				if joined, ok := err.(interface{ Unwrap() []error }); ok {
+					assert.Len(t, joined.Unwrap(), tc.errors)
+				} else {
+					assert.Equal(t, 1, tc.errors)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.policy == ErrorFailFast {
+				capt.Records().AssertEmpty(t)
+			} else {
+				capt.Records().AssertCount(t, 1)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// parseLines parses each line of JSON in the buffer into a map.
+func parseLines(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	var results []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte{'\n'}) {
+		var result map[string]any
+		require.NoError(t, json.Unmarshal(line, &result))
+		results = append(results, result)
+	}
+	return results
+}
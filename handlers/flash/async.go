@@ -0,0 +1,238 @@
+package flash
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+const DefaultQueueDepth = 1024
+
+// ErrClosed is returned when logging to a handler after its Close method has been called.
+var ErrClosed = errors.New("flash handler closed")
+
+//go:generate go run github.com/dmarkham/enumer -type=Overflow
+type Overflow uint8
+
+const (
+	// OverflowBlock mode blocks the logging goroutine until there is room in the queue.
+	OverflowBlock Overflow = iota
+	// OverflowDropNewest mode drops the record being logged when the queue is full.
+	OverflowDropNewest
+	// OverflowDropOldest mode drops the oldest queued record to make room for the new one.
+	// Pending Flush or Close calls still wait for the records queued before them to be written.
+	OverflowDropOldest
+)
+
+// AsyncOptions configures asynchronous output for a flash.Handler.
+//
+// Log records are composed by the goroutine that logs them and then pushed onto
+// a bounded queue which is drained by a background goroutine that does the actual writing.
+// This keeps slow io.Writer destinations from stalling the goroutines that are logging.
+type AsyncOptions struct {
+	// QueueDepth is the maximum number of log records waiting to be written.
+	// If not set defaults to the value of flash.DefaultQueueDepth (= 1024).
+	QueueDepth uint
+
+	// Overflow specifies what happens when a record is logged and the queue is full.
+	// If not set defaults to OverflowBlock which doesn't lose any log records.
+	Overflow Overflow
+}
+
+// AsyncStats contains counters for asynchronous output.
+// All counters are zero when the handler is not in asynchronous mode.
+type AsyncStats struct {
+	// Written is the number of records written to the io.Writer.
+	Written uint64
+
+	// DroppedNewest is the number of records dropped by OverflowDropNewest.
+	DroppedNewest uint64
+
+	// DroppedOldest is the number of records dropped by OverflowDropOldest.
+	DroppedOldest uint64
+
+	// WriteErrors is the number of io.Writer errors returned during writing.
+	WriteErrors uint64
+}
+
+// Dropped returns the total number of records dropped due to queue overflow.
+func (as AsyncStats) Dropped() uint64 {
+	return as.DroppedNewest + as.DroppedOldest
+}
+
+// -----------------------------------------------------------------------------
+
+// asyncItem is a single entry in the asyncWriter queue.
+// Either the line is a composed log record or flushed is a channel to close
+// when all preceding records have been written.
+type asyncItem struct {
+	line    []byte
+	flushed chan struct{}
+}
+
+// asyncWriter manages the queue and background goroutine for asynchronous output.
+// A single asyncWriter is shared by a handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type asyncWriter struct {
+	writer   io.Writer
+	overflow Overflow
+	queue    chan asyncItem
+	done     chan struct{}
+
+	// lock guards closed and the queue channel against being closed while in use.
+	lock   sync.RWMutex
+	closed bool
+
+	errLock sync.Mutex
+	err     error
+
+	written       atomic.Uint64
+	droppedNewest atomic.Uint64
+	droppedOldest atomic.Uint64
+	writeErrors   atomic.Uint64
+}
+
+func newAsyncWriter(writer io.Writer, options *AsyncOptions) *asyncWriter {
+	depth := options.QueueDepth
+	if depth < 1 {
+		depth = DefaultQueueDepth
+	}
+	aw := &asyncWriter{
+		writer:   writer,
+		overflow: options.Overflow,
+		queue:    make(chan asyncItem, depth),
+		done:     make(chan struct{}),
+	}
+	go aw.drain()
+	return aw
+}
+
+// drain is the background goroutine that writes queued records.
+func (aw *asyncWriter) drain() {
+	defer close(aw.done)
+	for item := range aw.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if _, err := aw.writer.Write(item.line); err != nil {
+			aw.writeErrors.Add(1)
+			aw.errLock.Lock()
+			if aw.err == nil {
+				aw.err = fmt.Errorf("write log line: %w", err)
+			}
+			aw.errLock.Unlock()
+		} else {
+			aw.written.Add(1)
+		}
+		logPool.put(item.line)
+	}
+}
+
+// enqueue pushes a composed log record onto the queue per the overflow policy.
+// Ownership of the line passes to the asyncWriter which returns it to logPool when done.
+func (aw *asyncWriter) enqueue(line []byte) error {
+	aw.lock.RLock()
+	defer aw.lock.RUnlock()
+	if aw.closed {
+		logPool.put(line)
+		return ErrClosed
+	}
+	item := asyncItem{line: line}
+	switch aw.overflow {
+	case OverflowDropNewest:
+		select {
+		case aw.queue <- item:
+		default:
+			aw.droppedNewest.Add(1)
+			logPool.put(line)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case aw.queue <- item:
+				return nil
+			default:
+			}
+			// Queue is full, make room by removing the oldest record.
+			aw.dropOldest()
+		}
+	default:
+		aw.queue <- item
+	}
+	return nil
+}
+
+// dropOldest removes the oldest queued record to make room in the queue.
+// Flush markers are never dropped, any removed on the way are queued again
+// so that flush still waits for all records queued before it.
+func (aw *asyncWriter) dropOldest() {
+	var markers []asyncItem
+	defer func() {
+		for _, marker := range markers {
+			aw.queue <- marker
+		}
+	}()
+	for {
+		select {
+		case old := <-aw.queue:
+			if old.flushed == nil {
+				aw.droppedOldest.Add(1)
+				logPool.put(old.line)
+				return
+			}
+			markers = append(markers, old)
+		default:
+			// The background goroutine emptied the queue in the meantime.
+			return
+		}
+	}
+}
+
+// flush blocks until all records queued before the call have been written.
+// The result is the first write error encountered since the previous flush, if any.
+func (aw *asyncWriter) flush() error {
+	aw.lock.RLock()
+	if aw.closed {
+		aw.lock.RUnlock()
+		return aw.takeError()
+	}
+	flushed := make(chan struct{})
+	// Flush markers always block, they must not be dropped on entry.
+	aw.queue <- asyncItem{flushed: flushed}
+	aw.lock.RUnlock()
+	<-flushed
+	return aw.takeError()
+}
+
+// close stops accepting records, writes any that are queued, and stops the background goroutine.
+// The underlying io.Writer is not closed as it was not opened by the handler.
+func (aw *asyncWriter) close() error {
+	aw.lock.Lock()
+	if !aw.closed {
+		aw.closed = true
+		close(aw.queue)
+	}
+	aw.lock.Unlock()
+	<-aw.done
+	return aw.takeError()
+}
+
+func (aw *asyncWriter) stats() AsyncStats {
+	return AsyncStats{
+		Written:       aw.written.Load(),
+		DroppedNewest: aw.droppedNewest.Load(),
+		DroppedOldest: aw.droppedOldest.Load(),
+		WriteErrors:   aw.writeErrors.Load(),
+	}
+}
+
+func (aw *asyncWriter) takeError() error {
+	aw.errLock.Lock()
+	defer aw.errLock.Unlock()
+	err := aw.err
+	aw.err = nil
+	return err
+}
@@ -0,0 +1,205 @@
+package flash
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/internal/json"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// gateWriter is an io.Writer that blocks every Write until the gate is opened.
+type gateWriter struct {
+	bytes.Buffer
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+	mutex   sync.Mutex
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{
+		gate:    make(chan struct{}),
+		started: make(chan struct{}),
+	}
+}
+
+func (gw *gateWriter) Write(p []byte) (int, error) {
+	gw.once.Do(func() { close(gw.started) })
+	<-gw.gate
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+	return gw.Buffer.Write(p)
+}
+
+func (gw *gateWriter) messages(t *testing.T) []string {
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(gw.Bytes()))
+	for scanner.Scan() {
+		logMap, err := json.Parse(scanner.Bytes())
+		require.NoError(t, err)
+		result = append(result, logMap[slog.MessageKey].(string))
+	}
+	return result
+}
+
+type errorWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (ew *errorWriter) Write(_ []byte) (int, error) {
+	return 0, errWrite
+}
+
+// -----------------------------------------------------------------------------
+
+func TestAsync(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, nil, &Extras{Async: &AsyncOptions{}})
+	logger := slog.New(hdlr)
+	logger.Info(test.Message, "count", 1)
+	logger.With("alpha", "omega").WithGroup("group").Info(test.Message, "count", 2)
+	require.NoError(t, hdlr.Flush())
+	assert.Equal(t, AsyncStats{Written: 2}, hdlr.Stats())
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte{'\n'})
+	require.Len(t, lines, 2)
+	logMap, err := json.Parse(lines[1])
+	require.NoError(t, err)
+	assert.Equal(t, "omega", logMap["alpha"])
+	assert.Equal(t, map[string]any{"count": float64(2)}, logMap["group"])
+	require.NoError(t, hdlr.Close())
+	assert.ErrorIs(t, hdlr.Handle(context.Background(), slog.NewRecord(test.Now, slog.LevelInfo, test.Message, 0)), ErrClosed)
+}
+
+func TestAsync_DerivedFlush(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, nil, &Extras{Async: &AsyncOptions{}})
+	derived := hdlr.WithGroup("group").WithAttrs([]slog.Attr{slog.Int("count", 1)})
+	slog.New(derived).Info(test.Message)
+	flusher, ok := derived.(interface{ Flush() error })
+	require.True(t, ok)
+	require.NoError(t, flusher.Flush())
+	assert.Contains(t, buffer.String(), test.Message)
+	require.NoError(t, hdlr.Close())
+}
+
+func TestAsync_DropNewest(t *testing.T) {
+	writer := newGateWriter()
+	hdlr := NewHandler(writer, nil, &Extras{
+		Async: &AsyncOptions{QueueDepth: 2, Overflow: OverflowDropNewest},
+	})
+	logger := slog.New(hdlr)
+	logger.Info("0")
+	<-writer.started // Record 0 is stuck in the writer.
+	for _, msg := range []string{"1", "2", "3", "4"} {
+		logger.Info(msg)
+	}
+	close(writer.gate)
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, []string{"0", "1", "2"}, writer.messages(t))
+	stats := hdlr.Stats()
+	assert.Equal(t, uint64(3), stats.Written)
+	assert.Equal(t, uint64(2), stats.DroppedNewest)
+	assert.Equal(t, uint64(2), stats.Dropped())
+}
+
+func TestAsync_DropOldest(t *testing.T) {
+	writer := newGateWriter()
+	hdlr := NewHandler(writer, nil, &Extras{
+		Async: &AsyncOptions{QueueDepth: 2, Overflow: OverflowDropOldest},
+	})
+	logger := slog.New(hdlr)
+	logger.Info("0")
+	<-writer.started // Record 0 is stuck in the writer.
+	for _, msg := range []string{"1", "2", "3", "4"} {
+		logger.Info(msg)
+	}
+	close(writer.gate)
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, []string{"0", "3", "4"}, writer.messages(t))
+	stats := hdlr.Stats()
+	assert.Equal(t, uint64(3), stats.Written)
+	assert.Equal(t, uint64(2), stats.DroppedOldest)
+	assert.Equal(t, uint64(2), stats.Dropped())
+}
+
+func TestAsync_DropOldestFlush(t *testing.T) {
+	writer := newGateWriter()
+	hdlr := NewHandler(writer, nil, &Extras{
+		Async: &AsyncOptions{QueueDepth: 3, Overflow: OverflowDropOldest},
+	})
+	logger := slog.New(hdlr)
+	logger.Info("0")
+	<-writer.started // Record 0 is stuck in the writer.
+	logger.Info("1")
+	flushed := make(chan error)
+	go func() { flushed <- hdlr.Flush() }()
+	require.Eventually(t, func() bool { return len(hdlr.async.queue) == 2 }, time.Second, time.Millisecond)
+	// The flush marker becomes the oldest queued item and must not be dropped.
+	for _, msg := range []string{"2", "3", "4"} {
+		logger.Info(msg)
+	}
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned before the queued records were written")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(writer.gate)
+	require.NoError(t, <-flushed)
+	assert.Equal(t, []string{"0", "3"}, writer.messages(t)[:2])
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, []string{"0", "3", "4"}, writer.messages(t))
+	assert.Equal(t, uint64(2), hdlr.Stats().DroppedOldest)
+}
+
+func TestAsync_Block(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, nil, &Extras{Async: &AsyncOptions{QueueDepth: 1}})
+	logger := slog.New(hdlr)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info(test.Message)
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, AsyncStats{Written: 1000}, hdlr.Stats())
+	assert.Equal(t, 1000, bytes.Count(buffer.Bytes(), []byte{'\n'}))
+}
+
+func TestAsync_WriteError(t *testing.T) {
+	hdlr := NewHandler(&errorWriter{}, nil, &Extras{Async: &AsyncOptions{}})
+	logger := slog.New(hdlr)
+	logger.Info(test.Message)
+	logger.Info(test.Message)
+	assert.ErrorIs(t, hdlr.Flush(), errWrite)
+	assert.NoError(t, hdlr.Flush())
+	assert.Equal(t, uint64(2), hdlr.Stats().WriteErrors)
+	require.NoError(t, hdlr.Close())
+}
+
+func TestSync_AsyncMethods(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, nil, nil)
+	slog.New(hdlr).Info(test.Message)
+	assert.NotEmpty(t, buffer.Bytes())
+	assert.NoError(t, hdlr.Flush())
+	assert.NoError(t, hdlr.Close())
+	assert.Equal(t, AsyncStats{}, hdlr.Stats())
+}
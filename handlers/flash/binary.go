@@ -0,0 +1,529 @@
+package flash
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
+)
+
+// This file contains the binary (CBOR and MessagePack) output formats for flash.Handler.
+//
+// Each log record is a single map data item.
+// Records are written back-to-back without any separator
+// as both formats are self-delimiting (e.g. an RFC 8742 CBOR sequence).
+// Groups are nested maps as with JSON output.
+//
+// Maps are written with fixed-width (32-bit) length headers that are filled in
+// when each map is closed so that WithAttrs and WithGroup output can be
+// composed ahead of time as with JSON output.
+// Values use the same TimeEncoding and DurationEncoding options as JSON output.
+// Values of kind slog.KindAny that have no string representation are converted
+// via json.Marshal into native maps, arrays, and scalar values.
+
+const (
+	cborMap32    = 0xba // Major type 5 (map), 32-bit length follows.
+	msgpackMap32 = 0xdf
+	lenMap32     = 5
+)
+
+// binaryMap tracks an open map during binary output.
+type binaryMap struct {
+	// header is the offset of the map length header.
+	header int
+	// key is the offset of the map key in the enclosing map
+	// if the map is to be removed when it is empty, otherwise -1.
+	key int
+	// count is the number of key/value pairs in the map so far.
+	count int
+}
+
+// -----------------------------------------------------------------------------
+// Handler methods for binary output.
+
+// composeBinary composes a log record into the composer as a single map data item.
+func (h *Handler) composeBinary(c *composer, ctx context.Context, record slog.Record) error {
+	c.maps = append(c.maps[:0], binaryMap{header: len(c.buffer), key: -1})
+	c.addMapBinary()
+	if !record.Time.IsZero() {
+		if err := c.addAttributeBinary(slog.Time(h.extras.TimeKey, record.Time)); err != nil {
+			return fmt.Errorf("add time: %w", err)
+		}
+	}
+	var level slog.Attr
+	if h.extras.LevelNumeric {
+		level = slog.Int(h.extras.LevelKey, int(record.Level))
+	} else {
+		level = slog.String(h.extras.LevelKey, h.extras.levelName(record.Level))
+	}
+	if err := c.addAttributeBinary(level); err != nil {
+		return fmt.Errorf("add level: %w", err)
+	}
+	if h.extras.LevelNumberKey != "" {
+		number := int(record.Level) + h.extras.LevelNumberOffset
+		if err := c.addAttributeBinary(slog.Int(h.extras.LevelNumberKey, number)); err != nil {
+			return fmt.Errorf("add level number: %w", err)
+		}
+	}
+	message := h.extras.Limits.Message(record.Message)
+	if err := c.addAttributeBinary(slog.String(h.extras.MessageKey, message)); err != nil {
+		return fmt.Errorf("add message: %w", err)
+	}
+	if h.options.AddSource && record.PC != 0 {
+		var src source
+		loadSource(record.PC, &src)
+		if err := c.addAttributeBinary(slog.Any(h.extras.SourceKey, &src)); err != nil {
+			return fmt.Errorf("add source: %w", err)
+		}
+	}
+	if err := h.addContext(c, ctx, c.addAttributesBinary); err != nil {
+		return err
+	}
+
+	// The prefix offsets are relative to the start of the prefix.
+	// The first prefix map is the top level map of the record.
+	base := len(c.buffer)
+	c.buffer = append(c.buffer, h.prefix...)
+	for i, m := range h.binMaps {
+		if i == 0 {
+			c.maps[0].count += m.count
+		} else {
+			c.maps = append(c.maps, binaryMap{header: base + m.header, key: base + m.key, count: m.count})
+		}
+	}
+
+	if err := h.addRecord(record, c.addAttributeBinary); err != nil {
+		return err
+	}
+	for len(c.maps) > 0 {
+		c.closeMapBinary()
+	}
+	return nil
+}
+
+func (h *Handler) withAttrsBinary(attrs []slog.Attr) slog.Handler {
+	hdlr := h.cloneBinary()
+	c := newComposer(hdlr.prefix, true, h.options.ReplaceAttr, h.groups, h.extras)
+	defer reuseComposer(c)
+	c.maps = append(c.maps[:0], hdlr.binMaps...)
+	if err := c.addAttributesBinary(attrs); err != nil {
+		slog.Error("adding with attributes", "err", err)
+	}
+	hdlr.prefix = c.getBytes()
+	hdlr.binMaps = append(hdlr.binMaps[:0], c.maps...)
+	return hdlr
+}
+
+func (h *Handler) withGroupBinary(name string) slog.Handler {
+	hdlr := h.cloneBinary()
+	if h.options.ReplaceAttr != nil {
+		hdlr.groups = append(h.groups, name)
+	}
+	// The group is counted in the enclosing map now
+	// and removed again when the record is composed if it is still empty.
+	key := len(hdlr.prefix)
+	hdlr.binMaps[len(hdlr.binMaps)-1].count++
+	hdlr.prefix = appendStringBinary(hdlr.prefix, h.extras.Format, name)
+	hdlr.binMaps = append(hdlr.binMaps, binaryMap{header: len(hdlr.prefix), key: key})
+	hdlr.prefix = appendMapBinary(hdlr.prefix, h.extras.Format)
+	return hdlr
+}
+
+// cloneBinary returns a copy of the handler with its own copies of the prefix and open maps
+// so that sibling handlers don't share the arrays.
+func (h *Handler) cloneBinary() *Handler {
+	hdlr := &Handler{
+		options: h.options,
+		extras:  h.extras,
+		writer:  h.writer,
+		mutex:   h.mutex,
+		async:   h.async,
+		groups:  h.groups,
+		prefix:  make([]byte, len(h.prefix), len(h.prefix)+lenPrefix),
+		binMaps: make([]binaryMap, len(h.binMaps), len(h.binMaps)+1),
+	}
+	copy(hdlr.prefix, h.prefix)
+	copy(hdlr.binMaps, h.binMaps)
+	if len(hdlr.binMaps) < 1 {
+		// The top level map of the record.
+		hdlr.binMaps = append(hdlr.binMaps, binaryMap{header: -1, key: -1})
+	}
+	return hdlr
+}
+
+// -----------------------------------------------------------------------------
+// Composer methods for binary output.
+
+func (c *composer) addAttributeBinary(attr slog.Attr) error {
+	kind := attr.Value.Kind()
+	if kind == slog.KindLogValuer {
+		if !c.extras.Limits.Resolve(c.resolves + 1) {
+			attr.Value = slog.StringValue(limit.ResolveExceeded)
+		} else {
+			attr.Value = attr.Value.Resolve()
+			c.resolves++
+			defer func() { c.resolves-- }()
+		}
+		kind = attr.Value.Kind()
+	}
+	if c.replace != nil {
+		var groups []string
+		if !c.basicField[attr.Key] {
+			groups = c.groups
+		}
+		attr = c.replace(groups, attr)
+		kind = attr.Value.Kind()
+	}
+	if attr.Equal(infra.EmptyAttr()) {
+		return nil
+	}
+	value := attr.Value
+	if kind == slog.KindGroup {
+		if !c.extras.Limits.Group(c.depth + 1) {
+			value = slog.StringValue(limit.DepthExceeded)
+			kind = slog.KindString
+		} else if emptyGroup(value.Group(), c.extras.Limits.GroupRemaining(c.depth+1)) {
+			return nil
+		} else if attr.Key == "" {
+			c.depth++
+			err := c.addAttributesBinary(value.Group())
+			c.depth--
+			if err != nil {
+				return fmt.Errorf("inline group attributes: %w", err)
+			}
+			return nil
+		}
+	}
+	c.maps[len(c.maps)-1].count++
+	c.addStringBinary(attr.Key)
+	switch kind {
+	case slog.KindGroup:
+		c.maps = append(c.maps, binaryMap{header: len(c.buffer), key: -1})
+		c.addMapBinary()
+		c.depth++
+		err := c.addAttributesBinary(value.Group())
+		c.depth--
+		c.closeMapBinary()
+		if err != nil {
+			return fmt.Errorf("add group attributes: %w", err)
+		}
+	case slog.KindBool:
+		c.buffer = appendBoolBinary(c.buffer, c.extras.Format, value.Bool())
+	case slog.KindDuration:
+		c.addDurationBinary(value.Duration())
+	case slog.KindFloat64:
+		c.buffer = appendFloatBinary(c.buffer, c.extras.Format, value.Float64())
+	case slog.KindInt64:
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, value.Int64())
+	case slog.KindString:
+		c.addStringBinary(c.limitValue(attr.Key, value.String()))
+	case slog.KindTime:
+		c.addTimeBinary(value.Time())
+	case slog.KindUint64:
+		c.buffer = appendUintBinary(c.buffer, c.extras.Format, value.Uint64())
+	case slog.KindAny:
+		fallthrough
+	default:
+		return c.addAnyBinary(value.Any())
+	}
+	return nil
+}
+
+func (c *composer) addAttributesBinary(attrs []slog.Attr) error {
+	for _, attr := range attrs {
+		if err := c.addAttributeBinary(attr); err != nil {
+			return fmt.Errorf("add attribute '%s': %w", attr.String(), err)
+		}
+	}
+	return nil
+}
+
+func (c *composer) addAnyBinary(a any) (err error) {
+	mark := len(c.buffer)
+	defer func() {
+		if r := recover(); r != nil {
+			// Discard any partial output and report the panic as the value.
+			c.buffer = c.buffer[:mark]
+			c.addStringBinary(panicString(a, r))
+			err = nil
+		}
+	}()
+	switch v := a.(type) {
+	case *source:
+		c.buffer = appendMapHeaderBinary(c.buffer, c.extras.Format, 3)
+		c.addStringBinary("function")
+		c.addStringBinary(v.Function)
+		c.addStringBinary("file")
+		c.addStringBinary(v.File)
+		c.addStringBinary("line")
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, int64(v.Line))
+	case fmt.Stringer:
+		c.addStringBinary(c.extras.Limits.Value(v.String()))
+	case error:
+		c.addStringBinary(c.extras.Limits.Value(v.Error()))
+	case json.Marshaler:
+		if txt, err := v.MarshalJSON(); err != nil {
+			c.addStringBinary("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal JSON: %w", err)
+		} else {
+			c.addStringBinary(c.extras.Limits.Value(string(txt)))
+		}
+	case encoding.TextMarshaler:
+		if txt, err := v.MarshalText(); err != nil {
+			c.addStringBinary("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal text: %w", err)
+		} else {
+			c.addStringBinary(c.extras.Limits.Value(string(txt)))
+		}
+	default:
+		// Composite values are converted to their JSON structure so that
+		// field names and omitted fields match JSON output.
+		var decoded any
+		if b, err := json.Marshal(a); err != nil {
+			c.addStringBinary("!ERROR:" + err.Error())
+		} else if c.extras.Limits.ValueTooLong(len(b)) {
+			c.addStringBinary(c.extras.Limits.Value(string(b)))
+		} else if err := unmarshalNumbers(b, &decoded); err != nil {
+			c.addStringBinary("!ERROR:" + err.Error())
+		} else {
+			c.addDecodedBinary(decoded)
+		}
+	}
+	return nil
+}
+
+// addDecodedBinary adds a value decoded from JSON via unmarshalNumbers.
+func (c *composer) addDecodedBinary(value any) {
+	switch v := value.(type) {
+	case nil:
+		c.buffer = append(c.buffer, nilBinary(c.extras.Format))
+	case bool:
+		c.buffer = appendBoolBinary(c.buffer, c.extras.Format, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			c.buffer = appendIntBinary(c.buffer, c.extras.Format, i)
+		} else if f, err := v.Float64(); err == nil {
+			c.buffer = appendFloatBinary(c.buffer, c.extras.Format, f)
+		} else {
+			c.addStringBinary(v.String())
+		}
+	case string:
+		c.addStringBinary(v)
+	case []any:
+		c.buffer = appendArrayHeaderBinary(c.buffer, c.extras.Format, len(v))
+		for _, item := range v {
+			c.addDecodedBinary(item)
+		}
+	case map[string]any:
+		c.buffer = appendMapHeaderBinary(c.buffer, c.extras.Format, len(v))
+		for key, item := range v {
+			c.addStringBinary(key)
+			c.addDecodedBinary(item)
+		}
+	}
+}
+
+// addMapBinary adds a map header to be filled in by closeMapBinary.
+func (c *composer) addMapBinary() {
+	c.buffer = appendMapBinary(c.buffer, c.extras.Format)
+}
+
+// closeMapBinary fills in the length header of the innermost open map.
+// Empty maps opened by WithGroup are removed along with their keys.
+func (c *composer) closeMapBinary() {
+	last := len(c.maps) - 1
+	m := c.maps[last]
+	c.maps = c.maps[:last]
+	if m.count == 0 && m.key >= 0 {
+		c.buffer = c.buffer[:m.key]
+		c.maps[last-1].count--
+		return
+	}
+	binary.BigEndian.PutUint32(c.buffer[m.header+1:m.header+lenMap32], uint32(m.count))
+}
+
+func (c *composer) addStringBinary(str string) {
+	c.buffer = appendStringBinary(c.buffer, c.extras.Format, str)
+}
+
+func (c *composer) addDurationBinary(d time.Duration) {
+	switch c.extras.DurationEncoding {
+	case DurationMillis:
+		c.buffer = appendFloatBinary(c.buffer, c.extras.Format, float64(d)/float64(time.Millisecond))
+	case DurationSeconds:
+		c.buffer = appendFloatBinary(c.buffer, c.extras.Format, float64(d)/float64(time.Second))
+	case DurationString:
+		c.addStringBinary(d.String())
+	default:
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, d.Nanoseconds())
+	}
+}
+
+func (c *composer) addTimeBinary(t time.Time) {
+	switch c.extras.TimeEncoding {
+	case TimeUnixSeconds:
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, t.Unix())
+	case TimeUnixMillis:
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, t.UnixMilli())
+	case TimeUnixNanos:
+		c.buffer = appendIntBinary(c.buffer, c.extras.Format, t.UnixNano())
+	default:
+		var array [64]byte
+		c.buffer = appendStringBinary(c.buffer, c.extras.Format, string(t.AppendFormat(array[:0], c.extras.TimeFormat)))
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Data item encoding.
+
+// appendMapBinary appends a map header with a 32-bit length to be filled in later.
+func appendMapBinary(buffer []byte, format Format) []byte {
+	if format == FormatMsgPack {
+		return append(buffer, msgpackMap32, 0, 0, 0, 0)
+	}
+	return append(buffer, cborMap32, 0, 0, 0, 0)
+}
+
+func appendMapHeaderBinary(buffer []byte, format Format, length int) []byte {
+	if format == FormatMsgPack {
+		if length < 16 {
+			return append(buffer, 0x80|byte(length))
+		} else if length <= math.MaxUint16 {
+			return binary.BigEndian.AppendUint16(append(buffer, 0xde), uint16(length))
+		}
+		return binary.BigEndian.AppendUint32(append(buffer, 0xdf), uint32(length))
+	}
+	return appendHeadCBOR(buffer, 5, uint64(length))
+}
+
+func appendArrayHeaderBinary(buffer []byte, format Format, length int) []byte {
+	if format == FormatMsgPack {
+		if length < 16 {
+			return append(buffer, 0x90|byte(length))
+		} else if length <= math.MaxUint16 {
+			return binary.BigEndian.AppendUint16(append(buffer, 0xdc), uint16(length))
+		}
+		return binary.BigEndian.AppendUint32(append(buffer, 0xdd), uint32(length))
+	}
+	return appendHeadCBOR(buffer, 4, uint64(length))
+}
+
+func appendBoolBinary(buffer []byte, format Format, b bool) []byte {
+	switch {
+	case format == FormatMsgPack && b:
+		return append(buffer, 0xc3)
+	case format == FormatMsgPack:
+		return append(buffer, 0xc2)
+	case b:
+		return append(buffer, 0xf5)
+	default:
+		return append(buffer, 0xf4)
+	}
+}
+
+func nilBinary(format Format) byte {
+	if format == FormatMsgPack {
+		return 0xc0
+	}
+	return 0xf6
+}
+
+func appendFloatBinary(buffer []byte, format Format, f float64) []byte {
+	if format == FormatMsgPack {
+		buffer = append(buffer, 0xcb)
+	} else {
+		buffer = append(buffer, 0xfb)
+	}
+	return binary.BigEndian.AppendUint64(buffer, math.Float64bits(f))
+}
+
+func appendIntBinary(buffer []byte, format Format, i int64) []byte {
+	if i >= 0 {
+		return appendUintBinary(buffer, format, uint64(i))
+	}
+	if format == FormatMsgPack {
+		switch {
+		case i >= -32:
+			return append(buffer, byte(i))
+		case i >= math.MinInt8:
+			return append(buffer, 0xd0, byte(i))
+		case i >= math.MinInt16:
+			return binary.BigEndian.AppendUint16(append(buffer, 0xd1), uint16(i))
+		case i >= math.MinInt32:
+			return binary.BigEndian.AppendUint32(append(buffer, 0xd2), uint32(i))
+		default:
+			return binary.BigEndian.AppendUint64(append(buffer, 0xd3), uint64(i))
+		}
+	}
+	// CBOR negative integers are encoded as -1 - n.
+	return appendHeadCBOR(buffer, 1, uint64(-1-i))
+}
+
+func appendUintBinary(buffer []byte, format Format, u uint64) []byte {
+	if format == FormatMsgPack {
+		switch {
+		case u < 0x80:
+			return append(buffer, byte(u))
+		case u <= math.MaxUint8:
+			return append(buffer, 0xcc, byte(u))
+		case u <= math.MaxUint16:
+			return binary.BigEndian.AppendUint16(append(buffer, 0xcd), uint16(u))
+		case u <= math.MaxUint32:
+			return binary.BigEndian.AppendUint32(append(buffer, 0xce), uint32(u))
+		default:
+			return binary.BigEndian.AppendUint64(append(buffer, 0xcf), u)
+		}
+	}
+	return appendHeadCBOR(buffer, 0, u)
+}
+
+// appendStringBinary appends a UTF-8 string.
+// Invalid UTF-8 sequences are replaced as both formats require valid UTF-8 strings.
+func appendStringBinary(buffer []byte, format Format, str string) []byte {
+	if !utf8.ValidString(str) {
+		str = strings.ToValidUTF8(str, string(utf8.RuneError))
+	}
+	length := len(str)
+	if format == FormatMsgPack {
+		switch {
+		case length < 32:
+			buffer = append(buffer, 0xa0|byte(length))
+		case length <= math.MaxUint8:
+			buffer = append(buffer, 0xd9, byte(length))
+		case length <= math.MaxUint16:
+			buffer = binary.BigEndian.AppendUint16(append(buffer, 0xda), uint16(length))
+		default:
+			buffer = binary.BigEndian.AppendUint32(append(buffer, 0xdb), uint32(length))
+		}
+	} else {
+		buffer = appendHeadCBOR(buffer, 3, uint64(length))
+	}
+	return append(buffer, str...)
+}
+
+// appendHeadCBOR appends the initial bytes of a CBOR data item
+// with the specified major type and argument.
+func appendHeadCBOR(buffer []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buffer, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(buffer, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buffer, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buffer, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(buffer, major|27), arg)
+	}
+}
@@ -0,0 +1,141 @@
+package flash
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/internal/bincode"
+	"github.com/madkins23/go-slog/internal/json"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+var binaryFormats = map[Format]bincode.Format{
+	FormatCBOR:    bincode.CBOR,
+	FormatMsgPack: bincode.MsgPack,
+}
+
+type binaryValuer struct{}
+
+func (bv binaryValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("resolved", "yes"))
+}
+
+// binaryCase configures a handler and logs records to it.
+type binaryCase struct {
+	name    string
+	options *slog.HandlerOptions
+	logFn   func(logger *slog.Logger)
+}
+
+var binaryCases = []binaryCase{
+	{
+		name: "kinds",
+		logFn: func(logger *slog.Logger) {
+			logger.Info(test.Message,
+				"int", -23, "uint", uint64(1<<40), "float", 3.5, "bool", true, "string", "Hello",
+				"duration", time.Second, "time", test.Now, "error", errors.New("failed"),
+				"map", map[string]int{"x": 1}, "array", []string{"a", "b"}, "nil", nil,
+				"struct", struct {
+					Name  string `json:"name"`
+					Count int    `json:"count"`
+				}{"name", 3},
+				"group", slog.GroupValue(slog.Int("a", 1), slog.Group("b", slog.Int("c", 2))),
+				"valuer", binaryValuer{}, "invalid", "bad\xffutf8")
+		},
+	},
+	{
+		name: "with",
+		logFn: func(logger *slog.Logger) {
+			with := logger.With("alpha", 1).WithGroup("g1").With("bravo", 2).WithGroup("g2")
+			with.Info("first", "charlie", 3)
+			with.Info("empty")
+			with.WithGroup("g3").Info("empty group")
+			logger.WithGroup("e1").WithGroup("e2").Info("all empty")
+			// Siblings from the same parent must not share prefix data.
+			logger.With("delta", 4).Info("sibling", "echo", 5)
+		},
+	},
+	{
+		name:    "replace",
+		options: &slog.HandlerOptions{ReplaceAttr: noTime, AddSource: true, Level: slog.LevelDebug},
+		logFn: func(logger *slog.Logger) {
+			logger.WithGroup("group").With("alpha", 1).Debug(test.Message, "bravo", 2)
+		},
+	},
+}
+
+func TestBinary(t *testing.T) {
+	for _, bc := range binaryCases {
+		var jsonBuffer bytes.Buffer
+		bc.logFn(slog.New(NewHandler(&jsonBuffer, bc.options, nil)))
+		expected := bytes.Split(bytes.TrimSpace(jsonBuffer.Bytes()), []byte{'\n'})
+		for format, decode := range binaryFormats {
+			t.Run(bc.name+"/"+format.String(), func(t *testing.T) {
+				var buffer bytes.Buffer
+				bc.logFn(slog.New(NewHandler(&buffer, bc.options, &Extras{Format: format})))
+				data := buffer.Bytes()
+				for _, line := range expected {
+					expectMap, err := json.Parse(line)
+					require.NoError(t, err)
+					actual, rest, err := bincode.ToJSON(decode, data)
+					require.NoError(t, err)
+					actualMap, err := json.Parse(actual)
+					require.NoError(t, err)
+					// Records are logged at different times.
+					_, found := expectMap[slog.TimeKey]
+					assert.Equal(t, found, actualMap[slog.TimeKey] != nil)
+					delete(expectMap, slog.TimeKey)
+					delete(actualMap, slog.TimeKey)
+					assert.Equal(t, expectMap, actualMap)
+					data = rest
+				}
+				assert.Empty(t, data)
+			})
+		}
+	}
+}
+
+func TestBinary_Duplicates(t *testing.T) {
+	for format, decode := range binaryFormats {
+		var buffer bytes.Buffer
+		hdlr := NewHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: noTime}, &Extras{Format: format})
+		record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+		record.AddAttrs(slog.Int("a", 1), slog.Int("a", 2))
+		require.NoError(t, hdlr.Handle(context.Background(), record))
+		actual, rest, err := bincode.ToJSON(decode, buffer.Bytes())
+		require.NoError(t, err)
+		assert.Empty(t, rest)
+		assert.Equal(t, `{"level":"INFO","msg":"message","a":1,"a":2}`, string(actual), format.String())
+	}
+}
+
+func TestBinary_Encoding(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: noTime}, &Extras{Format: FormatCBOR})
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "x", 0)
+	record.AddAttrs(slog.Int("n", -2))
+	require.NoError(t, hdlr.Handle(context.Background(), record))
+	// Map headers always use a 32-bit length.
+	assert.Equal(t, []byte{
+		0xba, 0, 0, 0, 3,
+		0x65, 'l', 'e', 'v', 'e', 'l', 0x64, 'I', 'N', 'F', 'O',
+		0x63, 'm', 's', 'g', 0x61, 'x',
+		0x61, 'n', 0x21,
+	}, buffer.Bytes())
+	buffer.Reset()
+	hdlr = NewHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: noTime}, &Extras{Format: FormatMsgPack})
+	require.NoError(t, hdlr.Handle(context.Background(), record))
+	assert.Equal(t, []byte{
+		0xdf, 0, 0, 0, 3,
+		0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'I', 'N', 'F', 'O',
+		0xa3, 'm', 's', 'g', 0xa1, 'x',
+		0xa1, 'n', 0xfe,
+	}, buffer.Bytes())
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
 )
 
 var composerPool = newGenPool[composer]()
@@ -23,6 +25,19 @@ type composer struct {
 	groups     []string
 	extras     *Extras
 	basicField map[string]bool
+
+	// depth and resolves track nested groups and slog.LogValuer values for limit.Options.
+	depth    int
+	resolves int
+
+	// dedup tracks the keys in each open JSON object for Extras.Dedup.
+	dedup dedupKeys
+
+	// keyPrefix holds dotted group names during text output.
+	keyPrefix []byte
+
+	// maps tracks the open maps during binary output.
+	maps []binaryMap
 }
 
 func newComposer(buffer []byte, started bool, replace infra.AttrFn, groups []string, extras *Extras) *composer {
@@ -32,11 +47,22 @@ func newComposer(buffer []byte, started bool, replace infra.AttrFn, groups []str
 	comp.started = started
 	comp.replace = replace
 	comp.groups = groups
+	comp.depth = 0
+	comp.resolves = 0
+	comp.dedup.reset()
 	comp.basicField = make(map[string]bool, 4)
 	comp.basicField[extras.LevelKey] = true
 	comp.basicField[extras.MessageKey] = true
 	comp.basicField[extras.SourceKey] = true
 	comp.basicField[extras.TimeKey] = true
+	if extras.LevelNumberKey != "" {
+		comp.basicField[extras.LevelNumberKey] = true
+	}
+	if extras.SourceShape == SourceOTel || extras.otlp {
+		comp.basicField[OTelFunctionKey] = true
+		comp.basicField[OTelFileKey] = true
+		comp.basicField[OTelLineKey] = true
+	}
 	return comp
 }
 
@@ -57,7 +83,14 @@ func (c *composer) getBytes() []byte {
 func (c *composer) addAttribute(attr slog.Attr) error {
 	kind := attr.Value.Kind()
 	if kind == slog.KindLogValuer {
-		attr.Value = attr.Value.Resolve()
+		if !c.extras.Limits.Resolve(c.resolves + 1) {
+			attr.Value = slog.StringValue(limit.ResolveExceeded)
+		} else {
+			attr.Value = attr.Value.Resolve()
+			c.resolves++
+			defer func() { c.resolves-- }()
+		}
+		kind = attr.Value.Kind()
 	}
 	if c.replace != nil {
 		var groups []string
@@ -72,18 +105,32 @@ func (c *composer) addAttribute(attr slog.Attr) error {
 	}
 	value := attr.Value
 	if kind == slog.KindGroup {
-		if emptyGroup(value.Group()) {
+		if !c.extras.Limits.Group(c.depth + 1) {
+			value = slog.StringValue(limit.DepthExceeded)
+			kind = slog.KindString
+		} else if emptyGroup(value.Group(), c.extras.Limits.GroupRemaining(c.depth+1)) {
 			return nil
-		}
-		if attr.Key == "" {
-			if err := c.addAttributes(value.Group()); err != nil {
+		} else if attr.Key == "" {
+			c.depth++
+			err := c.addAttributes(value.Group())
+			c.depth--
+			if err != nil {
 				return fmt.Errorf("inline group attributes: %w", err)
 			}
 			return nil
 		}
 	}
+	if c.dedup.active() {
+		return c.addDedup(attr.Key, value, kind)
+	}
 	c.addSeparator()
 	c.addKey(attr.Key)
+	return c.addValue(attr.Key, value, kind)
+}
+
+// addValue adds an attribute value of the specified kind after its key.
+// The attribute key is used to determine whether the value is subject to limits.
+func (c *composer) addValue(key string, value slog.Value, kind slog.Kind) error {
 	switch kind {
 	case slog.KindGroup:
 		return c.addGroup(value.Group())
@@ -94,13 +141,13 @@ func (c *composer) addAttribute(attr slog.Attr) error {
 			c.buffer = append(c.buffer, "false"...)
 		}
 	case slog.KindDuration:
-		c.buffer = strconv.AppendInt(c.buffer, value.Duration().Nanoseconds(), 10)
+		c.addDuration(value.Duration())
 	case slog.KindFloat64:
 		c.buffer = strconv.AppendFloat(c.buffer, value.Float64(), 'f', -1, 64)
 	case slog.KindInt64:
 		c.buffer = strconv.AppendInt(c.buffer, value.Int64(), 10)
 	case slog.KindString:
-		c.addString(value.String())
+		c.addString(c.limitValue(key, value.String()))
 	case slog.KindTime:
 		c.addTime(value.Time())
 	case slog.KindUint64:
@@ -124,12 +171,29 @@ func (c *composer) addAttributes(attrs []slog.Attr) error {
 
 // -----------------------------------------------------------------------------
 
-func (c *composer) addAny(a any) error {
+func (c *composer) addAny(a any) (err error) {
+	mark := len(c.buffer)
+	defer func() {
+		if r := recover(); r != nil {
+			// Discard any partial output and report the panic as the value.
+			c.buffer = c.buffer[:mark]
+			c.addString(panicString(a, r))
+			err = nil
+		}
+	}()
+	if src, ok := a.(*source); ok && c.addSourceValue(src) {
+		return nil
+	}
+	if fn := c.extras.Encoders.lookup(a); fn != nil {
+		c.buffer = fn(c.buffer, a)
+		c.limitEncoded(mark)
+		return nil
+	}
 	switch v := a.(type) {
 	case fmt.Stringer:
-		c.addString(v.String())
+		c.addString(c.extras.Limits.Value(v.String()))
 	case error:
-		c.addString(v.Error())
+		c.addString(c.extras.Limits.Value(v.Error()))
 	case json.Marshaler:
 		return c.addJSONMarshaler(v)
 	case encoding.TextMarshaler:
@@ -139,15 +203,35 @@ func (c *composer) addAny(a any) error {
 		// Note: Important stuff buried in some random structure may be ignored.
 		//       For example, a LogValuer or a Stringer might show up as an empty map.
 		if b, err := json.Marshal(a); err != nil {
-			return fmt.Errorf("marshal %v: %w", a, err)
+			// Report the error as the value (e.g. a self-referential value) as does slog.JSONHandler.
+			c.addString("!ERROR:" + err.Error())
 		} else {
 			c.buffer = append(c.buffer, b...)
-			return nil
+			c.limitEncoded(mark)
 		}
 	}
 	return nil
 }
 
+// limitValue returns the specified string value truncated to the maximum value length.
+// Basic fields are not attribute values, the message is limited separately.
+func (c *composer) limitValue(key, str string) string {
+	if c.basicField[key] {
+		return str
+	}
+	return c.extras.Limits.Value(str)
+}
+
+// limitEncoded replaces an encoded value appended to the buffer since the mark
+// with a truncated string if it exceeds the maximum value length.
+func (c *composer) limitEncoded(mark int) {
+	if c.extras.Limits.ValueTooLong(len(c.buffer) - mark) {
+		encoded := string(c.buffer[mark:])
+		c.buffer = c.buffer[:mark]
+		c.addString(c.extras.Limits.Value(encoded))
+	}
+}
+
 func (c *composer) addBytes(b ...byte) {
 	c.buffer = append(c.buffer, b...)
 }
@@ -172,6 +256,11 @@ var hexDigit = `0123456789ABCDEF`
 // Any changes from the source are the fault the author of this method and
 // should not reflect on the source material. ;-)
 func (c *composer) addEscaped(s []byte) {
+	c.buffer = appendEscaped(c.buffer, s)
+}
+
+// appendEscaped appends the specified byte array to the buffer as with composer.addEscaped.
+func appendEscaped(buffer []byte, s []byte) []byte {
 	var b byte
 	var begin, index int
 	uniByte := make([]byte, 0, 4)
@@ -183,7 +272,7 @@ func (c *composer) addEscaped(s []byte) {
 			if uniMore < 1 {
 				// All unicode bytes collected in uniByte array.
 				// When they are all collected push them out.
-				c.buffer = append(c.buffer, uniByte...)
+				buffer = append(buffer, uniByte...)
 				uniByte = uniByte[:0]
 				begin = index + 1
 			}
@@ -193,39 +282,39 @@ func (c *composer) addEscaped(s []byte) {
 		switch b {
 		case '\\', '/', '"':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', b)
+			buffer = append(buffer, '\\', b)
 			begin = index + 1
 		case '\b':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', 'b')
+			buffer = append(buffer, '\\', 'b')
 			begin = index + 1
 		case '\f':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', 'f')
+			buffer = append(buffer, '\\', 'f')
 			begin = index + 1
 		case '\n':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', 'n')
+			buffer = append(buffer, '\\', 'n')
 			begin = index + 1
 		case '\r':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', 'r')
+			buffer = append(buffer, '\\', 'r')
 			begin = index + 1
 		case '\t':
 			if index > begin {
-				c.buffer = append(c.buffer, s[begin:index]...)
+				buffer = append(buffer, s[begin:index]...)
 			}
-			c.buffer = append(c.buffer, '\\', 't')
+			buffer = append(buffer, '\\', 't')
 			begin = index + 1
 		default:
 			if b >= 32 && b < 127 {
@@ -233,32 +322,32 @@ func (c *composer) addEscaped(s []byte) {
 			} else if b&0b11100000 == 0b11000000 {
 				// UTF8 two bytes
 				if index > begin {
-					c.buffer = append(c.buffer, s[begin:index]...)
+					buffer = append(buffer, s[begin:index]...)
 				}
 				uniByte = append(uniByte, b)
 				uniMore = 1
 			} else if b&0b11110000 == 0b11100000 {
 				// UTF8 three bytes
 				if index > begin {
-					c.buffer = append(c.buffer, s[begin:index]...)
+					buffer = append(buffer, s[begin:index]...)
 				}
 				uniByte = append(uniByte, b)
 				uniMore = 2
 			} else if b&0b11111000 == 0b11110000 {
 				// UTF8 four bytes
 				if index > begin {
-					c.buffer = append(c.buffer, s[begin:index]...)
+					buffer = append(buffer, s[begin:index]...)
 				}
 				uniByte = append(uniByte, b)
 				uniMore = 3
 			} else if b < 128 {
 				// Control character from lower 7 bits not previously handled.
 				if index > begin {
-					c.buffer = append(c.buffer, s[begin:index]...)
+					buffer = append(buffer, s[begin:index]...)
 				}
-				c.buffer = append(c.buffer, `\u00`...)
-				c.buffer = append(c.buffer, hexDigit[b>>4])
-				c.buffer = append(c.buffer, hexDigit[b&0xF])
+				buffer = append(buffer, `\u00`...)
+				buffer = append(buffer, hexDigit[b>>4])
+				buffer = append(buffer, hexDigit[b&0xF])
 				begin = index + 1
 			} else {
 				// Some character from upper 7 bits not previously handled but likely printable.
@@ -266,15 +355,21 @@ func (c *composer) addEscaped(s []byte) {
 		}
 	}
 	if index >= begin && index < len(s) {
-		c.buffer = append(c.buffer, s[begin:index+1]...)
+		buffer = append(buffer, s[begin:index+1]...)
 	}
+	return buffer
 }
 
 func (c *composer) addGroup(attrs []slog.Attr) error {
 	var err error
 	c.buffer = append(c.buffer, '{')
 	c.reset() // Reset composer (started = false) to avoid comma.
-	if err = c.addAttributes(attrs); err != nil {
+	c.depth++
+	c.dedupEnter()
+	err = c.addAttributes(attrs)
+	c.dedupLeave()
+	c.depth--
+	if err != nil {
 		return fmt.Errorf("add attributes: %w", err)
 	}
 	c.addBytes('}')
@@ -292,7 +387,7 @@ func (c *composer) addJSONMarshaler(m json.Marshaler) error {
 		c.addString("!ERROR:" + err.Error())
 		return fmt.Errorf("marshal JSON: %w", err)
 	} else {
-		c.addString(string(txt))
+		c.addString(c.extras.Limits.Value(string(txt)))
 		return nil
 	}
 }
@@ -309,12 +404,24 @@ func (c *composer) addTextMarshaler(m encoding.TextMarshaler) error {
 		c.addString("!ERROR:" + err.Error())
 		return fmt.Errorf("marshal text: %w", err)
 	} else {
-		c.addString(string(txt))
+		c.addString(c.extras.Limits.Value(string(txt)))
 		return nil
 	}
 }
 
+func (c *composer) addDuration(d time.Duration) {
+	if c.extras.DurationEncoding == DurationString {
+		c.addString(d.String())
+		return
+	}
+	c.buffer = appendDuration(c.buffer, d, c.extras.DurationEncoding)
+}
+
 func (c *composer) addTime(t time.Time) {
+	if c.extras.TimeEncoding != TimeLayout {
+		c.buffer = appendUnixTime(c.buffer, t, c.extras.TimeEncoding)
+		return
+	}
 	c.buffer = append(c.buffer, '"')
 	c.buffer = t.AppendFormat(c.buffer, c.extras.TimeFormat)
 	c.buffer = append(c.buffer, '"')
@@ -330,13 +437,44 @@ func (c *composer) addSeparator() {
 
 // -----------------------------------------------------------------------------
 
-func emptyGroup(attrs []slog.Attr) bool {
+// appendDuration appends a numeric duration per the specified encoding.
+// DurationString must be handled by the caller as quoting varies by output format.
+func appendDuration(buffer []byte, d time.Duration, encoding DurationEncoding) []byte {
+	switch encoding {
+	case DurationMillis:
+		return strconv.AppendFloat(buffer, float64(d)/float64(time.Millisecond), 'f', -1, 64)
+	case DurationSeconds:
+		return strconv.AppendFloat(buffer, float64(d)/float64(time.Second), 'f', -1, 64)
+	default:
+		return strconv.AppendInt(buffer, d.Nanoseconds(), 10)
+	}
+}
+
+// appendUnixTime appends a numeric time per the specified encoding.
+// TimeLayout must be handled by the caller as quoting varies by output format.
+func appendUnixTime(buffer []byte, t time.Time, encoding TimeEncoding) []byte {
+	switch encoding {
+	case TimeUnixSeconds:
+		return strconv.AppendInt(buffer, t.Unix(), 10)
+	case TimeUnixMillis:
+		return strconv.AppendInt(buffer, t.UnixMilli(), 10)
+	default:
+		return strconv.AppendInt(buffer, t.UnixNano(), 10)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// emptyGroup returns true if the group attributes would not generate any output.
+// Nested groups are checked to the specified number of levels (-1 for no limit).
+// Deeper groups are not empty as they are logged as limit.DepthExceeded.
+func emptyGroup(attrs []slog.Attr, levels int) bool {
 	for _, attr := range attrs {
 		if attr.Equal(infra.EmptyAttr()) {
 			continue
 		}
 		if attr.Value.Kind() == slog.KindGroup {
-			if !emptyGroup(attr.Value.Group()) {
+			if levels == 0 || !emptyGroup(attr.Value.Group(), levels-1) {
 				return false
 			}
 		} else {
@@ -346,3 +484,13 @@ func emptyGroup(attrs []slog.Attr) bool {
 	}
 	return true
 }
+
+// panicString returns the value to be logged when encoding a value panics.
+// As with slog.JSONHandler a nil pointer (e.g. to an error or fmt.Stringer)
+// is logged as "<nil>", otherwise the panic itself is logged.
+func panicString(a any, r any) string {
+	if v := reflect.ValueOf(a); v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("!PANIC: %v", r)
+}
@@ -0,0 +1,328 @@
+package flash
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// DedupMode specifies how duplicate keys are resolved in JSON output.
+// The modes mirror those of the veqryn/dedup handlers.
+//
+//go:generate go run github.com/dmarkham/enumer -type=DedupMode
+type DedupMode uint8
+
+const (
+	// DedupNone logs duplicate keys as is, matching slog.JSONHandler.
+	DedupNone DedupMode = iota
+	// DedupAppend collects the values for a duplicated key into a JSON array.
+	DedupAppend
+	// DedupIgnore keeps the first value for a key and ignores any later values.
+	DedupIgnore
+	// DedupIncrement keeps all values, adding a count to later keys (e.g. "key#01").
+	DedupIncrement
+	// DedupOverwrite keeps the last value for a key and removes any earlier values.
+	DedupOverwrite
+)
+
+// -----------------------------------------------------------------------------
+// Handler methods for duplicate key resolution.
+//
+// Duplicate keys may come from WithAttrs calls as well as the log record,
+// so these can't be pre-composed into the handler prefix.
+// Instead, the WithAttrs and WithGroup calls are recorded as steps and
+// replayed into the composer for each log record.
+// The same steps are replayed by composeOTLP for SchemaOTLP output.
+
+// dedupStep records a single WithAttrs or WithGroup call.
+type dedupStep struct {
+	// group is the name of the group for WithGroup, otherwise empty.
+	group string
+	// groups is the current stack of groups for ReplaceAttr calls.
+	groups []string
+	// attrs holds the attributes for WithAttrs.
+	attrs []slog.Attr
+}
+
+// composeDedup composes the attributes from WithAttrs and WithGroup calls and
+// the log record into the composer, resolving duplicate keys per Extras.Dedup.
+// The basic fields and context attributes must already have been added.
+func (h *Handler) composeDedup(c *composer, record slog.Record) error {
+	for _, step := range h.steps {
+		c.groups = step.groups
+		if step.group != "" {
+			c.dedupOpen(step.group)
+		} else if err := c.addAttributes(step.attrs); err != nil {
+			return fmt.Errorf("add with attributes: %w", err)
+		}
+	}
+	c.groups = h.groups
+	if err := h.addRecord(record, c.addAttribute); err != nil {
+		return err
+	}
+	c.dedupClose()
+	c.addBytes('}', '\n')
+	return nil
+}
+
+func (h *Handler) withAttrsDedup(attrs []slog.Attr) slog.Handler {
+	return &Handler{
+		options: h.options,
+		extras:  h.extras,
+		writer:  h.writer,
+		mutex:   h.mutex,
+		async:   h.async,
+		groups:  h.groups,
+		steps:   append(slices.Clip(h.steps), dedupStep{groups: h.groups, attrs: attrs}),
+	}
+}
+
+func (h *Handler) withGroupDedup(name string) slog.Handler {
+	var groups []string
+	if h.options.ReplaceAttr != nil {
+		groups = append(slices.Clip(h.groups), name)
+	}
+	// Empty groups are removed by composer.dedupClose (or composeOTLP)
+	// so there is no need for a group object.
+	return &Handler{
+		options: h.options,
+		extras:  h.extras,
+		writer:  h.writer,
+		mutex:   h.mutex,
+		async:   h.async,
+		groups:  groups,
+		steps:   append(slices.Clip(h.steps), dedupStep{group: name, groups: groups}),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Composer methods for duplicate key resolution.
+//
+// The composer tracks the location in the buffer of each field in each open JSON object.
+// Only the innermost open object is ever changed, so removing or inserting data
+// only affects the location of later fields in that object.
+
+// dedupKey tracks the location of a single field in the composer buffer.
+type dedupKey struct {
+	key string
+	// start is the beginning of the field including any leading separator,
+	// field is the beginning of the key, value is the beginning of the value,
+	// and end is just past the end of the value.
+	start, field, value, end int
+	// count of duplicates resolved into this field for DedupAppend and DedupIncrement.
+	count int
+	// reserved fields (the basic fields) can't be removed or appended to.
+	reserved bool
+}
+
+// dedupKeys tracks the fields in each open JSON object in the composer buffer.
+type dedupKeys struct {
+	keys []dedupKey
+	// levels holds the index into keys for the first field of each open object.
+	levels []int
+	// open is the number of open groups from WithGroup.
+	open int
+	// scratch holds values being appended for DedupAppend.
+	scratch []byte
+}
+
+func (dk *dedupKeys) reset() {
+	dk.keys = dk.keys[:0]
+	dk.levels = dk.levels[:0]
+	dk.open = 0
+}
+
+// active returns true if duplicate keys are being tracked.
+func (dk *dedupKeys) active() bool {
+	return len(dk.levels) > 0
+}
+
+// addDedup adds an attribute to the innermost open object,
+// resolving any duplicate key per Extras.Dedup.
+func (c *composer) addDedup(key string, value slog.Value, kind slog.Kind) error {
+	if index := c.dedupFind(key); index >= 0 {
+		found := &c.dedup.keys[index]
+		switch mode := c.extras.Dedup; {
+		case found.reserved || mode == DedupIncrement:
+			// Basic fields are never lost, the attribute key is incremented instead.
+			found.count++
+			key = incrementKey(key, found.count)
+		case mode == DedupIgnore:
+			return nil
+		case mode == DedupOverwrite:
+			c.dedupRemove(index)
+		case mode == DedupAppend:
+			return c.dedupAppend(index, value, kind)
+		}
+	}
+	entry := dedupKey{key: key, start: len(c.buffer)}
+	c.addSeparator()
+	entry.field = len(c.buffer)
+	c.addKey(key)
+	entry.value = len(c.buffer)
+	if err := c.addValue(key, value, kind); err != nil {
+		return err
+	}
+	entry.end = len(c.buffer)
+	c.dedup.keys = append(c.dedup.keys, entry)
+	return nil
+}
+
+// dedupAppend appends a value to the field at the specified index,
+// converting the existing value into a JSON array if necessary.
+func (c *composer) dedupAppend(index int, value slog.Value, kind slog.Kind) error {
+	// Compose the value at the end of the buffer and then move it into place.
+	mark := len(c.buffer)
+	if err := c.addValue(c.dedup.keys[index].key, value, kind); err != nil {
+		return err
+	}
+	c.dedup.scratch = append(c.dedup.scratch[:0], ',', ' ')
+	c.dedup.scratch = append(c.dedup.scratch, c.buffer[mark:]...)
+	c.buffer = c.buffer[:mark]
+	found := &c.dedup.keys[index]
+	if found.count < 1 {
+		c.dedupInsert(index, found.value, '[')
+		c.dedup.scratch = append(c.dedup.scratch, ']')
+		c.dedupInsert(index, found.end, c.dedup.scratch...)
+	} else {
+		// Insert before the closing bracket of the array.
+		c.dedupInsert(index, found.end-1, c.dedup.scratch...)
+	}
+	found.count++
+	return nil
+}
+
+// dedupBegin starts tracking fields at the top level of the log record.
+func (c *composer) dedupBegin() {
+	c.dedup.levels = append(c.dedup.levels, len(c.dedup.keys))
+}
+
+// dedupReserve marks the specified keys as reserved in the innermost open object.
+// Keys that haven't been used are reserved anyway, as with veqryn/dedup.
+func (c *composer) dedupReserve(keys ...string) {
+	for _, key := range keys {
+		if index := c.dedupFind(key); index >= 0 {
+			c.dedup.keys[index].reserved = true
+		} else {
+			c.dedup.keys = append(c.dedup.keys, dedupKey{key: key, reserved: true})
+		}
+	}
+}
+
+// dedupEnter starts a new object for a group value if fields are being tracked.
+func (c *composer) dedupEnter() {
+	if c.dedup.active() {
+		c.dedup.levels = append(c.dedup.levels, len(c.dedup.keys))
+	}
+}
+
+// dedupLeave ends the innermost object if fields are being tracked.
+func (c *composer) dedupLeave() {
+	if c.dedup.active() {
+		last := len(c.dedup.levels) - 1
+		c.dedup.keys = c.dedup.keys[:c.dedup.levels[last]]
+		c.dedup.levels = c.dedup.levels[:last]
+	}
+}
+
+// dedupOpen opens a group from WithGroup in the innermost open object.
+// The group can't be ignored or added to an array as the group is still being filled,
+// so except for DedupOverwrite a duplicate group name is incremented instead.
+func (c *composer) dedupOpen(name string) {
+	if index := c.dedupFind(name); index >= 0 {
+		found := &c.dedup.keys[index]
+		if c.extras.Dedup == DedupOverwrite && !found.reserved {
+			c.dedupRemove(index)
+		} else {
+			found.count++
+			name = incrementKey(name, found.count)
+		}
+	}
+	entry := dedupKey{key: name, start: len(c.buffer)}
+	c.addSeparator()
+	entry.field = len(c.buffer)
+	c.addKey(name)
+	entry.value = len(c.buffer)
+	c.dedup.keys = append(c.dedup.keys, entry)
+	c.addBytes('{')
+	c.reset() // Reset composer (started = false) to avoid comma.
+	c.dedupEnter()
+	c.dedup.open++
+}
+
+// dedupClose closes all groups opened by dedupOpen, removing any that are empty.
+func (c *composer) dedupClose() {
+	for ; c.dedup.open > 0; c.dedup.open-- {
+		empty := len(c.dedup.keys) == c.dedup.levels[len(c.dedup.levels)-1]
+		c.dedupLeave()
+		index := len(c.dedup.keys) - 1
+		if empty {
+			c.dedup.keys[index].end = len(c.buffer)
+			c.dedupRemove(index)
+		} else {
+			c.addBytes('}')
+			c.dedup.keys[index].end = len(c.buffer)
+		}
+	}
+}
+
+// dedupFind returns the index of the field with the specified key
+// in the innermost open object or -1 if there is no such field.
+// This is a linear search as objects are generally small.
+func (c *composer) dedupFind(key string) int {
+	for i := c.dedup.levels[len(c.dedup.levels)-1]; i < len(c.dedup.keys); i++ {
+		if c.dedup.keys[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// dedupInsert inserts data into the buffer at the specified position
+// within the field at the specified index.
+func (c *composer) dedupInsert(index, pos int, data ...byte) {
+	size := len(data)
+	c.buffer = append(c.buffer, data...)
+	copy(c.buffer[pos+size:], c.buffer[pos:len(c.buffer)-size])
+	copy(c.buffer[pos:], data)
+	c.dedup.keys[index].end += size
+	c.dedupShift(index+1, size)
+}
+
+// dedupRemove removes the field at the specified index from the buffer.
+func (c *composer) dedupRemove(index int) {
+	removed := c.dedup.keys[index]
+	from, to := removed.start, removed.end
+	next := index + 1
+	firstField := removed.start == removed.field
+	if firstField {
+		if next < len(c.dedup.keys) {
+			// Remove the separator from the next field instead.
+			to = c.dedup.keys[next].field
+		} else {
+			// Removed the only field in the object.
+			c.started = false
+		}
+	}
+	c.buffer = append(c.buffer[:from], c.buffer[to:]...)
+	c.dedupShift(next, from-to)
+	if firstField && next < len(c.dedup.keys) {
+		c.dedup.keys[next].start = c.dedup.keys[next].field
+	}
+	c.dedup.keys = append(c.dedup.keys[:index], c.dedup.keys[next:]...)
+}
+
+// dedupShift adjusts the buffer locations of the fields starting at the specified index.
+func (c *composer) dedupShift(index, size int) {
+	for i := index; i < len(c.dedup.keys); i++ {
+		c.dedup.keys[i].start += size
+		c.dedup.keys[i].field += size
+		c.dedup.keys[i].value += size
+		c.dedup.keys[i].end += size
+	}
+}
+
+// incrementKey returns the key with a count suffix, as with veqryn/dedup.
+func incrementKey(key string, count int) string {
+	return fmt.Sprintf("%s#%02d", key, count)
+}
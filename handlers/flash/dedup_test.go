@@ -0,0 +1,104 @@
+package flash
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dedupLog logs a single record without a time field via the specified handler,
+// after applying the specified function to the handler, and returns the output.
+func dedupLog(t *testing.T, mode DedupMode, with func(slog.Handler) slog.Handler, attrs ...slog.Attr) string {
+	var buf bytes.Buffer
+	var hdlr slog.Handler = NewHandler(&buf, nil, &Extras{Dedup: mode})
+	if with != nil {
+		hdlr = with(hdlr)
+	}
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+	record.AddAttrs(attrs...)
+	require.NoError(t, hdlr.Handle(context.Background(), record))
+	return buf.String()
+}
+
+func TestDedup(t *testing.T) {
+	with := func(hdlr slog.Handler) slog.Handler {
+		return hdlr.WithAttrs([]slog.Attr{slog.Int("alpha", 1), slog.Int("bravo", 2)})
+	}
+	attrs := []slog.Attr{
+		slog.Int("alpha", 3),
+		slog.String("msg", "conflict"),
+		slog.Group("group", slog.Int("one", 1), slog.Int("one", 2)),
+		slog.Int("alpha", 4),
+		slog.Int("charlie", 5),
+	}
+	for mode, expected := range map[DedupMode]string{
+		DedupNone: `{"level": "INFO", "msg": "message", "alpha": 1, "bravo": 2, "alpha": 3, "msg": "conflict", ` +
+			`"group": {"one": 1, "one": 2}, "alpha": 4, "charlie": 5}`,
+		DedupAppend: `{"level": "INFO", "msg": "message", "alpha": [1, 3, 4], "bravo": 2, "msg#01": "conflict", ` +
+			`"group": {"one": [1, 2]}, "charlie": 5}`,
+		DedupIgnore: `{"level": "INFO", "msg": "message", "alpha": 1, "bravo": 2, "msg#01": "conflict", ` +
+			`"group": {"one": 1}, "charlie": 5}`,
+		DedupIncrement: `{"level": "INFO", "msg": "message", "alpha": 1, "bravo": 2, "alpha#01": 3, "msg#01": "conflict", ` +
+			`"group": {"one": 1, "one#01": 2}, "alpha#02": 4, "charlie": 5}`,
+		DedupOverwrite: `{"level": "INFO", "msg": "message", "bravo": 2, "msg#01": "conflict", ` +
+			`"group": {"one": 2}, "alpha": 4, "charlie": 5}`,
+	} {
+		t.Run(mode.String(), func(t *testing.T) {
+			assert.Equal(t, expected+"\n", dedupLog(t, mode, with, attrs...))
+		})
+	}
+}
+
+func TestDedupWithGroup(t *testing.T) {
+	with := func(hdlr slog.Handler) slog.Handler {
+		return hdlr.WithAttrs([]slog.Attr{slog.Int("group", 1)}).
+			WithGroup("group").WithAttrs([]slog.Attr{slog.Int("alpha", 1)})
+	}
+	attrs := []slog.Attr{slog.Int("alpha", 2)}
+	for mode, expected := range map[DedupMode]string{
+		DedupAppend:    `{"level": "INFO", "msg": "message", "group": 1, "group#01": {"alpha": [1, 2]}}`,
+		DedupIgnore:    `{"level": "INFO", "msg": "message", "group": 1, "group#01": {"alpha": 1}}`,
+		DedupIncrement: `{"level": "INFO", "msg": "message", "group": 1, "group#01": {"alpha": 1, "alpha#01": 2}}`,
+		DedupOverwrite: `{"level": "INFO", "msg": "message", "group": {"alpha": 2}}`,
+	} {
+		t.Run(mode.String(), func(t *testing.T) {
+			assert.Equal(t, expected+"\n", dedupLog(t, mode, with, attrs...))
+		})
+	}
+}
+
+func TestDedupEmptyGroups(t *testing.T) {
+	with := func(hdlr slog.Handler) slog.Handler {
+		return hdlr.WithGroup("outer").WithGroup("inner")
+	}
+	for _, mode := range []DedupMode{DedupAppend, DedupIgnore, DedupIncrement, DedupOverwrite} {
+		t.Run(mode.String(), func(t *testing.T) {
+			assert.Equal(t, `{"level": "INFO", "msg": "message"}`+"\n", dedupLog(t, mode, with))
+			assert.Equal(t, `{"level": "INFO", "msg": "message", "outer": {"inner": {"alpha": 1}}}`+"\n",
+				dedupLog(t, mode, with, slog.Int("alpha", 1)))
+		})
+	}
+}
+
+func TestDedupOverwriteFirst(t *testing.T) {
+	// Overwriting the first field in a group must remove the separator of the next field.
+	assert.Equal(t, `{"level": "INFO", "msg": "message", "group": {"bravo": 2, "alpha": 3}}`+"\n",
+		dedupLog(t, DedupOverwrite, nil,
+			slog.Group("group", slog.Int("alpha", 1), slog.Int("bravo", 2), slog.Int("alpha", 3))))
+	assert.Equal(t, `{"level": "INFO", "msg": "message", "group": {"alpha": 2}}`+"\n",
+		dedupLog(t, DedupOverwrite, nil,
+			slog.Group("group", slog.Int("alpha", 1), slog.Int("alpha", 2))))
+}
+
+func TestDedupText(t *testing.T) {
+	// Dedup doesn't apply to text output.
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil, &Extras{Format: FormatText, Dedup: DedupOverwrite}))
+	logger.With("alpha", 1).Info("message", "alpha", 2)
+	assert.Contains(t, buf.String(), "alpha=1 alpha=2")
+}
@@ -0,0 +1,90 @@
+// Code generated by "enumer -type=DedupMode"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _DedupModeName = "DedupNoneDedupAppendDedupIgnoreDedupIncrementDedupOverwrite"
+
+var _DedupModeIndex = [...]uint8{0, 9, 20, 31, 45, 59}
+
+const _DedupModeLowerName = "dedupnonededupappenddedupignorededupincrementdedupoverwrite"
+
+func (i DedupMode) String() string {
+	if i >= DedupMode(len(_DedupModeIndex)-1) {
+		return fmt.Sprintf("DedupMode(%d)", i)
+	}
+	return _DedupModeName[_DedupModeIndex[i]:_DedupModeIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _DedupModeNoOp() {
+	var x [1]struct{}
+	_ = x[DedupNone-(0)]
+	_ = x[DedupAppend-(1)]
+	_ = x[DedupIgnore-(2)]
+	_ = x[DedupIncrement-(3)]
+	_ = x[DedupOverwrite-(4)]
+}
+
+var _DedupModeValues = []DedupMode{DedupNone, DedupAppend, DedupIgnore, DedupIncrement, DedupOverwrite}
+
+var _DedupModeNameToValueMap = map[string]DedupMode{
+	_DedupModeName[0:9]:        DedupNone,
+	_DedupModeLowerName[0:9]:   DedupNone,
+	_DedupModeName[9:20]:       DedupAppend,
+	_DedupModeLowerName[9:20]:  DedupAppend,
+	_DedupModeName[20:31]:      DedupIgnore,
+	_DedupModeLowerName[20:31]: DedupIgnore,
+	_DedupModeName[31:45]:      DedupIncrement,
+	_DedupModeLowerName[31:45]: DedupIncrement,
+	_DedupModeName[45:59]:      DedupOverwrite,
+	_DedupModeLowerName[45:59]: DedupOverwrite,
+}
+
+var _DedupModeNames = []string{
+	_DedupModeName[0:9],
+	_DedupModeName[9:20],
+	_DedupModeName[20:31],
+	_DedupModeName[31:45],
+	_DedupModeName[45:59],
+}
+
+// DedupModeString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func DedupModeString(s string) (DedupMode, error) {
+	if val, ok := _DedupModeNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _DedupModeNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to DedupMode values", s)
+}
+
+// DedupModeValues returns all values of the enum
+func DedupModeValues() []DedupMode {
+	return _DedupModeValues
+}
+
+// DedupModeStrings returns a slice of all String values of the enum
+func DedupModeStrings() []string {
+	strs := make([]string, len(_DedupModeNames))
+	copy(strs, _DedupModeNames)
+	return strs
+}
+
+// IsADedupMode returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i DedupMode) IsADedupMode() bool {
+	for _, v := range _DedupModeValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -11,11 +11,116 @@
 // This can be used to test the behavior of ReplaceAttr functionality or to
 // match the behavior of another logging library.
 //
+// The [flash.Extras] fields DurationEncoding and TimeEncoding select
+// numeric or string encodings for durations and times,
+// for example to replicate the conventions of zap, zerolog, or logrus
+// without a ReplaceAttr call per attribute.
+// TimeEncoding applies to both the basic time field and time attribute values.
+//
+// Level names can be configured for any level (e.g. TRACE or FATAL via [flash.Extras] field LevelNames).
+// Other levels are named relative to a configured level in the same way as slog.Level.String()
+// (e.g. "INFO+2"), or the field LevelNumeric can be set to log levels as integers.
+//
+// # Typed Encoders
+//
+// Attribute values of kind slog.KindAny normally fall back to json.Marshal,
+// which is relatively slow and allocates memory.
+// An [flash.Encoders] registry maps concrete types to functions that append
+// the JSON value directly to the log record buffer.
+// No registry is used unless one is configured via the [flash.Extras] field Encoders.
+// The registry created by NewEncoders covers net.IP, url.URL,
+// standard error chains, and []string without changing the output.
+// Use RegisterEncoder to add encoders for application types
+// or AppendUUID to log UUID-like [16]byte arrays as strings.
+//
+// # Text Output
+//
+// Setting [flash.Extras] field Format to FormatText generates human-readable
+// key=value (logfmt) lines instead of JSON.
+// Attributes within groups are shown with dotted key prefixes (e.g. group.key=value).
+// The same TimeFormat, LevelNames, and key overrides apply as for JSON output,
+// and ReplaceAttr functions are called in the same way.
+// The [flash.TextOptions] in the Extras field Text can add colorized levels
+// and pad the level and message fields so that following keys line up.
+//
+// # Binary Output
+//
+// Setting [flash.Extras] field Format to FormatCBOR or FormatMsgPack generates
+// one compact binary map per log record instead of a line of JSON.
+// Records are written back-to-back as both formats are self-delimiting.
+// Groups are nested maps, attributes from WithAttrs and WithGroup are still composed
+// ahead of time, and ReplaceAttr functions are called in the same way as for JSON output.
+// Times and durations follow the TimeEncoding and DurationEncoding fields.
+// The Encoders, Dedup, and SourceShape fields apply to JSON output only.
+//
+// # Context Attributes
+//
+// Attributes attached to the context via [ctxattr.With] are added to each log record
+// handled with that context, after the basic fields and outside of any WithGroup groups.
+// Setting the [flash.Extras] field Context can place them under a named group instead.
+//
+// # Output Limits
+//
+// Setting the [flash.Extras] field Limits to a [limit.Options] object bounds the output for each log record:
+// the message and attribute value lengths, the number of attributes,
+// and the nesting of groups and slog.LogValuer values.
+// Even without Limits the nesting of groups and slog.LogValuer values is bounded
+// by default so that self-referential data is logged as "!DEPTH" or "!RESOLVE".
+// Regardless of limits, values that panic or can't be marshaled are logged
+// as "!PANIC: ..." or "!ERROR:..." strings as with slog.JSONHandler.
+//
+// # Duplicate Keys
+//
+// Like slog.JSONHandler, flash logs duplicate keys as is by default.
+// Setting the [flash.Extras] field Dedup to a [flash.DedupMode] resolves duplicate keys
+// within each JSON object (overwrite, ignore, append, or increment) as with veqryn/dedup.
+// Keys that conflict with the basic fields (time, level, msg, source) are always incremented.
+// Duplicates may come from both WithAttrs and the log record, so with Dedup configured
+// attributes from WithAttrs are composed for each log record instead of being pre-composed.
+//
+// # Schema Presets
+//
+// Setting the [flash.Extras] field Schema to a [flash.Schema] preset configures the
+// basic field keys, level names, source data layout, and time format for a log backend
+// in one step, so switching backends is a configuration change:
+//
+//   - SchemaECS: Elastic Common Schema ("@timestamp", "log.level", "message", "log.origin"),
+//   - SchemaGCP: Google Cloud Logging ("time", "severity", "message",
+//     "logging.googleapis.com/sourceLocation"), and
+//   - SchemaOTLP: the OTLP JSON encoding of an OpenTelemetry log record
+//     ("timeUnixNano", "severityNumber", "severityText", "body", "attributes").
+//
+// Any of the corresponding Extras fields that are set explicitly override the preset.
+// The source data layout ([flash.SourceShape]) applies to JSON output only.
+//
+// With SchemaOTLP the time is a decimal string of nanoseconds,
+// the body is an AnyValue object (e.g. {"stringValue": "message"}),
+// and attributes, including groups from WithGroup and source data
+// ("code.function", "code.filepath", "code.lineno"), are logged as a list of
+// {"key": ..., "value": {...}} objects with kvlistValue objects for groups.
+// Encoders and Dedup are not used with SchemaOTLP.
+//
+// # Asynchronous Output
+//
+// Setting [flash.Extras] field Async to a non-nil [flash.AsyncOptions] object
+// moves writing of log records to a background goroutine.
+// Records are still composed by the goroutine doing the logging,
+// then pushed onto a bounded queue so that a slow io.Writer doesn't stall the caller.
+// The queue depth and the overflow policy (block, drop newest, or drop oldest) are configurable.
+// The Handler methods Flush, Close, and Stats manage asynchronous output.
+//
 // # Performance Edits
 //
 // After flash was cloned from sloggy it went through a number of performance-related [edits].
 //
+// [flash.Encoders]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Encoders
 // [flash.Extras]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Extras
+// [flash.DedupMode]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#DedupMode
+// [flash.Schema]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#Schema
+// [flash.SourceShape]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#SourceShape
+// [flash.TextOptions]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash#TextOptions
+// [ctxattr.With]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/ctxattr#With
+// [limit.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/infra/limit#Options
 // [sloggy]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/sloggy
 // [edits]: https://github.com/madkins23/go-slog/blob/main/handlers/flash/EDITS.md
 package flash
@@ -0,0 +1,86 @@
+// Code generated by "enumer -type=DurationEncoding"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _DurationEncodingName = "DurationNanosDurationMillisDurationSecondsDurationString"
+
+var _DurationEncodingIndex = [...]uint8{0, 13, 27, 42, 56}
+
+const _DurationEncodingLowerName = "durationnanosdurationmillisdurationsecondsdurationstring"
+
+func (i DurationEncoding) String() string {
+	if i >= DurationEncoding(len(_DurationEncodingIndex)-1) {
+		return fmt.Sprintf("DurationEncoding(%d)", i)
+	}
+	return _DurationEncodingName[_DurationEncodingIndex[i]:_DurationEncodingIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _DurationEncodingNoOp() {
+	var x [1]struct{}
+	_ = x[DurationNanos-(0)]
+	_ = x[DurationMillis-(1)]
+	_ = x[DurationSeconds-(2)]
+	_ = x[DurationString-(3)]
+}
+
+var _DurationEncodingValues = []DurationEncoding{DurationNanos, DurationMillis, DurationSeconds, DurationString}
+
+var _DurationEncodingNameToValueMap = map[string]DurationEncoding{
+	_DurationEncodingName[0:13]:       DurationNanos,
+	_DurationEncodingLowerName[0:13]:  DurationNanos,
+	_DurationEncodingName[13:27]:      DurationMillis,
+	_DurationEncodingLowerName[13:27]: DurationMillis,
+	_DurationEncodingName[27:42]:      DurationSeconds,
+	_DurationEncodingLowerName[27:42]: DurationSeconds,
+	_DurationEncodingName[42:56]:      DurationString,
+	_DurationEncodingLowerName[42:56]: DurationString,
+}
+
+var _DurationEncodingNames = []string{
+	_DurationEncodingName[0:13],
+	_DurationEncodingName[13:27],
+	_DurationEncodingName[27:42],
+	_DurationEncodingName[42:56],
+}
+
+// DurationEncodingString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func DurationEncodingString(s string) (DurationEncoding, error) {
+	if val, ok := _DurationEncodingNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _DurationEncodingNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to DurationEncoding values", s)
+}
+
+// DurationEncodingValues returns all values of the enum
+func DurationEncodingValues() []DurationEncoding {
+	return _DurationEncodingValues
+}
+
+// DurationEncodingStrings returns a slice of all String values of the enum
+func DurationEncodingStrings() []string {
+	strs := make([]string, len(_DurationEncodingNames))
+	copy(strs, _DurationEncodingNames)
+	return strs
+}
+
+// IsADurationEncoding returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i DurationEncoding) IsADurationEncoding() bool {
+	for _, v := range _DurationEncodingValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
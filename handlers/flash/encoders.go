@@ -0,0 +1,181 @@
+package flash
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// EncoderFn appends the JSON encoding of a value of type T to the buffer and returns the result.
+// The encoding must be a single, valid JSON value.
+type EncoderFn[T any] func(buffer []byte, value T) []byte
+
+// anyEncoderFn is the type-erased form of EncoderFn stored in an Encoders registry.
+type anyEncoderFn func(buffer []byte, value any) []byte
+
+// Encoders is a registry of per-type encoders for slog.KindAny attribute values.
+//
+// When generating JSON output the composer looks up the concrete type of each
+// slog.KindAny value in the registry before falling back to
+// fmt.Stringer, error, json.Marshaler, encoding.TextMarshaler, and json.Marshal.
+// Registered encoders append directly to the log record buffer,
+// avoiding the allocations and reflection of json.Marshal.
+// Encoders are not used for FormatText output.
+//
+// An Encoders object is safe for concurrent use.
+// Lookups are lock-free, registration copies the registry.
+// The zero value is an empty registry ready to use.
+type Encoders struct {
+	mutex sync.Mutex
+	types atomic.Pointer[map[reflect.Type]anyEncoderFn]
+}
+
+// NewEncoders returns a new Encoders registry containing the built-in encoders for:
+//
+//   - net.IP as a string (e.g. "192.168.0.1"),
+//   - url.URL and *url.URL as a string,
+//   - errors created via errors.New, errors.Join, and fmt.Errorf (including wrapped error chains)
+//     as the string returned by the Error method, and
+//   - []string as an array of strings.
+//
+// The built-in encoders generate the same output as the default flash handler, only faster.
+// The AppendUUID encoder is not included as it changes the output for all [16]byte values.
+func NewEncoders() *Encoders {
+	encoders := &Encoders{}
+	RegisterEncoder(encoders, appendIP)
+	RegisterEncoder(encoders, func(buffer []byte, value url.URL) []byte {
+		return AppendJSONString(buffer, value.String())
+	})
+	RegisterEncoder(encoders, func(buffer []byte, value *url.URL) []byte {
+		if value == nil {
+			return append(buffer, "null"...)
+		}
+		return AppendJSONString(buffer, value.String())
+	})
+	RegisterEncoder(encoders, appendStrings)
+	inner := errors.New("inner")
+	for _, err := range []error{
+		inner,
+		errors.Join(inner, inner),
+		fmt.Errorf("outer"),
+		fmt.Errorf("outer: %w", inner),
+		fmt.Errorf("outer: %w, %w", inner, inner),
+	} {
+		encoders.register(reflect.TypeOf(err), appendError)
+	}
+	return encoders
+}
+
+// RegisterEncoder adds an encoder for values of type T to the registry,
+// replacing any encoder previously registered for T.
+// The type T must be a concrete type, as encoders are matched against
+// the dynamic type of slog.KindAny values.
+// Pointer and non-pointer types are separate (e.g. url.URL and *url.URL).
+func RegisterEncoder[T any](encoders *Encoders, fn EncoderFn[T]) {
+	encoders.register(reflect.TypeFor[T](), func(buffer []byte, value any) []byte {
+		return fn(buffer, value.(T))
+	})
+}
+
+// register adds an encoder for the specified type by copying the registry.
+func (e *Encoders) register(typ reflect.Type, fn anyEncoderFn) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	var current map[reflect.Type]anyEncoderFn
+	if loaded := e.types.Load(); loaded != nil {
+		current = *loaded
+	}
+	types := make(map[reflect.Type]anyEncoderFn, len(current)+1)
+	for t, f := range current {
+		types[t] = f
+	}
+	types[typ] = fn
+	e.types.Store(&types)
+}
+
+// lookup returns the encoder for the dynamic type of the value or nil if there is none.
+func (e *Encoders) lookup(value any) anyEncoderFn {
+	if e == nil || value == nil {
+		return nil
+	}
+	types := e.types.Load()
+	if types == nil {
+		// Zero value registry with nothing registered.
+		return nil
+	}
+	return (*types)[reflect.TypeOf(value)]
+}
+
+// -----------------------------------------------------------------------------
+
+// AppendJSONString appends the string to the buffer as a quoted, escaped JSON string.
+// This is provided for use in EncoderFn functions.
+func AppendJSONString(buffer []byte, str string) []byte {
+	buffer = append(buffer, '"')
+	buffer = appendEscaped(buffer, []byte(str))
+	return append(buffer, '"')
+}
+
+func appendError(buffer []byte, value any) []byte {
+	return AppendJSONString(buffer, value.(error).Error())
+}
+
+func appendIP(buffer []byte, ip net.IP) []byte {
+	if len(ip) == 0 {
+		// Matches net.IP.MarshalText.
+		return append(buffer, '"', '"')
+	}
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return AppendJSONString(buffer, ip.String())
+	}
+	buffer = append(buffer, '"')
+	for i, b := range ip4 {
+		if i > 0 {
+			buffer = append(buffer, '.')
+		}
+		buffer = strconv.AppendUint(buffer, uint64(b), 10)
+	}
+	return append(buffer, '"')
+}
+
+const hexLower = "0123456789abcdef"
+
+// AppendUUID is an EncoderFn that appends a [16]byte value as a UUID string
+// (e.g. "123e4567-e89b-12d3-a456-426614174000").
+// It is not included in the built-in encoders as it applies to all [16]byte values
+// (e.g. an MD5 sum) which slog.JSONHandler logs as an array of numbers.
+// Add it to a registry via:
+//
+//	flash.RegisterEncoder(encoders, flash.AppendUUID)
+func AppendUUID(buffer []byte, uuid [16]byte) []byte {
+	buffer = append(buffer, '"')
+	for i, b := range uuid {
+		switch i {
+		case 4, 6, 8, 10:
+			buffer = append(buffer, '-')
+		}
+		buffer = append(buffer, hexLower[b>>4], hexLower[b&0xF])
+	}
+	return append(buffer, '"')
+}
+
+func appendStrings(buffer []byte, strs []string) []byte {
+	if strs == nil {
+		// Matches json.Marshal.
+		return append(buffer, "null"...)
+	}
+	buffer = append(buffer, '[')
+	for i, str := range strs {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = AppendJSONString(buffer, str)
+	}
+	return append(buffer, ']')
+}
@@ -0,0 +1,129 @@
+package flash
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/internal/json"
+)
+
+func TestEncoders_BuiltIn(t *testing.T) {
+	u, err := url.Parse("https://example.com/path?q=a&r=b")
+	require.NoError(t, err)
+	inner := errors.New("inner")
+	encoders := NewEncoders()
+	for _, tc := range []struct {
+		value    any
+		expected string
+	}{
+		{net.IPv4(192, 168, 0, 1), `"192.168.0.1"`},
+		{net.IP{10, 0, 0, 255}, `"10.0.0.255"`},
+		{net.ParseIP("2001:db8::1"), `"2001:db8::1"`},
+		{net.IP(nil), `""`},
+		{u, `"https:\/\/example.com\/path?q=a&r=b"`},
+		{*u, `"https:\/\/example.com\/path?q=a&r=b"`},
+		{inner, `"inner"`},
+		{fmt.Errorf("outer: %w", inner), `"outer: inner"`},
+		{fmt.Errorf("outer: %w, %w", inner, errors.New(`"quoted"`)), `"outer: inner, \"quoted\""`},
+		{errors.Join(inner, inner), `"inner\ninner"`},
+		{[]string{"alpha", "omega\t"}, `["alpha","omega\t"]`},
+		{[]string{}, `[]`},
+		{[]string(nil), `null`},
+	} {
+		fn := encoders.lookup(tc.value)
+		require.NotNil(t, fn, tc.expected)
+		assert.Equal(t, tc.expected, string(fn(nil, tc.value)), tc.expected)
+	}
+	assert.Nil(t, encoders.lookup(nil))
+	assert.Nil(t, encoders.lookup(17))
+	assert.Nil(t, encoders.lookup([16]byte{}), "UUID encoder is opt-in")
+	assert.Nil(t, (*Encoders)(nil).lookup(net.IPv4zero))
+}
+
+func TestEncoders_UUID(t *testing.T) {
+	uuid := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+	// By default a [16]byte is logged as an array of numbers, matching slog.JSONHandler.
+	var buf, expected bytes.Buffer
+	slog.New(NewHandler(&buf, nil, nil)).Info("encoded", "uuid", uuid)
+	slog.New(slog.NewJSONHandler(&expected, nil)).Info("encoded", "uuid", uuid)
+	logMap, err := json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	expectedMap, err := json.Parse(expected.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, expectedMap["uuid"], logMap["uuid"])
+
+	encoders := NewEncoders()
+	RegisterEncoder(encoders, AppendUUID)
+	buf.Reset()
+	slog.New(NewHandler(&buf, nil, &Extras{Encoders: encoders})).Info("encoded", "uuid", uuid)
+	logMap, err = json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", logMap["uuid"])
+}
+
+type point struct{ X, Y int }
+
+func TestEncoders_ZeroValue(t *testing.T) {
+	encoders := &Encoders{}
+	assert.Nil(t, encoders.lookup(point{}))
+
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil, &Extras{Encoders: encoders}))
+	logger.Info("encoded", "point", point{X: 1, Y: 2})
+	logMap, err := json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"X": float64(1), "Y": float64(2)}, logMap["point"])
+
+	RegisterEncoder(encoders, func(buffer []byte, p point) []byte {
+		return fmt.Appendf(buffer, "[%d,%d]", p.X, p.Y)
+	})
+	buf.Reset()
+	logger.Info("encoded", "point", point{X: 1, Y: 2})
+	logMap, err = json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []any{float64(1), float64(2)}, logMap["point"])
+}
+
+func TestEncoders_Register(t *testing.T) {
+	encoders := NewEncoders()
+	RegisterEncoder(encoders, func(buffer []byte, p point) []byte {
+		return fmt.Appendf(buffer, "[%d,%d]", p.X, p.Y)
+	})
+	// Override a built-in encoder.
+	RegisterEncoder(encoders, func(buffer []byte, strs []string) []byte {
+		return AppendJSONString(buffer, fmt.Sprint(len(strs)))
+	})
+	assert.Nil(t, NewEncoders().lookup(point{}), "new registry unchanged")
+
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil, &Extras{Encoders: encoders}))
+	logger.Info("encoded", "point", point{X: 1, Y: 2}, "strs", []string{"a", "b"}, "ip", net.IPv4(1, 2, 3, 4))
+	logMap, err := json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []any{float64(1), float64(2)}, logMap["point"])
+	assert.Equal(t, "2", logMap["strs"])
+	assert.Equal(t, "1.2.3.4", logMap["ip"])
+
+	// Without a registered encoder the value is marshaled as before.
+	buf.Reset()
+	slog.New(NewHandler(&buf, nil, nil)).Info("encoded", "point", point{X: 1, Y: 2})
+	logMap, err = json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"X": float64(1), "Y": float64(2)}, logMap["point"])
+
+	// Encoders are not used for text output.
+	buf.Reset()
+	slog.New(NewHandler(&buf, nil, &Extras{Encoders: encoders, Format: FormatText})).
+		InfoContext(context.Background(), "encoded", "strs", []string{"a", "b"})
+	assert.Contains(t, buf.String(), `strs=["a","b"]`)
+}
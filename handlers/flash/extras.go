@@ -2,30 +2,115 @@ package flash
 
 import (
 	"log/slog"
+	"slices"
+	"strconv"
 	"time"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
+	"github.com/madkins23/go-slog/infra/limit"
 )
 
 const (
 	DefaultTimeFormat = time.RFC3339Nano
 )
 
+// Format specifies the output format for log records.
+//
+//go:generate go run github.com/dmarkham/enumer -type=Format
+type Format uint8
+
+const (
+	// FormatJSON generates one JSON object per line, matching slog.JSONHandler.
+	FormatJSON Format = iota
+	// FormatText generates human-readable key=value (logfmt) lines.
+	FormatText
+	// FormatCBOR generates one CBOR (RFC 8949) map per log record.
+	FormatCBOR
+	// FormatMsgPack generates one MessagePack map per log record.
+	FormatMsgPack
+)
+
+// binary returns true if the format is one of the binary formats.
+func (f Format) binary() bool {
+	return f == FormatCBOR || f == FormatMsgPack
+}
+
+// DurationEncoding specifies how time.Duration attribute values are logged.
+//
+//go:generate go run github.com/dmarkham/enumer -type=DurationEncoding
+type DurationEncoding uint8
+
+const (
+	// DurationNanos logs durations as integer nanoseconds, matching slog.JSONHandler.
+	DurationNanos DurationEncoding = iota
+	// DurationMillis logs durations as floating point milliseconds (e.g. zap, zerolog).
+	DurationMillis
+	// DurationSeconds logs durations as floating point seconds.
+	DurationSeconds
+	// DurationString logs durations as strings via time.Duration.String() (e.g. logrus).
+	DurationString
+)
+
+// TimeEncoding specifies how time.Time values are logged.
+//
+//go:generate go run github.com/dmarkham/enumer -type=TimeEncoding
+type TimeEncoding uint8
+
+const (
+	// TimeLayout logs times as strings formatted with Extras.TimeFormat, matching slog.JSONHandler.
+	TimeLayout TimeEncoding = iota
+	// TimeUnixSeconds logs times as integer seconds since the Unix epoch (e.g. zerolog).
+	TimeUnixSeconds
+	// TimeUnixMillis logs times as integer milliseconds since the Unix epoch.
+	TimeUnixMillis
+	// TimeUnixNanos logs times as integer nanoseconds since the Unix epoch.
+	TimeUnixNanos
+)
+
 // Extras defines extra options specific to a flash.Handler.
 //
 // Using these options it is possible to override some of the log/slog "standard" behavior.
 // This supports testing of slog.HandlerOptions.ReplaceAttr functions and may also
 // be used to replicate non-standard behavior in other handlers.
 type Extras struct {
-	// TimeFormat holds the format of the basic time field.
+	// TimeFormat holds the layout for time values when TimeEncoding is TimeLayout.
+	// This applies to both the basic time field and time attribute values.
 	// If not set defaults to the value of flash.DefaultTimeFormat (= time.RFC3339Nano).
 	TimeFormat string
 
+	// TimeEncoding specifies how time values are logged,
+	// both for the basic time field and for time attribute values.
+	// If not set defaults to TimeLayout.
+	TimeEncoding TimeEncoding
+
+	// DurationEncoding specifies how time.Duration attribute values are logged.
+	// If not set defaults to DurationNanos.
+	DurationEncoding DurationEncoding
+
 	// LevelNames holds a map from slog.Level to string.
 	// If these fields are configured they replace the usual level names.
 	// It is possible to configure only some of the level names.
-	// Any level name that is not configured will be set to the appropriate
-	// slog global constant (e.g. slog.LevelInfo.String()).
+	// Any of the four standard level names that is not configured will be set to
+	// the appropriate slog global constant (e.g. slog.LevelInfo.String()).
+	// Names may also be configured for other levels (e.g. TRACE = -8 or FATAL = 12).
+	//
+	// Levels without a configured name are named slog-style relative to
+	// the closest lower configured level (e.g. slog.LevelInfo+2 is "INFO+2")
+	// or relative to the lowest configured level if there is no lower level (e.g. "DEBUG-2").
 	LevelNames map[slog.Level]string
 
+	// LevelNumeric logs the slog.Level as an integer instead of a name.
+	LevelNumeric bool
+
+	// LevelNumberKey specifies the JSON field name for an additional integer level field
+	// logged immediately after the level (e.g. "severityNumber" for SchemaOTLP).
+	// The value is the slog.Level plus LevelNumberOffset.
+	// If this field is not configured no level number field is logged.
+	LevelNumberKey string
+
+	// LevelNumberOffset is added to the slog.Level for the LevelNumberKey field.
+	LevelNumberOffset int
+
 	// LevelKey specifies the JSON field name for the slog.Level for the log records.
 	// If this field is not configured the value of slog.LevelKey is used.
 	LevelKey string
@@ -41,6 +126,52 @@ type Extras struct {
 	// TimeKey specifies the JSON field name for the time the record was logged.
 	// If this field is not configured the value of slog.TimeKey is used.
 	TimeKey string
+
+	// SourceShape specifies the JSON layout of source data.
+	// This applies to FormatJSON only and is otherwise ignored.
+	// If not set defaults to SourceSlog, matching slog.JSONHandler.
+	SourceShape SourceShape
+
+	// Schema sets the key names, level names, level number, source shape, and time format
+	// to a named preset (e.g. SchemaECS) in one step.
+	// Fields that are also configured explicitly override the preset values.
+	// If not set defaults to SchemaNone, matching slog.JSONHandler.
+	Schema Schema
+
+	// Async configures asynchronous output via a bounded queue and a background writer.
+	// If this field is not configured log records are written synchronously by Handle.
+	Async *AsyncOptions
+
+	// Format specifies the output format for log records.
+	// If not set defaults to FormatJSON.
+	Format Format
+
+	// Text configures FormatText output and is otherwise ignored.
+	Text TextOptions
+
+	// Encoders is the registry of per-type encoders for slog.KindAny values in JSON output.
+	// If not set no encoders are used, matching slog.JSONHandler.
+	Encoders *Encoders
+
+	// Limits configures safety limits on the output for each log record.
+	// If not set only the default group and slog.LogValuer nesting limits apply,
+	// otherwise matching slog.JSONHandler.
+	Limits *limit.Options
+
+	// Context configures placement of attributes attached to the context via ctxattr.With.
+	// If not set context attributes are placed at the top level of the log record.
+	Context *ctxattr.Options
+
+	// Dedup specifies how duplicate keys are resolved within each JSON object.
+	// This applies to FormatJSON only and is otherwise ignored.
+	// If not set defaults to DedupNone, matching slog.JSONHandler.
+	Dedup DedupMode
+
+	// otlp is set when log records are composed in the OTLP JSON encoding for SchemaOTLP.
+	otlp bool
+
+	// levelOrder holds the configured levels in ascending order for level name fallback.
+	levelOrder []slog.Level
 }
 
 // fixExtras makes certain that an Extras object has been properly created and
@@ -53,6 +184,8 @@ func fixExtras(extras *Extras) *Extras {
 	if extras == nil {
 		extras = &Extras{}
 	}
+	extras.applySchema()
+	extras.otlp = extras.Schema == SchemaOTLP && extras.Format == FormatJSON
 	if extras.TimeFormat == "" {
 		extras.TimeFormat = DefaultTimeFormat
 	}
@@ -71,6 +204,12 @@ func fixExtras(extras *Extras) *Extras {
 	if extras.LevelNames[slog.LevelError] == "" {
 		extras.LevelNames[slog.LevelError] = slog.LevelError.String()
 	}
+	extras.levelOrder = make([]slog.Level, 0, len(extras.LevelNames))
+	for level := range extras.LevelNames {
+		extras.levelOrder = append(extras.levelOrder, level)
+	}
+	slices.Sort(extras.levelOrder)
+	extras.Limits = limit.Fix(extras.Limits)
 	if extras.LevelKey == "" {
 		extras.LevelKey = slog.LevelKey
 	}
@@ -85,3 +224,26 @@ func fixExtras(extras *Extras) *Extras {
 	}
 	return extras
 }
+
+// levelName returns the name of the specified level.
+// Levels without a configured name are named relative to a configured level.
+func (extras *Extras) levelName(level slog.Level) string {
+	if name, found := extras.LevelNames[level]; found {
+		return name
+	}
+	if len(extras.levelOrder) < 1 {
+		return level.String()
+	}
+	base := extras.levelOrder[0]
+	for _, lvl := range extras.levelOrder {
+		if lvl > level {
+			break
+		}
+		base = lvl
+	}
+	offset := int64(level - base)
+	if offset > 0 {
+		return extras.LevelNames[base] + "+" + strconv.FormatInt(offset, 10)
+	}
+	return extras.LevelNames[base] + strconv.FormatInt(offset, 10)
+}
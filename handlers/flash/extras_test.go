@@ -2,13 +2,16 @@ package flash
 
 import (
 	"bytes"
+	"context"
 	"log/slog"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/madkins23/go-slog/infra/limit"
 	"github.com/madkins23/go-slog/internal/json"
 	"github.com/madkins23/go-slog/internal/test"
 )
@@ -73,3 +76,193 @@ func TestFlashExtras(t *testing.T) {
 		assert.Equal(t, expected, logMap)
 	}
 }
+
+// TestFlashEncodings verifies the DurationEncoding and TimeEncoding extras
+// for both the basic time field and attribute values.
+func TestFlashEncodings(t *testing.T) {
+	when := time.Date(2024, time.March, 1, 12, 30, 45, 123456789, time.UTC)
+	elapsed := 1500*time.Millisecond + 250*time.Microsecond
+	for name, tc := range map[string]struct {
+		extras *Extras
+		json   string
+		text   string
+	}{
+		"default": {
+			extras: &Extras{},
+			json:   `{"time": "2024-03-01T12:30:45.123456789Z", "level": "INFO", "msg": "m", "elapsed": 1500250000, "when": "2024-03-01T12:30:45.123456789Z"}`,
+			text:   `time=2024-03-01T12:30:45.123456789Z level=INFO msg=m elapsed=1500250000 when=2024-03-01T12:30:45.123456789Z`,
+		},
+		"zap": {
+			extras: &Extras{DurationEncoding: DurationSeconds, TimeEncoding: TimeUnixNanos},
+			json:   `{"time": 1709296245123456789, "level": "INFO", "msg": "m", "elapsed": 1.50025, "when": 1709296245123456789}`,
+			text:   `time=1709296245123456789 level=INFO msg=m elapsed=1.50025 when=1709296245123456789`,
+		},
+		"zerolog": {
+			extras: &Extras{DurationEncoding: DurationMillis, TimeEncoding: TimeUnixSeconds},
+			json:   `{"time": 1709296245, "level": "INFO", "msg": "m", "elapsed": 1500.25, "when": 1709296245}`,
+			text:   `time=1709296245 level=INFO msg=m elapsed=1500.25 when=1709296245`,
+		},
+		"logrus": {
+			extras: &Extras{DurationEncoding: DurationString, TimeFormat: time.RFC3339},
+			json:   `{"time": "2024-03-01T12:30:45Z", "level": "INFO", "msg": "m", "elapsed": "1.50025s", "when": "2024-03-01T12:30:45Z"}`,
+			text:   `time=2024-03-01T12:30:45Z level=INFO msg=m elapsed=1.50025s when=2024-03-01T12:30:45Z`,
+		},
+		"millis": {
+			extras: &Extras{TimeEncoding: TimeUnixMillis},
+			json:   `{"time": 1709296245123, "level": "INFO", "msg": "m", "elapsed": 1500250000, "when": 1709296245123}`,
+			text:   `time=1709296245123 level=INFO msg=m elapsed=1500250000 when=1709296245123`,
+		},
+	} {
+		for _, format := range []Format{FormatJSON, FormatText} {
+			var buf bytes.Buffer
+			extras := *tc.extras
+			extras.Format = format
+			hdlr := NewHandler(&buf, nil, &extras)
+			record := slog.NewRecord(when, slog.LevelInfo, "m", 0)
+			record.AddAttrs(slog.Duration("elapsed", elapsed), slog.Time("when", when))
+			assert.NoError(t, hdlr.Handle(context.Background(), record), name)
+			expected := tc.json
+			if format == FormatText {
+				expected = tc.text
+			}
+			assert.Equal(t, expected+"\n", buf.String(), name+" "+format.String())
+		}
+	}
+	// The basic time field is also encoded when passed through ReplaceAttr.
+	var buf bytes.Buffer
+	hdlr := NewHandler(&buf, &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr { return a }},
+		&Extras{TimeEncoding: TimeUnixSeconds})
+	assert.NoError(t, hdlr.Handle(context.Background(), slog.NewRecord(when, slog.LevelInfo, "m", 0)))
+	assert.Equal(t, `{"time": 1709296245, "level": "INFO", "msg": "m"}`+"\n", buf.String())
+}
+
+// TestFlashLevelNames verifies level names for non-standard levels and numeric level output.
+func TestFlashLevelNames(t *testing.T) {
+	const (
+		levelTrace = slog.Level(-8)
+		levelFatal = slog.Level(12)
+	)
+	// Default names match slog.Level.String().
+	extras := fixExtras(nil)
+	for _, level := range []slog.Level{
+		slog.LevelDebug - 2, slog.LevelDebug, slog.LevelInfo, slog.LevelInfo + 2, slog.LevelWarn - 1,
+		slog.LevelWarn, slog.LevelError, slog.LevelError + 4,
+	} {
+		assert.Equal(t, level.String(), extras.levelName(level))
+	}
+	extras = fixExtras(&Extras{LevelNames: map[slog.Level]string{
+		levelTrace:      "TRACE",
+		slog.LevelDebug: "debug",
+		levelFatal:      "FATAL",
+	}})
+	for level, expected := range map[slog.Level]string{
+		levelTrace - 1:     "TRACE-1",
+		levelTrace:         "TRACE",
+		levelTrace + 2:     "TRACE+2",
+		slog.LevelDebug:    "debug",
+		slog.LevelInfo:     "INFO",
+		slog.LevelInfo + 2: "INFO+2",
+		slog.LevelError:    "ERROR",
+		levelFatal - 1:     "ERROR+3",
+		levelFatal:         "FATAL",
+		levelFatal + 4:     "FATAL+4",
+	} {
+		assert.Equal(t, expected, extras.levelName(level), int(level))
+	}
+
+	var buf bytes.Buffer
+	log := slog.New(NewHandler(&buf, &slog.HandlerOptions{Level: levelTrace}, extras))
+	log.Log(context.Background(), levelTrace, test.Message)
+	log.Log(context.Background(), levelFatal, test.Message)
+	log.Log(context.Background(), slog.LevelInfo+2, test.Message)
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte{'\n'})
+	assert.Len(t, lines, 3)
+	for i, expected := range []string{"TRACE", "FATAL", "INFO+2"} {
+		logMap, err := json.Parse(lines[i])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, logMap[slog.LevelKey])
+	}
+
+	// Numeric level output.
+	for _, replace := range []func([]string, slog.Attr) slog.Attr{nil, func(_ []string, a slog.Attr) slog.Attr { return a }} {
+		buf.Reset()
+		slog.New(NewHandler(&buf, &slog.HandlerOptions{ReplaceAttr: replace},
+			&Extras{LevelNumeric: true})).Log(context.Background(), slog.LevelWarn+1, test.Message)
+		logMap, err := json.Parse(buf.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, float64(slog.LevelWarn+1), logMap[slog.LevelKey])
+		buf.Reset()
+		slog.New(NewHandler(&buf, &slog.HandlerOptions{ReplaceAttr: replace},
+			&Extras{LevelNumeric: true, Format: FormatText})).Log(context.Background(), levelFatal, test.Message)
+		assert.Contains(t, buf.String(), " level=12 ")
+	}
+}
+
+// TestFlashLimits verifies that flash.Extras.Limits bounds the output of log records
+// that would otherwise be huge or recurse forever.
+func TestFlashLimits(t *testing.T) {
+	args := []any{
+		"string", "value too long",
+		"list", []int{1, 2, 3, 4, 5},
+		"loop", test.LoopValuer{},
+		test.SelfGroup("self"),
+		"stringer", test.PanicStringer{},
+		"cycle", test.NewCycle("loop"),
+		"extra1", 1,
+		"extra2", 2,
+	}
+	limits := &limit.Options{
+		MaxMessageLength: 8,
+		MaxValueLength:   6,
+		MaxGroupDepth:    3,
+		MaxAttrs:         7,
+		MaxResolveDepth:  2,
+	}
+
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, nil, &Extras{Limits: limits})).Info("message too long", args...)
+	logMap, err := json.Parse(buf.Bytes())
+	assert.NoError(t, err)
+	delete(logMap, slog.TimeKey)
+	assert.Equal(t, map[string]any{
+		slog.LevelKey:   "INFO",
+		slog.MessageKey: "message ...",
+		"string":        "value ...",
+		"list":          "[1,2,3...",
+		"loop":          map[string]any{"loop": map[string]any{"loop": limit.ResolveExceeded}},
+		"self":          map[string]any{"self": map[string]any{"self": map[string]any{"self": limit.DepthExceeded}}},
+		"stringer":      "!PANIC: PanicStringer",
+		"cycle":         "!ERROR:json: unsupported value: encountered a cycle via *test.Cycle",
+		"extra1":        float64(1),
+		"!DROPPED":      float64(1),
+	}, logMap)
+
+	buf.Reset()
+	slog.New(NewHandler(&buf, nil, &Extras{Limits: limits, Format: FormatText})).Info("message too long", args...)
+	for _, expected := range []string{
+		` msg="message ..." `,
+		` string="value ..." `,
+		` list="[1,2,3..." `,
+		` loop.loop.loop=!RESOLVE `,
+		` self.self.self.self=!DEPTH `,
+		` stringer="!PANIC: PanicStringer" `,
+		` extra1=1 !DROPPED=1` + "\n",
+	} {
+		assert.Contains(t, buf.String(), expected)
+	}
+}
+
+// TestFlashDefaultLimits verifies that self-referential groups and slog.LogValuer objects
+// are bounded by the default limits when flash.Extras.Limits is not set.
+func TestFlashDefaultLimits(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatText} {
+		var buf bytes.Buffer
+		slog.New(NewHandler(&buf, nil, &Extras{Format: format})).Info(test.Message, "loop", test.LoopValuer{}, test.SelfGroup("self"))
+		assert.Equal(t, 1, strings.Count(buf.String(), limit.ResolveExceeded))
+		assert.Equal(t, 1, strings.Count(buf.String(), limit.DepthExceeded))
+		if format == FormatJSON {
+			_, err := json.Parse(buf.Bytes())
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strconv"
 	"sync"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
 )
 
 const lenLog = 1024
@@ -22,13 +25,26 @@ type Handler struct {
 	extras         *Extras
 	writer         io.Writer
 	mutex          *sync.Mutex
+	async          *asyncWriter
 	prefix, suffix []byte
 	groups         []string
+
+	// keyPrefix holds dotted group names for text output.
+	keyPrefix string
+
+	// binMaps holds the maps opened by the prefix for binary output.
+	binMaps []binaryMap
+
+	// steps holds WithAttrs and WithGroup calls when Extras.Dedup is configured.
+	steps []dedupStep
 }
 
 // NewHandler returns a new sloggy handler with the specified output writer and slog.HandlerOptions.
 // If the options argument is nil it will be set to a level of slog.LevelInfo and nothing else.
 // If the extras argument is nil it will be set to defaults that match slog.JSONHandler.
+//
+// If extras.Async is set the handler writes log records from a background goroutine.
+// In this case the Close method should be called before the program exits.
 func NewHandler(writer io.Writer, options *slog.HandlerOptions, extras *Extras) *Handler {
 	hdlr := &Handler{
 		options: fixOptions(options),
@@ -38,6 +54,9 @@ func NewHandler(writer io.Writer, options *slog.HandlerOptions, extras *Extras)
 		prefix:  make([]byte, 0, lenPrefix),
 		suffix:  make([]byte, 0, lenSuffix),
 	}
+	if hdlr.extras.Async != nil {
+		hdlr.async = newAsyncWriter(writer, hdlr.extras.Async)
+	}
 	return hdlr
 }
 
@@ -49,15 +68,60 @@ func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
 
 var logPool = newArrayPool[byte](lenLog)
 
-func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 	// The x[:0] should reset len(x) to zero but leave cap(x) and
 	// the underlying array space intact for reuse.
 	buffer := logPool.get()[:0]
-	defer func() { logPool.put(buffer) }()
+	var queued bool
+	defer func() {
+		if !queued {
+			logPool.put(buffer)
+		}
+	}()
 
 	c := newComposer(buffer, false, h.options.ReplaceAttr, h.groups, h.extras)
 	defer reuseComposer(c)
+	switch h.extras.Format {
+	case FormatText:
+		if err := h.composeText(c, ctx, record); err != nil {
+			return err
+		}
+	case FormatCBOR, FormatMsgPack:
+		if err := h.composeBinary(c, ctx, record); err != nil {
+			return err
+		}
+	default:
+		if h.extras.otlp {
+			if err := h.composeOTLP(c, ctx, record); err != nil {
+				return err
+			}
+		} else if err := h.composeJSON(c, ctx, record); err != nil {
+			return err
+		}
+	}
+
+	if h.async != nil {
+		// The async writer owns the (possibly reallocated) buffer from here on.
+		queued = true
+		return h.async.enqueue(c.getBytes())
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, err := h.writer.Write(c.getBytes()); err != nil {
+		return fmt.Errorf("write log Line: %w", err)
+	}
+
+	return nil
+}
+
+// composeJSON composes a log record into the composer as a single line of JSON.
+func (h *Handler) composeJSON(c *composer, ctx context.Context, record slog.Record) error {
 	c.addBytes('{')
+	dedup := h.extras.Dedup != DedupNone
+	if dedup {
+		c.dedupBegin()
+	}
 
 	// Adding attributes to the composer one at a time instead of
 	// adding them to an array of attributes and
@@ -72,18 +136,37 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 			return fmt.Errorf("add time: %w", err)
 		}
 	}
-	if h.options.ReplaceAttr == nil {
+	if h.extras.LevelNumeric {
+		if h.options.ReplaceAttr == nil {
+			c.addSeparator()
+			c.addKey(h.extras.LevelKey)
+			c.buffer = strconv.AppendInt(c.buffer, int64(record.Level), 10)
+		} else if err := c.addAttribute(slog.Int(h.extras.LevelKey, int(record.Level))); err != nil {
+			return fmt.Errorf("add level: %w", err)
+		}
+	} else if h.options.ReplaceAttr == nil {
 		c.addSeparator()
 		c.addKey(h.extras.LevelKey)
-		c.addString(h.extras.LevelNames[record.Level])
-	} else if err := c.addAttribute(slog.String(h.extras.LevelKey, h.extras.LevelNames[record.Level])); err != nil {
+		c.addString(h.extras.levelName(record.Level))
+	} else if err := c.addAttribute(slog.String(h.extras.LevelKey, h.extras.levelName(record.Level))); err != nil {
 		return fmt.Errorf("add level: %w", err)
 	}
+	if h.extras.LevelNumberKey != "" {
+		number := int(record.Level) + h.extras.LevelNumberOffset
+		if h.options.ReplaceAttr == nil {
+			c.addSeparator()
+			c.addKey(h.extras.LevelNumberKey)
+			c.buffer = strconv.AppendInt(c.buffer, int64(number), 10)
+		} else if err := c.addAttribute(slog.Int(h.extras.LevelNumberKey, number)); err != nil {
+			return fmt.Errorf("add level number: %w", err)
+		}
+	}
+	message := h.extras.Limits.Message(record.Message)
 	if h.options.ReplaceAttr == nil {
 		c.addSeparator()
 		c.addKey(h.extras.MessageKey)
-		c.addString(record.Message)
-	} else if err := c.addAttribute(slog.String(h.extras.MessageKey, record.Message)); err != nil {
+		c.addString(message)
+	} else if err := c.addAttribute(slog.String(h.extras.MessageKey, message)); err != nil {
 		return fmt.Errorf("add message: %w", err)
 	}
 	if h.options.AddSource && record.PC != 0 {
@@ -91,7 +174,11 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 		// See BenchmarkSourceLoad and BenchmarkSourceNewReuse in speed_test.go.
 		var src source
 		loadSource(record.PC, &src)
-		if h.options.ReplaceAttr == nil {
+		if h.extras.SourceShape == SourceOTel {
+			if err := h.addSourceFields(c, &src); err != nil {
+				return fmt.Errorf("add source fields: %w", err)
+			}
+		} else if h.options.ReplaceAttr == nil {
 			c.addSeparator()
 			c.addKey(h.extras.SourceKey)
 			if err := c.addAny(&src); err != nil {
@@ -101,6 +188,21 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 			return fmt.Errorf("add source: %w", err)
 		}
 	}
+	if dedup {
+		c.dedupReserve(h.extras.TimeKey, h.extras.LevelKey, h.extras.MessageKey, h.extras.SourceKey)
+		if h.extras.LevelNumberKey != "" {
+			c.dedupReserve(h.extras.LevelNumberKey)
+		}
+		if h.extras.SourceShape == SourceOTel {
+			c.dedupReserve(OTelFunctionKey, OTelFileKey, OTelLineKey)
+		}
+	}
+	if err := h.addContext(c, ctx, c.addAttributes); err != nil {
+		return err
+	}
+	if dedup {
+		return h.composeDedup(c, record)
+	}
 
 	if len(h.prefix) > 0 {
 		c.addSeparator()
@@ -111,36 +213,32 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 		}
 	}
 
-	var err error
-	record.Attrs(func(attr slog.Attr) bool {
-		if err = c.addAttribute(attr); err != nil {
-			return false
-		}
-		return true // keep going
-	})
-	if err != nil {
-		return fmt.Errorf("add attribute: %w", err)
+	if err := h.addRecord(record, c.addAttribute); err != nil {
+		return err
 	}
 	if len(h.suffix) > 0 {
 		c.addByteArray(h.suffix)
 	}
 	c.addBytes('}', '\n')
-
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
-	if _, err := h.writer.Write(c.getBytes()); err != nil {
-		return fmt.Errorf("write log Line: %w", err)
-	}
-
 	return nil
 }
 
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.extras.Format == FormatText {
+		return h.withAttrsText(attrs)
+	}
+	if h.extras.Format.binary() {
+		return h.withAttrsBinary(attrs)
+	}
+	if h.extras.Dedup != DedupNone || h.extras.otlp {
+		return h.withAttrsDedup(attrs)
+	}
 	hdlr := &Handler{
 		options: h.options,
 		extras:  h.extras,
 		writer:  h.writer,
 		mutex:   h.mutex,
+		async:   h.async,
 		groups:  h.groups,
 	}
 	var prefixStarted bool
@@ -174,6 +272,15 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 		// Groups with empty names are to be inlined.
 		return h
 	}
+	if h.extras.Format == FormatText {
+		return h.withGroupText(name)
+	}
+	if h.extras.Format.binary() {
+		return h.withGroupBinary(name)
+	}
+	if h.extras.Dedup != DedupNone || h.extras.otlp {
+		return h.withGroupDedup(name)
+	}
 	var groups []string
 	if h.options.ReplaceAttr != nil {
 		// Only need this if there is a ReplaceAttr function.
@@ -186,6 +293,7 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 			extras:  h.extras,
 			writer:  h.writer,
 			mutex:   h.mutex,
+			async:   h.async,
 			groups:  groups,
 		},
 		name:   name,
@@ -212,6 +320,82 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 	return hdlr
 }
 
+// addContext adds any context attributes at the top level of the log record
+// using the specified composer function (which varies by output format).
+// Context attributes are never in a group so the composer groups are suppressed.
+func (h *Handler) addContext(c *composer, ctx context.Context, addFn func([]slog.Attr) error) error {
+	attrs := ctxattr.Resolve(ctx, h.extras.Context)
+	if len(attrs) < 1 {
+		return nil
+	}
+	groups := c.groups
+	c.groups = nil
+	defer func() { c.groups = groups }()
+	if err := addFn(attrs); err != nil {
+		return fmt.Errorf("add context attributes: %w", err)
+	}
+	return nil
+}
+
+// addRecord adds the log record attributes using the specified composer function
+// (which varies by output format), dropping any beyond the Extras.Limits maximum.
+func (h *Handler) addRecord(record slog.Record, addFn func(slog.Attr) error) error {
+	var err error
+	var count int
+	record.Attrs(func(attr slog.Attr) bool {
+		if !h.extras.Limits.Attrs(count) {
+			return false
+		}
+		count++
+		if err = addFn(attr); err != nil {
+			return false
+		}
+		return true // keep going
+	})
+	if err != nil {
+		return fmt.Errorf("add attribute: %w", err)
+	}
+	if dropped := record.NumAttrs() - count; dropped > 0 {
+		if err = addFn(h.extras.Limits.Dropped(dropped)); err != nil {
+			return fmt.Errorf("add dropped attribute count: %w", err)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Methods for asynchronous output.
+// These may be called on any handler derived from the original handler via WithAttrs or WithGroup.
+
+// Flush blocks until all log records queued prior to the call have been written.
+// The result is the first io.Writer error encountered since the last Flush, if any.
+// Without asynchronous output this method does nothing.
+func (h *Handler) Flush() error {
+	if h.async == nil {
+		return nil
+	}
+	return h.async.flush()
+}
+
+// Close writes any queued log records and stops the background writer goroutine.
+// Log records handled after Close return ErrClosed.
+// The io.Writer provided to NewHandler is not closed.
+// Without asynchronous output this method does nothing.
+func (h *Handler) Close() error {
+	if h.async == nil {
+		return nil
+	}
+	return h.async.close()
+}
+
+// Stats returns the current asynchronous output counters.
+func (h *Handler) Stats() AsyncStats {
+	if h.async == nil {
+		return AsyncStats{}
+	}
+	return h.async.stats()
+}
+
 // -----------------------------------------------------------------------------
 
 // fixOptions makes certain that a slog.HandlerOptions object has been properly created and
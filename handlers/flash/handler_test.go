@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/madkins23/go-slog/handlers/ctxattr"
 	"github.com/madkins23/go-slog/infra"
 	"github.com/madkins23/go-slog/internal/test"
 )
@@ -227,6 +228,46 @@ func (suite *HandlerTestSuite) TestWithGroupAttrSubEmpty() {
 	suite.Assert().Equal("3", group["third"])
 }
 
+func (suite *HandlerTestSuite) TestContextAttrs() {
+	ctx := ctxattr.With(context.Background(), slog.String("requestID", "r-1"), slog.Int("tenant", 7))
+	var replaceGroups []string
+	options := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "requestID" {
+				replaceGroups = groups
+			}
+			return a
+		},
+	}
+	hdlr := suite.newHandler(options, nil).
+		WithAttrs([]slog.Attr{slog.String("first", "one")}).
+		WithGroup("group")
+	record := slog.NewRecord(test.Now, slog.LevelInfo, message, 0)
+	record.AddAttrs(slog.String("second", "two"))
+	suite.Assert().NoError(hdlr.Handle(ctx, record))
+	suite.Assert().Contains(suite.String(), `"requestID": "r-1", "tenant": 7, "first": "one", "group"`)
+	logMap := suite.logMap()
+	suite.Assert().Equal("r-1", logMap["requestID"])
+	suite.Assert().Equal(map[string]any{"second": "two"}, logMap["group"])
+	suite.Assert().Nil(replaceGroups)
+	// Under a named group.
+	suite.Reset()
+	hdlr = suite.newHandler(nil, &Extras{Context: &ctxattr.Options{Group: "ctx"}}).WithGroup("group")
+	suite.Assert().NoError(hdlr.Handle(ctx, record))
+	logMap = suite.logMap()
+	suite.Assert().Equal(map[string]any{"requestID": "r-1", "tenant": float64(7)}, logMap["ctx"])
+	suite.Assert().Equal(map[string]any{"second": "two"}, logMap["group"])
+	// Text format.
+	suite.Reset()
+	hdlr = suite.newHandler(nil, &Extras{Format: FormatText, Context: &ctxattr.Options{Group: "ctx"}}).WithGroup("group")
+	suite.Assert().NoError(hdlr.Handle(ctx, record))
+	suite.Assert().Contains(suite.String(), ` ctx.requestID=r-1 ctx.tenant=7 group.second=two`)
+	// No context attributes.
+	suite.Reset()
+	suite.Assert().NoError(hdlr.Handle(context.Background(), record))
+	suite.Assert().NotContains(suite.String(), "ctx.")
+}
+
 func (suite *HandlerTestSuite) TestExtras() {
 	hdlr := suite.newHandler(nil, &Extras{
 		TimeFormat: time.DateTime,
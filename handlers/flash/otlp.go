@@ -0,0 +1,421 @@
+package flash
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
+)
+
+// This file contains the OTLP JSON encoding of log records for SchemaOTLP.
+//
+// Each log record is a single line containing a JSON object in the form of
+// an OpenTelemetry LogRecord message as defined by the OTLP protocol JSON encoding:
+//
+//	{"timeUnixNano": "...", "severityNumber": 9, "severityText": "INFO",
+//	 "body": {"stringValue": "..."}, "attributes": [{"key": "...", "value": {...}}]}
+//
+// Attributes are a list of key/value objects and groups are kvlistValue objects,
+// so WithAttrs and WithGroup output can't be pre-composed into the handler prefix.
+// Instead, the steps recorded for Extras.Dedup are replayed for each log record.
+// As with the protocol JSON encoding 64-bit integers are logged as decimal strings.
+
+// otlpGroup tracks a group opened by WithGroup during OTLP output.
+type otlpGroup struct {
+	// mark is the buffer offset at which the group attribute starts.
+	mark int
+	// started is the composer started flag before the group was opened.
+	started bool
+}
+
+// -----------------------------------------------------------------------------
+// Handler methods for OTLP output.
+
+// composeOTLP composes a log record into the composer as a single line of OTLP JSON.
+func (h *Handler) composeOTLP(c *composer, ctx context.Context, record slog.Record) error {
+	c.addBytes('{')
+	if !record.Time.IsZero() {
+		if err := c.addFieldOTLP(slog.Time(h.extras.TimeKey, record.Time)); err != nil {
+			return fmt.Errorf("add time: %w", err)
+		}
+	}
+	if h.extras.LevelNumberKey != "" {
+		number := int(record.Level) + h.extras.LevelNumberOffset
+		if err := c.addFieldOTLP(slog.Int(h.extras.LevelNumberKey, number)); err != nil {
+			return fmt.Errorf("add level number: %w", err)
+		}
+	}
+	var level slog.Attr
+	if h.extras.LevelNumeric {
+		level = slog.Int(h.extras.LevelKey, int(record.Level))
+	} else {
+		level = slog.String(h.extras.LevelKey, h.extras.levelName(record.Level))
+	}
+	if err := c.addFieldOTLP(level); err != nil {
+		return fmt.Errorf("add level: %w", err)
+	}
+	if err := c.addFieldOTLP(slog.String(h.extras.MessageKey, h.extras.Limits.Message(record.Message))); err != nil {
+		return fmt.Errorf("add message: %w", err)
+	}
+
+	mark, started := len(c.buffer), c.started
+	c.addSeparator()
+	c.addKey(OTLPAttributesKey)
+	c.addBytes('[')
+	c.reset() // Reset composer (started = false) to avoid comma.
+	if h.options.AddSource && record.PC != 0 {
+		var src source
+		loadSource(record.PC, &src)
+		if err := c.addAttributesOTLP([]slog.Attr{
+			slog.String(OTelFunctionKey, src.Function),
+			slog.String(OTelFileKey, src.File),
+			slog.Int(OTelLineKey, src.Line),
+		}); err != nil {
+			return fmt.Errorf("add source: %w", err)
+		}
+	}
+	if err := h.addContext(c, ctx, c.addAttributesOTLP); err != nil {
+		return err
+	}
+	var opened []otlpGroup
+	for _, step := range h.steps {
+		c.groups = step.groups
+		if step.group != "" {
+			opened = append(opened, otlpGroup{mark: len(c.buffer), started: c.started})
+			c.addSeparator()
+			c.addKeyOTLP(step.group)
+			c.buffer = append(c.buffer, `{"kvlistValue": {"values": [`...)
+			c.reset() // Reset composer (started = false) to avoid comma.
+		} else if err := c.addAttributesOTLP(step.attrs); err != nil {
+			return fmt.Errorf("add with attributes: %w", err)
+		}
+	}
+	c.groups = h.groups
+	if err := h.addRecord(record, c.addAttributeOTLP); err != nil {
+		return err
+	}
+	for i := len(opened) - 1; i >= 0; i-- {
+		if c.started {
+			c.addBytes(']', '}', '}', '}')
+		} else {
+			// Remove the empty group.
+			c.buffer = c.buffer[:opened[i].mark]
+			c.started = opened[i].started
+		}
+	}
+	if c.started {
+		c.addBytes(']')
+	} else {
+		// Remove the empty attribute list.
+		c.buffer = c.buffer[:mark]
+		c.started = started
+	}
+	c.addBytes('}', '\n')
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Composer methods for OTLP output.
+
+// addFieldOTLP adds a basic field (time, level, or message) to the log record.
+// The time is logged as a decimal string of nanoseconds and the message as an AnyValue object.
+func (c *composer) addFieldOTLP(attr slog.Attr) error {
+	message := attr.Key == c.extras.MessageKey
+	if c.replace != nil {
+		attr = c.replace(nil, attr)
+	}
+	if attr.Equal(infra.EmptyAttr()) {
+		return nil
+	}
+	c.addSeparator()
+	c.addKey(attr.Key)
+	switch kind := attr.Value.Kind(); {
+	case message:
+		return c.addValueOTLP(attr.Key, attr.Value, kind)
+	case kind == slog.KindTime:
+		c.buffer = append(c.buffer, '"')
+		c.buffer = strconv.AppendInt(c.buffer, attr.Value.Time().UnixNano(), 10)
+		c.buffer = append(c.buffer, '"')
+	case kind == slog.KindInt64:
+		c.buffer = strconv.AppendInt(c.buffer, attr.Value.Int64(), 10)
+	default:
+		c.addString(attr.Value.String())
+	}
+	return nil
+}
+
+func (c *composer) addAttributeOTLP(attr slog.Attr) error {
+	kind := attr.Value.Kind()
+	if kind == slog.KindLogValuer {
+		if !c.extras.Limits.Resolve(c.resolves + 1) {
+			attr.Value = slog.StringValue(limit.ResolveExceeded)
+		} else {
+			attr.Value = attr.Value.Resolve()
+			c.resolves++
+			defer func() { c.resolves-- }()
+		}
+		kind = attr.Value.Kind()
+	}
+	if c.replace != nil {
+		var groups []string
+		if !c.basicField[attr.Key] {
+			groups = c.groups
+		}
+		attr = c.replace(groups, attr)
+		kind = attr.Value.Kind()
+	}
+	if attr.Equal(infra.EmptyAttr()) {
+		return nil
+	}
+	value := attr.Value
+	if kind == slog.KindGroup {
+		if !c.extras.Limits.Group(c.depth + 1) {
+			value = slog.StringValue(limit.DepthExceeded)
+			kind = slog.KindString
+		} else if emptyGroup(value.Group(), c.extras.Limits.GroupRemaining(c.depth+1)) {
+			return nil
+		} else if attr.Key == "" {
+			c.depth++
+			err := c.addAttributesOTLP(value.Group())
+			c.depth--
+			if err != nil {
+				return fmt.Errorf("inline group attributes: %w", err)
+			}
+			return nil
+		}
+	}
+	c.addSeparator()
+	c.addKeyOTLP(attr.Key)
+	if err := c.addValueOTLP(attr.Key, value, kind); err != nil {
+		return err
+	}
+	c.addBytes('}')
+	return nil
+}
+
+func (c *composer) addAttributesOTLP(attrs []slog.Attr) error {
+	for _, attr := range attrs {
+		if err := c.addAttributeOTLP(attr); err != nil {
+			return fmt.Errorf("add attribute '%s': %w", attr.String(), err)
+		}
+	}
+	return nil
+}
+
+// addValueOTLP adds an attribute value of the specified kind as an AnyValue object.
+// The attribute key is used to determine whether the value is subject to limits.
+func (c *composer) addValueOTLP(key string, value slog.Value, kind slog.Kind) error {
+	switch kind {
+	case slog.KindGroup:
+		return c.addGroupOTLP(value.Group())
+	case slog.KindBool:
+		c.addBoolOTLP(value.Bool())
+	case slog.KindDuration:
+		d := value.Duration()
+		switch c.extras.DurationEncoding {
+		case DurationString:
+			c.addStringOTLP(d.String())
+		case DurationMillis:
+			c.addFloatOTLP(float64(d) / float64(time.Millisecond))
+		case DurationSeconds:
+			c.addFloatOTLP(float64(d) / float64(time.Second))
+		default:
+			c.addIntOTLP(d.Nanoseconds())
+		}
+	case slog.KindFloat64:
+		c.addFloatOTLP(value.Float64())
+	case slog.KindInt64:
+		c.addIntOTLP(value.Int64())
+	case slog.KindString:
+		c.addStringOTLP(c.limitValue(key, value.String()))
+	case slog.KindTime:
+		if c.extras.TimeEncoding == TimeLayout {
+			c.addStringOTLP(value.Time().Format(c.extras.TimeFormat))
+		} else {
+			c.buffer = append(c.buffer, `{"intValue": "`...)
+			c.buffer = appendUnixTime(c.buffer, value.Time(), c.extras.TimeEncoding)
+			c.buffer = append(c.buffer, '"', '}')
+		}
+	case slog.KindUint64:
+		if u := value.Uint64(); u <= math.MaxInt64 {
+			c.addIntOTLP(int64(u))
+		} else {
+			c.addStringOTLP(strconv.FormatUint(u, 10))
+		}
+	case slog.KindAny:
+		fallthrough
+	default:
+		return c.addAnyOTLP(value.Any())
+	}
+	return nil
+}
+
+// addGroupOTLP adds the attributes of a group value as a kvlistValue object.
+func (c *composer) addGroupOTLP(attrs []slog.Attr) error {
+	c.buffer = append(c.buffer, `{"kvlistValue": {"values": [`...)
+	c.reset() // Reset composer (started = false) to avoid comma.
+	c.depth++
+	err := c.addAttributesOTLP(attrs)
+	c.depth--
+	if err != nil {
+		return fmt.Errorf("add attributes: %w", err)
+	}
+	c.addBytes(']', '}', '}')
+	c.started = true
+	return nil
+}
+
+func (c *composer) addAnyOTLP(a any) (err error) {
+	mark := len(c.buffer)
+	defer func() {
+		if r := recover(); r != nil {
+			// Discard any partial output and report the panic as the value.
+			c.buffer = c.buffer[:mark]
+			c.addStringOTLP(panicString(a, r))
+			err = nil
+		}
+	}()
+	switch v := a.(type) {
+	case fmt.Stringer:
+		c.addStringOTLP(c.extras.Limits.Value(v.String()))
+	case error:
+		c.addStringOTLP(c.extras.Limits.Value(v.Error()))
+	case json.Marshaler:
+		if txt, err := v.MarshalJSON(); err != nil {
+			c.addStringOTLP("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal JSON: %w", err)
+		} else {
+			c.addStringOTLP(c.extras.Limits.Value(string(txt)))
+		}
+	case encoding.TextMarshaler:
+		if txt, err := v.MarshalText(); err != nil {
+			c.addStringOTLP("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal text: %w", err)
+		} else {
+			c.addStringOTLP(c.extras.Limits.Value(string(txt)))
+		}
+	default:
+		// Composite values are converted to their JSON structure so that
+		// field names and omitted fields match JSON output.
+		var decoded any
+		if b, err := json.Marshal(a); err != nil {
+			c.addStringOTLP("!ERROR:" + err.Error())
+		} else if c.extras.Limits.ValueTooLong(len(b)) {
+			c.addStringOTLP(c.extras.Limits.Value(string(b)))
+		} else if err := unmarshalNumbers(b, &decoded); err != nil {
+			c.addStringOTLP("!ERROR:" + err.Error())
+		} else {
+			c.addDecodedOTLP(decoded)
+		}
+	}
+	return nil
+}
+
+// addDecodedOTLP adds a value decoded from JSON via unmarshalNumbers as an AnyValue object.
+// Object fields are sorted by key so that the output is repeatable.
+func (c *composer) addDecodedOTLP(value any) {
+	switch v := value.(type) {
+	case nil:
+		// An empty AnyValue object represents a null value.
+		c.addBytes('{', '}')
+	case bool:
+		c.addBoolOTLP(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			c.addIntOTLP(i)
+		} else if f, err := v.Float64(); err == nil {
+			c.addFloatOTLP(f)
+		} else {
+			c.addStringOTLP(v.String())
+		}
+	case string:
+		c.addStringOTLP(v)
+	case []any:
+		c.buffer = append(c.buffer, `{"arrayValue": {"values": [`...)
+		for i, item := range v {
+			if i > 0 {
+				c.addBytes(',', ' ')
+			}
+			c.addDecodedOTLP(item)
+		}
+		c.addBytes(']', '}', '}')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		c.buffer = append(c.buffer, `{"kvlistValue": {"values": [`...)
+		for i, key := range keys {
+			if i > 0 {
+				c.addBytes(',', ' ')
+			}
+			c.addKeyOTLP(key)
+			c.addDecodedOTLP(v[key])
+			c.addBytes('}')
+		}
+		c.addBytes(']', '}', '}')
+	}
+}
+
+// unmarshalNumbers decodes JSON into a value, keeping numbers as json.Number
+// so that integers are not converted to floating point.
+func unmarshalNumbers(data []byte, value *any) error {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+func (c *composer) addBoolOTLP(b bool) {
+	if b {
+		c.buffer = append(c.buffer, `{"boolValue": true}`...)
+	} else {
+		c.buffer = append(c.buffer, `{"boolValue": false}`...)
+	}
+}
+
+// addFloatOTLP adds a floating point number as a doubleValue object.
+// Non-finite numbers are logged as strings as with the protocol JSON encoding.
+func (c *composer) addFloatOTLP(f float64) {
+	c.buffer = append(c.buffer, `{"doubleValue": `...)
+	switch {
+	case math.IsNaN(f):
+		c.buffer = append(c.buffer, `"NaN"`...)
+	case math.IsInf(f, 1):
+		c.buffer = append(c.buffer, `"Infinity"`...)
+	case math.IsInf(f, -1):
+		c.buffer = append(c.buffer, `"-Infinity"`...)
+	default:
+		c.buffer = strconv.AppendFloat(c.buffer, f, 'f', -1, 64)
+	}
+	c.buffer = append(c.buffer, '}')
+}
+
+// addKeyOTLP starts a key/value object with the specified key, leaving the value to be added.
+// The caller must close the object.
+func (c *composer) addKeyOTLP(key string) {
+	c.buffer = append(c.buffer, `{"key": `...)
+	c.addString(key)
+	c.buffer = append(c.buffer, `, "value": `...)
+}
+
+func (c *composer) addIntOTLP(i int64) {
+	c.buffer = append(c.buffer, `{"intValue": "`...)
+	c.buffer = strconv.AppendInt(c.buffer, i, 10)
+	c.buffer = append(c.buffer, '"', '}')
+}
+
+func (c *composer) addStringOTLP(str string) {
+	c.buffer = append(c.buffer, `{"stringValue": `...)
+	c.addString(str)
+	c.buffer = append(c.buffer, '}')
+}
@@ -0,0 +1,82 @@
+// Code generated by "enumer -type=Overflow"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _OverflowName = "OverflowBlockOverflowDropNewestOverflowDropOldest"
+
+var _OverflowIndex = [...]uint8{0, 13, 31, 49}
+
+const _OverflowLowerName = "overflowblockoverflowdropnewestoverflowdropoldest"
+
+func (i Overflow) String() string {
+	if i >= Overflow(len(_OverflowIndex)-1) {
+		return fmt.Sprintf("Overflow(%d)", i)
+	}
+	return _OverflowName[_OverflowIndex[i]:_OverflowIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _OverflowNoOp() {
+	var x [1]struct{}
+	_ = x[OverflowBlock-(0)]
+	_ = x[OverflowDropNewest-(1)]
+	_ = x[OverflowDropOldest-(2)]
+}
+
+var _OverflowValues = []Overflow{OverflowBlock, OverflowDropNewest, OverflowDropOldest}
+
+var _OverflowNameToValueMap = map[string]Overflow{
+	_OverflowName[0:13]:       OverflowBlock,
+	_OverflowLowerName[0:13]:  OverflowBlock,
+	_OverflowName[13:31]:      OverflowDropNewest,
+	_OverflowLowerName[13:31]: OverflowDropNewest,
+	_OverflowName[31:49]:      OverflowDropOldest,
+	_OverflowLowerName[31:49]: OverflowDropOldest,
+}
+
+var _OverflowNames = []string{
+	_OverflowName[0:13],
+	_OverflowName[13:31],
+	_OverflowName[31:49],
+}
+
+// OverflowString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func OverflowString(s string) (Overflow, error) {
+	if val, ok := _OverflowNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _OverflowNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Overflow values", s)
+}
+
+// OverflowValues returns all values of the enum
+func OverflowValues() []Overflow {
+	return _OverflowValues
+}
+
+// OverflowStrings returns a slice of all String values of the enum
+func OverflowStrings() []string {
+	strs := make([]string, len(_OverflowNames))
+	copy(strs, _OverflowNames)
+	return strs
+}
+
+// IsAOverflow returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Overflow) IsAOverflow() bool {
+	for _, v := range _OverflowValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package flash
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// Schema specifies a named preset for the field layout of JSON log records.
+// Setting Extras.Schema configures the key names, level vocabulary, source shape,
+// and time format of the basic fields in one step.
+// Any of those Extras fields that are explicitly set are not changed.
+//
+//go:generate go run github.com/dmarkham/enumer -type=Schema
+type Schema uint8
+
+const (
+	// SchemaNone uses the slog.JSONHandler field layout.
+	SchemaNone Schema = iota
+	// SchemaECS uses the Elastic Common Schema field layout:
+	// "@timestamp", "log.level" (lower case), "message", and "log.origin".
+	SchemaECS
+	// SchemaGCP uses the Google Cloud Logging structured logging field layout:
+	// "time", "severity" (Cloud Logging severity names), "message",
+	// and "logging.googleapis.com/sourceLocation".
+	SchemaGCP
+	// SchemaOTLP uses the OTLP JSON encoding of an OpenTelemetry log record:
+	// "timeUnixNano" (a decimal string), "severityNumber", "severityText",
+	// "body" (an AnyValue object such as {"stringValue": ...}),
+	// and "attributes" (a list of {"key": ..., "value": {...}} objects)
+	// with "code.function", "code.filepath", and "code.lineno" attributes for source data.
+	// The OTLP JSON encoding applies to FormatJSON only.
+	SchemaOTLP
+)
+
+// SourceShape specifies the JSON layout of source data.
+//
+//go:generate go run github.com/dmarkham/enumer -type=SourceShape
+type SourceShape uint8
+
+const (
+	// SourceSlog logs source data as {"function": ..., "file": ..., "line": N}, matching slog.JSONHandler.
+	SourceSlog SourceShape = iota
+	// SourceECS logs source data as {"file": {"name": ..., "line": N}, "function": ...}.
+	SourceECS
+	// SourceGCP logs source data as {"file": ..., "line": "N", "function": ...}
+	// with the line number as a string per the Cloud Logging LogEntrySourceLocation.
+	SourceGCP
+	// SourceOTel logs source data as separate top-level fields
+	// named per the OpenTelemetry semantic conventions (OTelFunctionKey, OTelFileKey, OTelLineKey)
+	// instead of a single field with the Extras.SourceKey.
+	SourceOTel
+)
+
+// OTLPAttributesKey is the JSON field name for the list of attributes with SchemaOTLP.
+const OTLPAttributesKey = "attributes"
+
+// Keys for source data fields with SourceOTel.
+const (
+	OTelFunctionKey = "code.function"
+	OTelFileKey     = "code.filepath"
+	OTelLineKey     = "code.lineno"
+)
+
+// OTelSeverityOffset converts a slog.Level to an OpenTelemetry severity number
+// (e.g. slog.LevelInfo = 0 becomes INFO = 9).
+const OTelSeverityOffset = 9
+
+// schemaExtras holds the preset Extras field values for each Schema.
+var schemaExtras = map[Schema]Extras{
+	SchemaECS: {
+		TimeKey:    "@timestamp",
+		TimeFormat: "2006-01-02T15:04:05.000Z07:00",
+		LevelKey:   "log.level",
+		LevelNames: map[slog.Level]string{
+			slog.LevelDebug:     "debug",
+			slog.LevelInfo:      "info",
+			slog.LevelWarn:      "warn",
+			slog.LevelError:     "error",
+			slog.LevelError + 4: "critical",
+		},
+		MessageKey:  "message",
+		SourceKey:   "log.origin",
+		SourceShape: SourceECS,
+	},
+	SchemaGCP: {
+		TimeKey:  "time",
+		LevelKey: "severity",
+		LevelNames: map[slog.Level]string{
+			slog.LevelDebug:     "DEBUG",
+			slog.LevelInfo:      "INFO",
+			slog.LevelWarn:      "WARNING",
+			slog.LevelError:     "ERROR",
+			slog.LevelError + 4: "CRITICAL",
+		},
+		MessageKey:  "message",
+		SourceKey:   "logging.googleapis.com/sourceLocation",
+		SourceShape: SourceGCP,
+	},
+	SchemaOTLP: {
+		TimeKey:      "timeUnixNano",
+		TimeEncoding: TimeUnixNanos,
+		LevelKey:     "severityText",
+		LevelNames: map[slog.Level]string{
+			slog.LevelDebug:     "DEBUG",
+			slog.LevelInfo:      "INFO",
+			slog.LevelWarn:      "WARN",
+			slog.LevelError:     "ERROR",
+			slog.LevelError + 4: "FATAL",
+		},
+		LevelNumberKey:    "severityNumber",
+		LevelNumberOffset: OTelSeverityOffset,
+		MessageKey:        "body",
+		SourceShape:       SourceOTel,
+	},
+}
+
+// applySchema sets any unset Extras fields to the values for the configured Schema.
+func (extras *Extras) applySchema() {
+	preset, found := schemaExtras[extras.Schema]
+	if !found {
+		return
+	}
+	if extras.TimeKey == "" {
+		extras.TimeKey = preset.TimeKey
+	}
+	if extras.TimeFormat == "" {
+		extras.TimeFormat = preset.TimeFormat
+	}
+	if extras.TimeEncoding == TimeLayout {
+		extras.TimeEncoding = preset.TimeEncoding
+	}
+	if extras.LevelKey == "" {
+		extras.LevelKey = preset.LevelKey
+	}
+	if extras.LevelNames == nil {
+		// Copy the map as fixExtras may add to it.
+		extras.LevelNames = make(map[slog.Level]string, len(preset.LevelNames))
+		for level, name := range preset.LevelNames {
+			extras.LevelNames[level] = name
+		}
+	}
+	if extras.LevelNumberKey == "" {
+		extras.LevelNumberKey = preset.LevelNumberKey
+		extras.LevelNumberOffset = preset.LevelNumberOffset
+	}
+	if extras.MessageKey == "" {
+		extras.MessageKey = preset.MessageKey
+	}
+	if extras.SourceKey == "" {
+		extras.SourceKey = preset.SourceKey
+	}
+	if extras.SourceShape == SourceSlog {
+		extras.SourceShape = preset.SourceShape
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// addSourceValue adds source data as a JSON object per Extras.SourceShape.
+// Returns false if nothing was added, leaving SourceSlog to json.Marshal
+// to match slog.JSONHandler exactly.
+// SourceOTel is handled by addSourceFields as it is not a single value.
+func (c *composer) addSourceValue(src *source) bool {
+	switch c.extras.SourceShape {
+	case SourceECS:
+		c.buffer = append(c.buffer, `{"file": {"name": `...)
+		c.buffer = AppendJSONString(c.buffer, src.File)
+		c.buffer = append(c.buffer, `, "line": `...)
+		c.buffer = strconv.AppendInt(c.buffer, int64(src.Line), 10)
+		c.buffer = append(c.buffer, `}, "function": `...)
+		c.buffer = AppendJSONString(c.buffer, src.Function)
+		c.buffer = append(c.buffer, '}')
+	case SourceGCP:
+		c.buffer = append(c.buffer, `{"file": `...)
+		c.buffer = AppendJSONString(c.buffer, src.File)
+		c.buffer = append(c.buffer, `, "line": "`...)
+		c.buffer = strconv.AppendInt(c.buffer, int64(src.Line), 10)
+		c.buffer = append(c.buffer, `", "function": `...)
+		c.buffer = AppendJSONString(c.buffer, src.Function)
+		c.buffer = append(c.buffer, '}')
+	default:
+		return false
+	}
+	return true
+}
+
+// addSourceFields adds source data as separate top-level fields for SourceOTel.
+func (h *Handler) addSourceFields(c *composer, src *source) error {
+	if h.options.ReplaceAttr == nil {
+		c.addSeparator()
+		c.addKey(OTelFunctionKey)
+		c.addString(src.Function)
+		c.addSeparator()
+		c.addKey(OTelFileKey)
+		c.addString(src.File)
+		c.addSeparator()
+		c.addKey(OTelLineKey)
+		c.buffer = strconv.AppendInt(c.buffer, int64(src.Line), 10)
+		return nil
+	}
+	if err := c.addAttribute(slog.String(OTelFunctionKey, src.Function)); err != nil {
+		return err
+	}
+	if err := c.addAttribute(slog.String(OTelFileKey, src.File)); err != nil {
+		return err
+	}
+	return c.addAttribute(slog.Int(OTelLineKey, src.Line))
+}
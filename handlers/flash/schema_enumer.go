@@ -0,0 +1,86 @@
+// Code generated by "enumer -type=Schema"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _SchemaName = "SchemaNoneSchemaECSSchemaGCPSchemaOTLP"
+
+var _SchemaIndex = [...]uint8{0, 10, 19, 28, 38}
+
+const _SchemaLowerName = "schemanoneschemaecsschemagcpschemaotlp"
+
+func (i Schema) String() string {
+	if i >= Schema(len(_SchemaIndex)-1) {
+		return fmt.Sprintf("Schema(%d)", i)
+	}
+	return _SchemaName[_SchemaIndex[i]:_SchemaIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _SchemaNoOp() {
+	var x [1]struct{}
+	_ = x[SchemaNone-(0)]
+	_ = x[SchemaECS-(1)]
+	_ = x[SchemaGCP-(2)]
+	_ = x[SchemaOTLP-(3)]
+}
+
+var _SchemaValues = []Schema{SchemaNone, SchemaECS, SchemaGCP, SchemaOTLP}
+
+var _SchemaNameToValueMap = map[string]Schema{
+	_SchemaName[0:10]:       SchemaNone,
+	_SchemaLowerName[0:10]:  SchemaNone,
+	_SchemaName[10:19]:      SchemaECS,
+	_SchemaLowerName[10:19]: SchemaECS,
+	_SchemaName[19:28]:      SchemaGCP,
+	_SchemaLowerName[19:28]: SchemaGCP,
+	_SchemaName[28:38]:      SchemaOTLP,
+	_SchemaLowerName[28:38]: SchemaOTLP,
+}
+
+var _SchemaNames = []string{
+	_SchemaName[0:10],
+	_SchemaName[10:19],
+	_SchemaName[19:28],
+	_SchemaName[28:38],
+}
+
+// SchemaString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func SchemaString(s string) (Schema, error) {
+	if val, ok := _SchemaNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _SchemaNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Schema values", s)
+}
+
+// SchemaValues returns all values of the enum
+func SchemaValues() []Schema {
+	return _SchemaValues
+}
+
+// SchemaStrings returns a slice of all String values of the enum
+func SchemaStrings() []string {
+	strs := make([]string, len(_SchemaNames))
+	copy(strs, _SchemaNames)
+	return strs
+}
+
+// IsASchema returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Schema) IsASchema() bool {
+	for _, v := range _SchemaValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
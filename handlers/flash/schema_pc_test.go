@@ -0,0 +1,11 @@
+package flash
+
+import "runtime"
+
+// schemaPC returns a program counter for source data in schema golden tests.
+// It is kept in its own file so that the line number in the golden files is stable.
+func schemaPC() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	return pcs[0]
+}
@@ -0,0 +1,131 @@
+package flash
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"log/slog"
+	"math"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/schema_ecs.golden
+var schemaECSGolden string
+
+//go:embed testdata/schema_gcp.golden
+var schemaGCPGolden string
+
+//go:embed testdata/schema_otlp.golden
+var schemaOTLPGolden string
+
+var schemaTime = time.Date(2024, 8, 21, 13, 14, 15, 123456789, time.UTC)
+
+// schemaLog logs a fixed set of records with the specified extras and returns the output.
+// The directory of the source file is removed so that the output is the same on any machine.
+// Flash escapes the slash characters in JSON strings so the directory is escaped the same way.
+func schemaLog(t *testing.T, extras *Extras, replace bool) string {
+	var buffer bytes.Buffer
+	options := &slog.HandlerOptions{AddSource: true, Level: slog.LevelDebug}
+	if replace {
+		options.ReplaceAttr = func(_ []string, a slog.Attr) slog.Attr { return a }
+	}
+	hdlr := NewHandler(&buffer, options, extras)
+	pc := schemaPC()
+	for _, level := range []slog.Level{
+		slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, slog.LevelError + 4,
+	} {
+		record := slog.NewRecord(schemaTime, level, "message", pc)
+		record.AddAttrs(slog.Int("count", int(level)))
+		require.NoError(t, hdlr.Handle(context.Background(), record))
+	}
+	record := slog.NewRecord(schemaTime, slog.LevelInfo, "with", pc)
+	record.AddAttrs(slog.Time("when", schemaTime), slog.Bool("done", true))
+	require.NoError(t, hdlr.
+		WithAttrs([]slog.Attr{slog.String("service", "test")}).
+		WithGroup("request").
+		Handle(context.Background(), record))
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	dir := string(appendEscaped(nil, []byte(filepath.Dir(frame.File)+"/")))
+	return strings.ReplaceAll(buffer.String(), dir, "")
+}
+
+func TestSchema_Golden(t *testing.T) {
+	for schema, golden := range map[Schema]string{
+		SchemaECS:  schemaECSGolden,
+		SchemaGCP:  schemaGCPGolden,
+		SchemaOTLP: schemaOTLPGolden,
+	} {
+		assert.Equal(t, golden, schemaLog(t, &Extras{Schema: schema}, false), schema.String())
+		// The ReplaceAttr code path must generate the same output.
+		assert.Equal(t, golden, schemaLog(t, &Extras{Schema: schema}, true), schema.String())
+	}
+}
+
+func TestSchema_Override(t *testing.T) {
+	extras := fixExtras(&Extras{
+		Schema:     SchemaGCP,
+		MessageKey: "msg",
+		LevelNames: map[slog.Level]string{slog.LevelWarn: "WARN"},
+	})
+	assert.Equal(t, "msg", extras.MessageKey)
+	assert.Equal(t, "severity", extras.LevelKey)
+	assert.Equal(t, SourceGCP, extras.SourceShape)
+	// Explicit level names replace the preset vocabulary.
+	assert.Equal(t, "WARN", extras.levelName(slog.LevelWarn))
+	assert.Equal(t, slog.LevelError.String(), extras.levelName(slog.LevelError))
+	// Preset level names are copied, not shared.
+	extras = fixExtras(&Extras{Schema: SchemaGCP})
+	extras.LevelNames[slog.LevelInfo] = "changed"
+	assert.Equal(t, "INFO", schemaExtras[SchemaGCP].LevelNames[slog.LevelInfo])
+}
+
+func TestSchema_Dedup(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(NewHandler(&buffer, nil, &Extras{Schema: SchemaGCP, Dedup: DedupIncrement}))
+	logger.Info("message", "severity", 1, "message", "duplicate")
+	assert.Contains(t, buffer.String(),
+		`"severity": "INFO", "message": "message", "severity#01": 1, "message#01": "duplicate"}`)
+}
+
+func TestSchema_OTLPValues(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(NewHandler(&buffer, nil, &Extras{Schema: SchemaOTLP}))
+	logger.Info("message")
+	assert.Contains(t, buffer.String(), `"body": {"stringValue": "message"}}`+"\n")
+
+	buffer.Reset()
+	logger.WithGroup("empty").Info("message",
+		"float", 1.5,
+		"nan", math.NaN(),
+		"unsigned", uint64(math.MaxUint64),
+		"duration", time.Second,
+		"any", map[string]any{"b": []any{1, "two", nil}, "a": 2.5},
+		"stringer", net.IPv4(127, 0, 0, 1),
+		"group", slog.GroupValue(slog.Int("inner", 1)))
+	assert.Contains(t, buffer.String(), `"attributes": [{"key": "empty", "value": {"kvlistValue": {"values": [`+
+		`{"key": "float", "value": {"doubleValue": 1.5}}, `+
+		`{"key": "nan", "value": {"doubleValue": "NaN"}}, `+
+		`{"key": "unsigned", "value": {"stringValue": "18446744073709551615"}}, `+
+		`{"key": "duration", "value": {"intValue": "1000000000"}}, `+
+		`{"key": "any", "value": {"kvlistValue": {"values": [`+
+		`{"key": "a", "value": {"doubleValue": 2.5}}, `+
+		`{"key": "b", "value": {"arrayValue": {"values": [{"intValue": "1"}, {"stringValue": "two"}, {}]}}}]}}}, `+
+		`{"key": "stringer", "value": {"stringValue": "127.0.0.1"}}, `+
+		`{"key": "group", "value": {"kvlistValue": {"values": [{"key": "inner", "value": {"intValue": "1"}}]}}}]}}}]}`+"\n")
+
+	// Empty groups from WithGroup are removed.
+	buffer.Reset()
+	logger.With("with", true).WithGroup("empty").Info("message")
+	assert.Contains(t, buffer.String(), `"attributes": [{"key": "with", "value": {"boolValue": true}}]}`+"\n")
+	buffer.Reset()
+	logger.WithGroup("empty").WithGroup("inner").Info("message")
+	assert.Contains(t, buffer.String(), `"body": {"stringValue": "message"}}`+"\n")
+}
@@ -0,0 +1,86 @@
+// Code generated by "enumer -type=SourceShape"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _SourceShapeName = "SourceSlogSourceECSSourceGCPSourceOTel"
+
+var _SourceShapeIndex = [...]uint8{0, 10, 19, 28, 38}
+
+const _SourceShapeLowerName = "sourceslogsourceecssourcegcpsourceotel"
+
+func (i SourceShape) String() string {
+	if i >= SourceShape(len(_SourceShapeIndex)-1) {
+		return fmt.Sprintf("SourceShape(%d)", i)
+	}
+	return _SourceShapeName[_SourceShapeIndex[i]:_SourceShapeIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _SourceShapeNoOp() {
+	var x [1]struct{}
+	_ = x[SourceSlog-(0)]
+	_ = x[SourceECS-(1)]
+	_ = x[SourceGCP-(2)]
+	_ = x[SourceOTel-(3)]
+}
+
+var _SourceShapeValues = []SourceShape{SourceSlog, SourceECS, SourceGCP, SourceOTel}
+
+var _SourceShapeNameToValueMap = map[string]SourceShape{
+	_SourceShapeName[0:10]:       SourceSlog,
+	_SourceShapeLowerName[0:10]:  SourceSlog,
+	_SourceShapeName[10:19]:      SourceECS,
+	_SourceShapeLowerName[10:19]: SourceECS,
+	_SourceShapeName[19:28]:      SourceGCP,
+	_SourceShapeLowerName[19:28]: SourceGCP,
+	_SourceShapeName[28:38]:      SourceOTel,
+	_SourceShapeLowerName[28:38]: SourceOTel,
+}
+
+var _SourceShapeNames = []string{
+	_SourceShapeName[0:10],
+	_SourceShapeName[10:19],
+	_SourceShapeName[19:28],
+	_SourceShapeName[28:38],
+}
+
+// SourceShapeString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func SourceShapeString(s string) (SourceShape, error) {
+	if val, ok := _SourceShapeNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _SourceShapeNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to SourceShape values", s)
+}
+
+// SourceShapeValues returns all values of the enum
+func SourceShapeValues() []SourceShape {
+	return _SourceShapeValues
+}
+
+// SourceShapeStrings returns a slice of all String values of the enum
+func SourceShapeStrings() []string {
+	strs := make([]string, len(_SourceShapeNames))
+	copy(strs, _SourceShapeNames)
+	return strs
+}
+
+// IsASourceShape returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i SourceShape) IsASourceShape() bool {
+	for _, v := range _SourceShapeValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package flash
 import (
 	"bytes"
 	"log/slog"
+	"net"
 	"runtime"
 	"strconv"
 	"sync"
@@ -333,3 +334,43 @@ func BenchmarkEscapeAddEscaped(b *testing.B) {
 		}
 	})
 }
+
+// -----------------------------------------------------------------------------
+// Compare registered encoders with json.Marshal for slog.KindAny values.
+//
+// scripts/comp handlers/flash Encode
+
+var encodeValues = []any{
+	net.IPv4(192, 168, 0, 1),
+	[16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+	[]string{"alpha", "omega"},
+}
+
+// BenchmarkEncodeMarshal composes slog.KindAny values without registered encoders.
+func BenchmarkEncodeMarshal(b *testing.B) {
+	benchmarkEncode(b, fixExtras(&Extras{Encoders: &Encoders{}}))
+}
+
+// BenchmarkEncodeRegistry composes slog.KindAny values using the built-in encoders.
+func BenchmarkEncodeRegistry(b *testing.B) {
+	encoders := NewEncoders()
+	RegisterEncoder(encoders, AppendUUID)
+	benchmarkEncode(b, fixExtras(&Extras{Encoders: encoders}))
+}
+
+func benchmarkEncode(b *testing.B, extras *Extras) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		buffer := make([]byte, 0, lenLog)
+		for pb.Next() {
+			c := newComposer(buffer[:0], true, nil, nil, extras)
+			for _, value := range encodeValues {
+				if err := c.addAny(value); err != nil {
+					b.Errorf("add any: %s", err.Error())
+				}
+			}
+			reuseComposer(c)
+		}
+	})
+}
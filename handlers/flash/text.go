@@ -0,0 +1,537 @@
+package flash
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
+)
+
+// This file contains the text (logfmt) output format for flash.Handler.
+//
+// Text records are lines of space-separated key=value pairs.
+// Attributes in groups have keys prefixed by the group names separated by dots.
+// String values are quoted whenever they would otherwise be mistaken for
+// numbers, booleans, or null, or contain spaces or other special characters.
+// Composite values that don't have a string representation are shown as JSON.
+
+const (
+	// DefaultMessageWidth is a reasonable value for TextOptions.MessageWidth.
+	DefaultMessageWidth = 40
+
+	colorReset = "\x1b[0m"
+)
+
+// TextOptions defines options for FormatText output.
+type TextOptions struct {
+	// Color adds ANSI terminal color codes to level values.
+	Color bool
+
+	// AlignLevel pads level values with spaces to the length of the longest level name,
+	// including fallback names such as "INFO+2",
+	// so that the message field always starts in the same column.
+	AlignLevel bool
+
+	// MessageWidth pads the message field with spaces to at least the specified width
+	// so that keys following short messages line up.
+	// See DefaultMessageWidth for a reasonable value.
+	MessageWidth uint
+
+	// LevelColors maps levels to ANSI color codes (e.g. "\x1b[32m") when Color is true.
+	// Levels that are not configured are colored by the next lower configured level.
+	// If not configured defaults to the value of DefaultLevelColors.
+	LevelColors map[slog.Level]string
+}
+
+// DefaultLevelColors provides the default ANSI color codes used for each level.
+var DefaultLevelColors = map[slog.Level]string{
+	slog.LevelDebug: "\x1b[36m", // cyan
+	slog.LevelInfo:  "\x1b[32m", // green
+	slog.LevelWarn:  "\x1b[33m", // yellow
+	slog.LevelError: "\x1b[31m", // red
+}
+
+// -----------------------------------------------------------------------------
+// Handler methods for text output.
+
+// composeText composes a log record into the composer as a single line of text.
+func (h *Handler) composeText(c *composer, ctx context.Context, record slog.Record) error {
+	c.keyPrefix = c.keyPrefix[:0]
+	if !record.Time.IsZero() {
+		if h.options.ReplaceAttr == nil {
+			c.addSeparatorText()
+			c.addKeyText(h.extras.TimeKey)
+			c.addTimeText(record.Time)
+		} else if err := c.addAttributeText(slog.Time(h.extras.TimeKey, record.Time)); err != nil {
+			return fmt.Errorf("add time: %w", err)
+		}
+	}
+	levelName := h.extras.levelName(record.Level)
+	if h.extras.LevelNumeric {
+		// Numeric levels are not colorized or aligned.
+		if err := c.addAttributeText(slog.Int(h.extras.LevelKey, int(record.Level))); err != nil {
+			return fmt.Errorf("add level: %w", err)
+		}
+	} else if h.options.ReplaceAttr == nil {
+		c.addSeparatorText()
+		c.addKeyText(h.extras.LevelKey)
+		c.addLevelText(record.Level, levelName)
+	} else if attr := h.options.ReplaceAttr(nil, slog.String(h.extras.LevelKey, levelName)); attr.Key == h.extras.LevelKey &&
+		attr.Value.Kind() == slog.KindString {
+		// Still a level, format it as such.
+		c.addSeparatorText()
+		c.addKeyText(attr.Key)
+		c.addLevelText(record.Level, attr.Value.String())
+	} else if err := c.addReplacedText(attr); err != nil {
+		return fmt.Errorf("add level: %w", err)
+	}
+	message := h.extras.Limits.Message(record.Message)
+	if h.options.ReplaceAttr == nil {
+		c.addSeparatorText()
+		c.addKeyText(h.extras.MessageKey)
+		c.addMessageText(message)
+	} else if attr := h.options.ReplaceAttr(nil, slog.String(h.extras.MessageKey, message)); attr.Key == h.extras.MessageKey &&
+		attr.Value.Kind() == slog.KindString {
+		c.addSeparatorText()
+		c.addKeyText(attr.Key)
+		c.addMessageText(attr.Value.String())
+	} else if err := c.addReplacedText(attr); err != nil {
+		return fmt.Errorf("add message: %w", err)
+	}
+	if h.options.AddSource && record.PC != 0 {
+		var src source
+		loadSource(record.PC, &src)
+		if err := c.addAttributeText(slog.Any(h.extras.SourceKey, &src)); err != nil {
+			return fmt.Errorf("add source: %w", err)
+		}
+	}
+	if err := h.addContext(c, ctx, c.addAttributesText); err != nil {
+		return err
+	}
+
+	// Prefix fields are stored with leading spaces so no separator is required.
+	if len(h.prefix) > 0 {
+		if c.started {
+			c.addByteArray(h.prefix)
+		} else {
+			// All basic fields were removed by ReplaceAttr.
+			c.addByteArray(h.prefix[1:])
+			c.started = true
+		}
+	}
+	c.keyPrefix = append(c.keyPrefix, h.keyPrefix...)
+
+	if err := h.addRecord(record, c.addAttributeText); err != nil {
+		return err
+	}
+	c.addBytes('\n')
+	return nil
+}
+
+func (h *Handler) withAttrsText(attrs []slog.Attr) slog.Handler {
+	hdlr := &Handler{
+		options:   h.options,
+		extras:    h.extras,
+		writer:    h.writer,
+		mutex:     h.mutex,
+		async:     h.async,
+		groups:    h.groups,
+		keyPrefix: h.keyPrefix,
+	}
+	// Always copy the prefix so that sibling handlers don't share the array.
+	prefix := make([]byte, len(h.prefix), len(h.prefix)+lenPrefix)
+	copy(prefix, h.prefix)
+	c := newComposer(prefix, true, h.options.ReplaceAttr, h.groups, h.extras)
+	defer reuseComposer(c)
+	c.keyPrefix = append(c.keyPrefix[:0], h.keyPrefix...)
+	if err := c.addAttributesText(attrs); err != nil {
+		slog.Error("adding with attributes", "err", err)
+	}
+	hdlr.prefix = c.getBytes()
+	return hdlr
+}
+
+func (h *Handler) withGroupText(name string) slog.Handler {
+	var groups []string
+	if h.options.ReplaceAttr != nil {
+		groups = append(h.groups, name)
+	}
+	return &group{
+		Handler: &Handler{
+			options:   h.options,
+			extras:    h.extras,
+			writer:    h.writer,
+			mutex:     h.mutex,
+			async:     h.async,
+			groups:    groups,
+			prefix:    h.prefix,
+			keyPrefix: h.keyPrefix + name + ".",
+		},
+		name:   name,
+		parent: h,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Composer methods for text output.
+
+func (c *composer) addAttributeText(attr slog.Attr) error {
+	if attr.Value.Kind() == slog.KindLogValuer {
+		if !c.extras.Limits.Resolve(c.resolves + 1) {
+			attr.Value = slog.StringValue(limit.ResolveExceeded)
+		} else {
+			attr.Value = attr.Value.Resolve()
+			c.resolves++
+			defer func() { c.resolves-- }()
+		}
+	}
+	if c.replace != nil {
+		var groups []string
+		if !c.basicField[attr.Key] {
+			groups = c.groups
+		}
+		attr = c.replace(groups, attr)
+	}
+	return c.addReplacedText(attr)
+}
+
+// addReplacedText adds an attribute that has already been passed through ReplaceAttr.
+func (c *composer) addReplacedText(attr slog.Attr) error {
+	if attr.Equal(infra.EmptyAttr()) {
+		return nil
+	}
+	value := attr.Value
+	kind := value.Kind()
+	if kind == slog.KindAny {
+		if src, ok := value.Any().(*source); ok {
+			// Show source data as a group so that it has dotted keys.
+			value = slog.GroupValue(
+				slog.String("function", src.Function),
+				slog.String("file", src.File),
+				slog.Int("line", src.Line))
+			kind = slog.KindGroup
+		}
+	}
+	if kind == slog.KindGroup && !c.extras.Limits.Group(c.depth+1) {
+		value = slog.StringValue(limit.DepthExceeded)
+		kind = slog.KindString
+	}
+	if kind == slog.KindGroup {
+		if emptyGroup(value.Group(), c.extras.Limits.GroupRemaining(c.depth+1)) {
+			return nil
+		}
+		c.depth++
+		defer func() { c.depth-- }()
+		if attr.Key == "" {
+			if err := c.addAttributesText(value.Group()); err != nil {
+				return fmt.Errorf("inline group attributes: %w", err)
+			}
+			return nil
+		}
+		// Group fields are shown with the group name as a key prefix.
+		mark := len(c.keyPrefix)
+		c.keyPrefix = append(c.keyPrefix, attr.Key...)
+		c.keyPrefix = append(c.keyPrefix, '.')
+		err := c.addAttributesText(value.Group())
+		c.keyPrefix = c.keyPrefix[:mark]
+		if err != nil {
+			return fmt.Errorf("add group attributes: %w", err)
+		}
+		return nil
+	}
+	c.addSeparatorText()
+	c.addKeyText(attr.Key)
+	switch kind {
+	case slog.KindBool:
+		c.buffer = strconv.AppendBool(c.buffer, value.Bool())
+	case slog.KindDuration:
+		c.addDurationText(value.Duration())
+	case slog.KindFloat64:
+		c.buffer = strconv.AppendFloat(c.buffer, value.Float64(), 'f', -1, 64)
+	case slog.KindInt64:
+		c.buffer = strconv.AppendInt(c.buffer, value.Int64(), 10)
+	case slog.KindString:
+		c.addStringText(c.limitValue(attr.Key, value.String()))
+	case slog.KindTime:
+		c.addTimeText(value.Time())
+	case slog.KindUint64:
+		c.buffer = strconv.AppendUint(c.buffer, value.Uint64(), 10)
+	case slog.KindAny:
+		fallthrough
+	default:
+		return c.addAnyText(value.Any())
+	}
+	return nil
+}
+
+func (c *composer) addAttributesText(attrs []slog.Attr) error {
+	for _, attr := range attrs {
+		if err := c.addAttributeText(attr); err != nil {
+			return fmt.Errorf("add attribute '%s': %w", attr.String(), err)
+		}
+	}
+	return nil
+}
+
+func (c *composer) addAnyText(a any) (err error) {
+	mark := len(c.buffer)
+	defer func() {
+		if r := recover(); r != nil {
+			// Discard any partial output and report the panic as the value.
+			c.buffer = c.buffer[:mark]
+			c.addStringText(panicString(a, r))
+			err = nil
+		}
+	}()
+	switch v := a.(type) {
+	case fmt.Stringer:
+		c.addStringText(c.extras.Limits.Value(v.String()))
+	case error:
+		c.addStringText(c.extras.Limits.Value(v.Error()))
+	case json.Marshaler:
+		if txt, err := v.MarshalJSON(); err != nil {
+			c.addStringText("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal JSON: %w", err)
+		} else {
+			c.addStringText(c.extras.Limits.Value(string(txt)))
+		}
+	case encoding.TextMarshaler:
+		if txt, err := v.MarshalText(); err != nil {
+			c.addStringText("!ERROR:" + err.Error())
+			return fmt.Errorf("marshal text: %w", err)
+		} else {
+			c.addStringText(c.extras.Limits.Value(string(txt)))
+		}
+	default:
+		// Composite values are shown as JSON, which is unambiguous.
+		if b, err := json.Marshal(a); err != nil {
+			c.addStringText("!ERROR:" + err.Error())
+		} else if c.extras.Limits.ValueTooLong(len(b)) {
+			c.addStringText(c.extras.Limits.Value(string(b)))
+		} else {
+			c.buffer = append(c.buffer, b...)
+		}
+	}
+	return nil
+}
+
+func (c *composer) addKeyText(key string) {
+	if len(c.keyPrefix) > 0 {
+		mark := len(c.keyPrefix)
+		c.keyPrefix = append(c.keyPrefix, key...)
+		c.addTokenText(c.keyPrefix, false)
+		c.keyPrefix = c.keyPrefix[:mark]
+	} else {
+		c.addTokenText([]byte(key), false)
+	}
+	c.buffer = append(c.buffer, '=')
+}
+
+func (c *composer) addLevelText(level slog.Level, name string) {
+	text := c.extras.Text
+	if text.Color {
+		c.buffer = append(c.buffer, levelColor(level, text.LevelColors)...)
+	}
+	mark := len(c.buffer)
+	c.addStringText(name)
+	width := len(c.buffer) - mark
+	if text.Color {
+		c.buffer = append(c.buffer, colorReset...)
+	}
+	if text.AlignLevel {
+		// Color codes don't take up any space on the terminal.
+		c.addPadding(len(c.buffer)-width, levelWidth(c.extras))
+	}
+}
+
+func (c *composer) addMessageText(msg string) {
+	mark := len(c.buffer)
+	c.addStringText(msg)
+	if c.extras.Text.MessageWidth > 0 {
+		c.addPadding(mark, int(c.extras.Text.MessageWidth))
+	}
+}
+
+// addPadding appends spaces until the buffer since the mark is at least the specified width.
+func (c *composer) addPadding(mark, width int) {
+	for n := len(c.buffer) - mark; n < width; n++ {
+		c.buffer = append(c.buffer, ' ')
+	}
+}
+
+func (c *composer) addSeparatorText() {
+	if !c.started {
+		c.started = true
+	} else {
+		c.buffer = append(c.buffer, ' ')
+	}
+}
+
+func (c *composer) addStringText(str string) {
+	c.addTokenText([]byte(str), true)
+}
+
+func (c *composer) addDurationText(d time.Duration) {
+	if c.extras.DurationEncoding == DurationString {
+		c.addStringText(d.String())
+		return
+	}
+	c.buffer = appendDuration(c.buffer, d, c.extras.DurationEncoding)
+}
+
+func (c *composer) addTimeText(t time.Time) {
+	if c.extras.TimeEncoding != TimeLayout {
+		c.buffer = appendUnixTime(c.buffer, t, c.extras.TimeEncoding)
+		return
+	}
+	var array [64]byte
+	c.addTokenText(t.AppendFormat(array[:0], c.extras.TimeFormat), true)
+}
+
+// addTokenText appends a key or string value, quoting and escaping if necessary.
+// Quoting is also required for values that would otherwise look like something other than a string.
+func (c *composer) addTokenText(token []byte, value bool) {
+	if !needsQuote(token, value) {
+		c.buffer = append(c.buffer, token...)
+		return
+	}
+	c.buffer = append(c.buffer, '"')
+	begin := 0
+	for index, b := range token {
+		var escape byte
+		switch b {
+		case '\\', '"':
+			escape = b
+		case '\n':
+			escape = 'n'
+		case '\r':
+			escape = 'r'
+		case '\t':
+			escape = 't'
+		default:
+			if b >= 0x20 && b != 0x7f {
+				continue
+			}
+		}
+		c.buffer = append(c.buffer, token[begin:index]...)
+		if escape != 0 {
+			c.buffer = append(c.buffer, '\\', escape)
+		} else {
+			c.buffer = append(c.buffer, `\u00`...)
+			c.buffer = append(c.buffer, hexDigit[b>>4], hexDigit[b&0xF])
+		}
+		begin = index + 1
+	}
+	c.buffer = append(c.buffer, token[begin:]...)
+	c.buffer = append(c.buffer, '"')
+}
+
+// -----------------------------------------------------------------------------
+
+// needsQuote returns true if the token must be quoted.
+// Values must also be quoted if they would be mistaken for JSON literals or composites.
+func needsQuote(token []byte, value bool) bool {
+	if len(token) == 0 {
+		return true
+	}
+	for _, b := range token {
+		if b <= ' ' || b == '=' || b == '"' || b == '\\' || b == 0x7f {
+			return true
+		}
+	}
+	if value {
+		switch token[0] {
+		case '{', '[':
+			return true
+		case 't', 'f', 'n':
+			s := string(token)
+			return s == "true" || s == "false" || s == "null"
+		}
+		return isNumber(token)
+	}
+	return false
+}
+
+// isNumber returns true if the token has JSON number syntax.
+func isNumber(token []byte) bool {
+	i := 0
+	if token[i] == '-' {
+		if i++; i >= len(token) {
+			return false
+		}
+	}
+	digits := func() int {
+		start := i
+		for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if digits() < 1 {
+		return false
+	}
+	if i < len(token) && token[i] == '.' {
+		i++
+		if digits() < 1 {
+			return false
+		}
+	}
+	if i < len(token) && (token[i] == 'e' || token[i] == 'E') {
+		i++
+		if i < len(token) && (token[i] == '+' || token[i] == '-') {
+			i++
+		}
+		if digits() < 1 {
+			return false
+		}
+	}
+	return i == len(token)
+}
+
+// levelColor returns the color for the specified level.
+// Levels without a configured color use the color of the next lower configured level.
+func levelColor(level slog.Level, colors map[slog.Level]string) string {
+	if colors == nil {
+		colors = DefaultLevelColors
+	}
+	if color, found := colors[level]; found {
+		return color
+	}
+	var best slog.Level
+	var color string
+	for lvl, clr := range colors {
+		if lvl <= level && (color == "" || lvl > best) {
+			best, color = lvl, clr
+		}
+	}
+	return color
+}
+
+// levelWidth returns the length of the longest level name,
+// including fallback names (e.g. "INFO+2") for levels between the configured ones.
+// Offsets beyond the highest or below the lowest configured level are assumed
+// to be a single digit, as they are with the standard slog levels.
+func levelWidth(extras *Extras) int {
+	var width int
+	for i, level := range extras.levelOrder {
+		name := extras.LevelNames[level]
+		offset := 9
+		if i+1 < len(extras.levelOrder) {
+			offset = int(extras.levelOrder[i+1]-level) - 1
+		}
+		width = max(width, len(name))
+		if offset > 0 {
+			width = max(width, len(name)+1+len(strconv.Itoa(offset)))
+		}
+	}
+	if len(extras.levelOrder) > 0 {
+		width = max(width, len(extras.LevelNames[extras.levelOrder[0]])+2)
+	}
+	return width
+}
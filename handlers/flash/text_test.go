@@ -0,0 +1,168 @@
+package flash
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// noTime is a ReplaceAttr function that removes the basic time field.
+func noTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return infra.EmptyAttr()
+	}
+	return a
+}
+
+func newTextLogger(buffer *bytes.Buffer, options *slog.HandlerOptions, text TextOptions) *slog.Logger {
+	return slog.New(NewHandler(buffer, options, &Extras{Format: FormatText, Text: text}))
+}
+
+func TestText(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, nil, TextOptions{})
+	logger.Info(test.Message,
+		"int", 23, "float", 3.5, "bool", true, "string", "Hello", "space", "x y",
+		"number", "12", "true", "true", "empty", "",
+		"duration", time.Second, "error", errors.New("failed"), "map", map[string]int{"x": 1},
+		"time", test.Now)
+	assert.Equal(t,
+		`level=INFO msg="`+test.Message+`" int=23 float=3.5 bool=true string=Hello space="x y"`+
+			` number="12" true="true" empty="" duration=1000000000 error=failed map={"x":1}`+
+			` time=`+test.Now.Format(time.RFC3339Nano)+"\n",
+		strings.SplitN(buffer.String(), " ", 2)[1])
+}
+
+func TestText_Escape(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: noTime,
+	}, TextOptions{})
+	logger.Info("line\nbreak", "quote", `say "hi"`, "a=b", 1, "ctl", "\x01", "json", "{x}")
+	assert.Equal(t,
+		`level=INFO msg="line\nbreak" quote="say \"hi\"" "a=b"=1 ctl="\u0001" json="{x}"`+"\n",
+		buffer.String())
+}
+
+func TestText_Groups(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: noTime,
+	}, TextOptions{})
+	logger.With("alpha", 1).WithGroup("group").With("bravo", 2).WithGroup("sub").
+		Info(test.Message, "charlie", 3, slog.Group("inner", "delta", 4), slog.Group("", "echo", 5),
+			slog.Group("empty"))
+	assert.Equal(t,
+		`level=INFO msg="`+test.Message+`" alpha=1 group.bravo=2`+
+			` group.sub.charlie=3 group.sub.inner.delta=4 group.sub.echo=5`+"\n",
+		buffer.String())
+}
+
+func TestText_WithAttrsSiblings(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: noTime,
+	}, TextOptions{})
+	parent := logger.With("parent", 1)
+	alpha := parent.With("alpha", 1)
+	bravo := parent.With("bravo", 2)
+	alpha.Info("a")
+	bravo.Info("b")
+	assert.Equal(t, "level=INFO msg=a parent=1 alpha=1\nlevel=INFO msg=b parent=1 bravo=2\n", buffer.String())
+}
+
+func TestText_Extras(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(NewHandler(&buffer, nil, &Extras{
+		Format:     FormatText,
+		TimeFormat: time.Kitchen,
+		LevelNames: map[slog.Level]string{slog.LevelInfo: "Information"},
+		LevelKey:   "lvl",
+		MessageKey: "message",
+		TimeKey:    "when",
+		SourceKey:  "src",
+	}))
+	logger.Info(test.Message)
+	assert.Regexp(t, `^when=\d{1,2}:\d{2}[AP]M `, buffer.String())
+	assert.Contains(t, buffer.String(), ` lvl=Information message="`+test.Message+`"`)
+}
+
+func TestText_Source(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{AddSource: true}, TextOptions{})
+	logger.Info(test.Message)
+	assert.Contains(t, buffer.String(), " source.function=github.com/madkins23/go-slog/handlers/flash.TestText_Source ")
+	assert.Contains(t, buffer.String(), " source.file=")
+	assert.Contains(t, buffer.String(), " source.line=")
+}
+
+func TestText_ReplaceAttr(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+				return infra.EmptyAttr()
+			case "secret":
+				assert.Equal(t, []string{"group"}, groups)
+				return slog.String(a.Key, "***")
+			}
+			return a
+		},
+	}, TextOptions{})
+	logger.With("alpha", 1).WithGroup("group").Info(test.Message, "secret", "password")
+	assert.Equal(t, "alpha=1 group.secret=***\n", buffer.String())
+}
+
+func TestText_Layout(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTextLogger(&buffer, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: noTime,
+	}, TextOptions{Color: true, AlignLevel: true, MessageWidth: 8})
+	logger.Info("msg", "a", 1)
+	logger.Error("message", "a", 1)
+	logger.Log(context.Background(), slog.LevelInfo+2, "msg")
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "level=\x1b[32mINFO\x1b[0m    msg=msg      a=1", lines[0])
+	assert.Equal(t, "level=\x1b[31mERROR\x1b[0m   msg=message  a=1", lines[1])
+	// Levels without configured colors use the color of the next lower level.
+	// Fallback level names are aligned as well.
+	assert.Equal(t, "level=\x1b[32mINFO+2\x1b[0m  msg=msg", lines[2])
+}
+
+func TestText_LevelWidth(t *testing.T) {
+	// Default names with fallbacks from "DEBUG-4" to "ERROR+9".
+	assert.Equal(t, 7, levelWidth(fixExtras(nil)))
+	// Adjacent configured levels have no fallback names between them,
+	// "CRITICAL+9" is the longest fallback name.
+	assert.Equal(t, 10, levelWidth(fixExtras(&Extras{LevelNames: map[slog.Level]string{
+		0: "INFO", 1: "NOTICE", 20: "CRITICAL",
+	}})))
+	// Wide gaps between configured levels have longer offsets ("ERROR+91").
+	assert.Equal(t, 8, levelWidth(fixExtras(&Extras{LevelNames: map[slog.Level]string{
+		0: "I", 100: "E",
+	}})))
+}
+
+func TestNeedsQuote(t *testing.T) {
+	for _, str := range []string{"", "a b", "a=b", `a"b`, `a\b`, "\x7f", "{", "[1]", "true", "null", "-1.5e3", "0"} {
+		assert.True(t, needsQuote([]byte(str), true), str)
+	}
+	for _, str := range []string{"alpha", "1.2.3", "-", "1e", "truth", "2024-01-02T03:04:05Z", "a/b"} {
+		assert.False(t, needsQuote([]byte(str), true), str)
+	}
+	assert.False(t, needsQuote([]byte("true"), false))
+	assert.False(t, needsQuote([]byte("23"), false))
+}
@@ -0,0 +1,86 @@
+// Code generated by "enumer -type=TimeEncoding"; DO NOT EDIT.
+
+package flash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _TimeEncodingName = "TimeLayoutTimeUnixSecondsTimeUnixMillisTimeUnixNanos"
+
+var _TimeEncodingIndex = [...]uint8{0, 10, 25, 39, 52}
+
+const _TimeEncodingLowerName = "timelayouttimeunixsecondstimeunixmillistimeunixnanos"
+
+func (i TimeEncoding) String() string {
+	if i >= TimeEncoding(len(_TimeEncodingIndex)-1) {
+		return fmt.Sprintf("TimeEncoding(%d)", i)
+	}
+	return _TimeEncodingName[_TimeEncodingIndex[i]:_TimeEncodingIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _TimeEncodingNoOp() {
+	var x [1]struct{}
+	_ = x[TimeLayout-(0)]
+	_ = x[TimeUnixSeconds-(1)]
+	_ = x[TimeUnixMillis-(2)]
+	_ = x[TimeUnixNanos-(3)]
+}
+
+var _TimeEncodingValues = []TimeEncoding{TimeLayout, TimeUnixSeconds, TimeUnixMillis, TimeUnixNanos}
+
+var _TimeEncodingNameToValueMap = map[string]TimeEncoding{
+	_TimeEncodingName[0:10]:       TimeLayout,
+	_TimeEncodingLowerName[0:10]:  TimeLayout,
+	_TimeEncodingName[10:25]:      TimeUnixSeconds,
+	_TimeEncodingLowerName[10:25]: TimeUnixSeconds,
+	_TimeEncodingName[25:39]:      TimeUnixMillis,
+	_TimeEncodingLowerName[25:39]: TimeUnixMillis,
+	_TimeEncodingName[39:52]:      TimeUnixNanos,
+	_TimeEncodingLowerName[39:52]: TimeUnixNanos,
+}
+
+var _TimeEncodingNames = []string{
+	_TimeEncodingName[0:10],
+	_TimeEncodingName[10:25],
+	_TimeEncodingName[25:39],
+	_TimeEncodingName[39:52],
+}
+
+// TimeEncodingString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func TimeEncodingString(s string) (TimeEncoding, error) {
+	if val, ok := _TimeEncodingNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _TimeEncodingNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to TimeEncoding values", s)
+}
+
+// TimeEncodingValues returns all values of the enum
+func TimeEncodingValues() []TimeEncoding {
+	return _TimeEncodingValues
+}
+
+// TimeEncodingStrings returns a slice of all String values of the enum
+func TimeEncodingStrings() []string {
+	strs := make([]string, len(_TimeEncodingNames))
+	copy(strs, _TimeEncodingNames)
+	return strs
+}
+
+// IsATimeEncoding returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i TimeEncoding) IsATimeEncoding() bool {
+	for _, v := range _TimeEncodingValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+// Package flight provides a "flight recorder" slog.Handler wrapper that
+// keeps recent low-level log records in memory and only writes them when something goes wrong.
+//
+// Records that the wrapped handler would not log (e.g. debug records when it is configured for info)
+// are kept in ring buffers instead of being written.
+// When a record at or above the trigger level (slog.LevelError by default) arrives,
+// the buffered records are passed to the wrapped handler, followed by the trigger record.
+// This provides debug detail for failures without the cost of debug output all the time.
+//
+// Records are buffered per key as returned by the [flight.Options] Key function.
+// By default the key is the scope attached to the context via NewScope,
+// so each request or group of related goroutines can have its own buffer.
+// Use ContextKey to key buffers on a preexisting context value such as a request ID.
+//
+// Memory is bounded by the size of each buffer and the maximum number of buffers.
+// The oldest record in a full buffer is dropped to make room for a new one and
+// the least recently used buffer is dropped to make room for a new buffer.
+//
+// [flight.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flight#Options
+package flight
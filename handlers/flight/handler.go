@@ -0,0 +1,105 @@
+package flight
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler wraps another slog.Handler and buffers log records that the wrapped handler
+// would not log, flushing them to the wrapped handler when a trigger record is logged.
+// Buffers are shared by the handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type Handler struct {
+	next     slog.Handler
+	recorder *recorder
+}
+
+// NewHandler returns a new flight recorder handler that wraps the next handler.
+// If the options argument is nil it will be set to the defaults.
+func NewHandler(next slog.Handler, options *Options) *Handler {
+	return &Handler{
+		next:     next,
+		recorder: newRecorder(fixOptions(options)),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.recorder.options.Level.Level() || h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= h.recorder.options.Trigger.Level() {
+		flushErr := h.Flush(ctx)
+		if h.next.Enabled(ctx, record.Level) {
+			if err := h.next.Handle(ctx, record); err != nil {
+				return err
+			}
+		}
+		return flushErr
+	}
+	if h.next.Enabled(ctx, record.Level) {
+		return h.next.Handle(ctx, record)
+	}
+	if record.Level >= h.recorder.options.Level.Level() {
+		// The record must be cloned as it is retained after Handle returns.
+		h.recorder.add(h.recorder.options.Key(ctx), entry{
+			ctx:     ctx,
+			handler: h.next,
+			record:  record.Clone(),
+		})
+	}
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{
+		next:     h.next.WithAttrs(attrs),
+		recorder: h.recorder,
+	}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{
+		next:     h.next.WithGroup(name),
+		recorder: h.recorder,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Flush passes any buffered records for the context's buffer key to the wrapped handler
+// without a trigger record.
+// Each record is passed to the wrapped handler derived via WithAttrs and WithGroup
+// in the same way as the handler with which it was logged.
+// All records are flushed even if there is an error, the first of which is returned.
+func (h *Handler) Flush(ctx context.Context) error {
+	var first error
+	for _, e := range h.recorder.take(h.recorder.options.Key(ctx)) {
+		h.recorder.flushed.Add(1)
+		if err := e.handler.Handle(e.ctx, e.record); err != nil && first == nil {
+			first = fmt.Errorf("flush buffered record: %w", err)
+		}
+	}
+	return first
+}
+
+// Discard drops any buffered records for the context's buffer key.
+// Call this when a scope (e.g. a request) completes successfully
+// so that its buffer doesn't use memory until it is dropped as least recently used.
+func (h *Handler) Discard(ctx context.Context) {
+	h.recorder.dropped.Add(uint64(len(h.recorder.take(h.recorder.options.Key(ctx)))))
+}
+
+// Stats returns cumulative counts of buffered, flushed, and dropped records.
+func (h *Handler) Stats() Stats {
+	return h.recorder.stats()
+}
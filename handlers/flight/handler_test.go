@@ -0,0 +1,138 @@
+package flight
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/json"
+)
+
+func newTestHandler(buffer *bytes.Buffer, options *Options) *Handler {
+	return NewHandler(flash.NewHandler(buffer, nil, nil), options)
+}
+
+func parse(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		logMap, err := json.Parse([]byte(line))
+		require.NoError(t, err)
+		result = append(result, logMap)
+	}
+	return result
+}
+
+func messages(t *testing.T, buffer *bytes.Buffer) []string {
+	var result []string
+	for _, logMap := range parse(t, buffer) {
+		result = append(result, logMap[slog.MessageKey].(string))
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+func TestTrigger(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, nil)
+	logger := slog.New(hdlr)
+	assert.True(t, hdlr.Enabled(context.Background(), slog.LevelDebug))
+	logger.Debug("debug1")
+	logger.Info("info")
+	logger.Debug("debug2")
+	assert.Equal(t, []string{"info"}, messages(t, &buffer))
+	logger.Error("error")
+	assert.Equal(t, []string{"info", "debug1", "debug2", "error"}, messages(t, &buffer))
+	logger.Error("again") // Buffer is empty after flush.
+	assert.Equal(t, []string{"info", "debug1", "debug2", "error", "again"}, messages(t, &buffer))
+	assert.Equal(t, Stats{Buffered: 2, Flushed: 2}, hdlr.Stats())
+}
+
+func TestLevels(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, &Options{Level: slog.LevelDebug + 2, Trigger: slog.LevelWarn})
+	logger := slog.New(hdlr)
+	assert.False(t, hdlr.Enabled(context.Background(), slog.LevelDebug))
+	logger.Debug("debug")
+	logger.Log(context.Background(), slog.LevelDebug+2, "debug+2")
+	logger.Warn("warn")
+	assert.Equal(t, []string{"debug+2", "warn"}, messages(t, &buffer))
+}
+
+func TestSize(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, &Options{Size: 2})
+	logger := slog.New(hdlr)
+	logger.Debug("one")
+	logger.Debug("two")
+	logger.Debug("three")
+	logger.Error("error")
+	assert.Equal(t, []string{"two", "three", "error"}, messages(t, &buffer))
+	assert.Equal(t, Stats{Buffered: 3, Flushed: 2, Dropped: 1}, hdlr.Stats())
+}
+
+func TestScopes(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, &Options{MaxBuffers: 2})
+	logger := slog.New(hdlr)
+	alpha := NewScope(context.Background())
+	bravo := NewScope(context.Background())
+	logger.DebugContext(alpha, "alpha")
+	logger.DebugContext(bravo, "bravo")
+	logger.Debug("none")
+	// Third buffer drops the least recently used (alpha).
+	logger.ErrorContext(alpha, "alpha error")
+	logger.ErrorContext(bravo, "bravo error")
+	assert.Equal(t, []string{"alpha error", "bravo", "bravo error"}, messages(t, &buffer))
+	buffer.Reset()
+	hdlr.Discard(context.Background())
+	logger.Error("error")
+	assert.Equal(t, []string{"error"}, messages(t, &buffer))
+	assert.Equal(t, Stats{Buffered: 3, Flushed: 1, Dropped: 2}, hdlr.Stats())
+}
+
+type requestKey struct{}
+
+func TestContextKey(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(newTestHandler(&buffer, &Options{Key: ContextKey(requestKey{})}))
+	first := context.WithValue(context.Background(), requestKey{}, "first")
+	second := context.WithValue(context.Background(), requestKey{}, "second")
+	logger.DebugContext(first, "first")
+	logger.DebugContext(second, "second")
+	logger.ErrorContext(context.WithValue(context.Background(), requestKey{}, "first"), "error")
+	assert.Equal(t, []string{"first", "error"}, messages(t, &buffer))
+}
+
+func TestWith(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, nil)
+	logger := slog.New(hdlr)
+	logger.With("alpha", 1).WithGroup("group").Debug("with", "bravo", 2)
+	logger.Debug("plain", "bravo", 3)
+	logger.Error("error")
+	logMaps := parse(t, &buffer)
+	require.Len(t, logMaps, 3)
+	assert.Equal(t, float64(1), logMaps[0]["alpha"])
+	assert.Equal(t, map[string]any{"bravo": float64(2)}, logMaps[0]["group"])
+	assert.NotContains(t, logMaps[1], "alpha")
+	assert.Equal(t, float64(3), logMaps[1]["bravo"])
+}
+
+func TestFlush(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := newTestHandler(&buffer, nil)
+	logger := slog.New(hdlr)
+	logger.Debug("debug")
+	require.NoError(t, hdlr.Flush(context.Background()))
+	assert.Equal(t, []string{"debug"}, messages(t, &buffer))
+}
@@ -0,0 +1,103 @@
+package flight
+
+import (
+	"context"
+	"log/slog"
+)
+
+const (
+	DefaultSize       = 100
+	DefaultMaxBuffers = 1000
+)
+
+// Options configures a flight.Handler.
+type Options struct {
+	// Level is the minimum level of records to be buffered.
+	// Records below this level are dropped if the wrapped handler isn't enabled for them.
+	// If not set defaults to slog.LevelDebug.
+	Level slog.Leveler
+
+	// Trigger is the level at which buffered records are flushed to the wrapped handler.
+	// If not set defaults to slog.LevelError.
+	Trigger slog.Leveler
+
+	// Size is the maximum number of records kept in each buffer.
+	// When a buffer is full the oldest record is dropped to make room.
+	// If not set defaults to the value of flight.DefaultSize.
+	Size int
+
+	// MaxBuffers is the maximum number of buffers.
+	// When a new buffer is required and there are already MaxBuffers
+	// the least recently used buffer is dropped.
+	// If not set defaults to the value of flight.DefaultMaxBuffers.
+	MaxBuffers int
+
+	// Key returns the buffer key for a log record from its context.
+	// Records with the same key share a buffer.
+	// Keys must be comparable, as with map keys.
+	// If not set defaults to ScopeKey.
+	Key func(ctx context.Context) any
+}
+
+// fixOptions makes certain that an Options object has been properly created and
+// configured with default values.
+func fixOptions(options *Options) *Options {
+	if options == nil {
+		options = &Options{}
+	}
+	if options.Level == nil {
+		options.Level = slog.LevelDebug
+	}
+	if options.Trigger == nil {
+		options.Trigger = slog.LevelError
+	}
+	if options.Size < 1 {
+		options.Size = DefaultSize
+	}
+	if options.MaxBuffers < 1 {
+		options.MaxBuffers = DefaultMaxBuffers
+	}
+	if options.Key == nil {
+		options.Key = ScopeKey
+	}
+	return options
+}
+
+// -----------------------------------------------------------------------------
+// Buffer key functions.
+
+type scopeKey struct{}
+
+// scope is attached to a context by NewScope.
+// It must not be a zero-size type so that each pointer is unique.
+type scope struct {
+	_ byte
+}
+
+// NewScope returns a copy of the context with a new flight recorder scope attached.
+// With the default Key function records logged with contexts derived from
+// the result share a buffer, separate from the buffers for other scopes.
+// Call NewScope at the start of each request or group of related goroutines.
+func NewScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{})
+}
+
+// ScopeKey returns the scope attached to the context by NewScope.
+// Records logged with contexts without a scope share a single buffer.
+func ScopeKey(ctx context.Context) any {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(scopeKey{})
+}
+
+// ContextKey returns a Key function that returns the value attached to
+// the context with the specified key (e.g. a request ID).
+func ContextKey(key any) func(ctx context.Context) any {
+	return func(ctx context.Context) any {
+		if ctx == nil {
+			return nil
+		}
+		return ctx.Value(key)
+	}
+}
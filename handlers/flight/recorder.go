@@ -0,0 +1,126 @@
+package flight
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats contains cumulative counters for a flight.Handler.
+type Stats struct {
+	// Buffered is the number of records added to buffers.
+	Buffered uint64
+
+	// Flushed is the number of buffered records passed to the wrapped handler.
+	Flushed uint64
+
+	// Dropped is the number of buffered records dropped to bound memory
+	// or discarded via Handler.Discard.
+	Dropped uint64
+}
+
+// entry is a single buffered log record along with the
+// wrapped handler and context with which it was logged.
+type entry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// ring is a fixed size circular buffer of entries for a single key.
+type ring struct {
+	key     any
+	entries []entry
+	start   int
+	count   int
+	element *list.Element
+}
+
+// add an entry to the ring, returning true if the oldest entry was dropped to make room.
+func (r *ring) add(e entry) bool {
+	if r.count < len(r.entries) {
+		r.entries[(r.start+r.count)%len(r.entries)] = e
+		r.count++
+		return false
+	}
+	r.entries[r.start] = e
+	r.start = (r.start + 1) % len(r.entries)
+	return true
+}
+
+// drain returns the entries in the ring from oldest to newest.
+func (r *ring) drain() []entry {
+	result := make([]entry, r.count)
+	for i := range result {
+		result[i] = r.entries[(r.start+i)%len(r.entries)]
+	}
+	return result
+}
+
+// recorder holds the buffers shared by a handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type recorder struct {
+	options *Options
+
+	lock  sync.Mutex
+	rings map[any]*ring
+	lru   *list.List // of *ring, most recently used at front
+
+	buffered atomic.Uint64
+	flushed  atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+func newRecorder(options *Options) *recorder {
+	return &recorder{
+		options: options,
+		rings:   make(map[any]*ring),
+		lru:     list.New(),
+	}
+}
+
+// add an entry to the buffer for the specified key, creating the buffer if necessary.
+func (r *recorder) add(key any, e entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	rng, found := r.rings[key]
+	if found {
+		r.lru.MoveToFront(rng.element)
+	} else {
+		if len(r.rings) >= r.options.MaxBuffers {
+			oldest := r.lru.Remove(r.lru.Back()).(*ring)
+			delete(r.rings, oldest.key)
+			r.dropped.Add(uint64(oldest.count))
+		}
+		rng = &ring{key: key, entries: make([]entry, r.options.Size)}
+		rng.element = r.lru.PushFront(rng)
+		r.rings[key] = rng
+	}
+	if rng.add(e) {
+		r.dropped.Add(1)
+	}
+	r.buffered.Add(1)
+}
+
+// take removes the buffer for the specified key, returning its entries from oldest to newest.
+func (r *recorder) take(key any) []entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	rng, found := r.rings[key]
+	if !found {
+		return nil
+	}
+	r.lru.Remove(rng.element)
+	delete(r.rings, key)
+	return rng.drain()
+}
+
+func (r *recorder) stats() Stats {
+	return Stats{
+		Buffered: r.buffered.Load(),
+		Flushed:  r.flushed.Load(),
+		Dropped:  r.dropped.Load(),
+	}
+}
@@ -0,0 +1,40 @@
+// Package oteltrace provides a slog.Handler wrapper that correlates log records with
+// OpenTelemetry traces.
+//
+// The [oteltrace.Handler] extracts the trace ID, span ID, and trace flags from
+// the OpenTelemetry SpanContext in the context.Context passed to Handle and
+// adds them to the log record before passing it to the wrapped handler:
+//
+//	logger := slog.New(oteltrace.NewHandler(flash.NewHandler(os.Stdout, nil, nil), nil))
+//	ctx, span := tracer.Start(ctx, "operation")
+//	defer span.End()
+//	logger.InfoContext(ctx, "working")
+//
+// generates something like:
+//
+//	{"time": "...", "level": "INFO", "msg": "working", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7", "trace_flags": "01"}
+//
+// Key names and placement (top level or a named group) are configured via [oteltrace.Options].
+// Trace attributes are attached to the context via the ctxattr package,
+// so they are never placed in groups opened by WithGroup and
+// are merged with any other context attributes.
+// The wrapped handler is itself wrapped in a ctxattr.Handler unless
+// [oteltrace.Options] field NativeContext is set for handlers that support
+// context attributes natively (flash and sloggy), which avoids the overhead of the wrapper:
+//
+//	logger := slog.New(oteltrace.NewHandler(flash.NewHandler(os.Stdout, nil, nil),
+//	    &oteltrace.Options{NativeContext: true}))
+//
+// Records logged without a valid SpanContext in the context are passed through unchanged.
+//
+// # Span Events
+//
+// Setting [oteltrace.Options] field SpanEvents also records log records at or above
+// a configured level (slog.LevelError by default) as events on the current span,
+// if that span is recording.
+// Only the attributes in the record itself are included in the event,
+// not those added to the logger via With.
+//
+// [oteltrace.Handler]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/oteltrace#Handler
+// [oteltrace.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/oteltrace#Options
+package oteltrace
@@ -0,0 +1,62 @@
+package oteltrace
+
+import (
+	"log/slog"
+	"math"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	eventLevelKey   = "log.severity"
+	eventMessageKey = "log.message"
+)
+
+// eventOptions returns span event options for the specified log record.
+func eventOptions(record slog.Record) []trace.EventOption {
+	kvs := make([]attribute.KeyValue, 0, 2+record.NumAttrs())
+	kvs = append(kvs,
+		attribute.String(eventLevelKey, record.Level.String()),
+		attribute.String(eventMessageKey, record.Message))
+	record.Attrs(func(attr slog.Attr) bool {
+		kvs = appendKeyValues(kvs, "", attr)
+		return true
+	})
+	options := []trace.EventOption{trace.WithAttributes(kvs...)}
+	if !record.Time.IsZero() {
+		options = append(options, trace.WithTimestamp(record.Time))
+	}
+	return options
+}
+
+// appendKeyValues converts a slog.Attr into OpenTelemetry attributes.
+// Groups are flattened using dotted key prefixes.
+func appendKeyValues(kvs []attribute.KeyValue, prefix string, attr slog.Attr) []attribute.KeyValue {
+	value := attr.Value.Resolve()
+	if attr.Key == "" && value.Kind() != slog.KindGroup {
+		return kvs
+	}
+	key := prefix + attr.Key
+	switch value.Kind() {
+	case slog.KindGroup:
+		if attr.Key != "" {
+			prefix = key + "."
+		}
+		for _, member := range value.Group() {
+			kvs = appendKeyValues(kvs, prefix, member)
+		}
+		return kvs
+	case slog.KindBool:
+		return append(kvs, attribute.Bool(key, value.Bool()))
+	case slog.KindFloat64:
+		return append(kvs, attribute.Float64(key, value.Float64()))
+	case slog.KindInt64:
+		return append(kvs, attribute.Int64(key, value.Int64()))
+	case slog.KindUint64:
+		if value.Uint64() <= math.MaxInt64 {
+			return append(kvs, attribute.Int64(key, int64(value.Uint64())))
+		}
+	}
+	return append(kvs, attribute.String(key, value.String()))
+}
@@ -0,0 +1,91 @@
+package oteltrace
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler wraps another slog.Handler and adds OpenTelemetry trace correlation attributes
+// to each log record handled with a context containing a valid SpanContext.
+//
+// Trace attributes are attached to the context via ctxattr.With and
+// placed in the log record by the ctxattr package, so they are never placed
+// in groups opened via WithGroup.
+// Unless Options.NativeContext is set the next handler is wrapped in a ctxattr.Handler.
+type Handler struct {
+	next    slog.Handler
+	options *Options
+}
+
+// NewHandler returns a new handler that adds trace attributes to records
+// before passing them to the next handler.
+// If the options argument is nil default key names are used at the top level
+// and no span events are recorded.
+func NewHandler(next slog.Handler, options *Options) *Handler {
+	options = fixOptions(options)
+	if !options.NativeContext {
+		next = ctxattr.NewHandler(next, nil)
+	}
+	return &Handler{
+		next:    next,
+		options: options,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx == nil {
+		return h.next.Handle(ctx, record)
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return h.next.Handle(ctx, record)
+	}
+	if h.options.SpanEvents && record.Level >= h.options.EventLevel.Level() {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.AddEvent(h.options.EventName, eventOptions(record)...)
+		}
+	}
+	return h.next.Handle(ctxattr.With(ctx, h.traceAttrs(spanCtx)...), record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) < 1 {
+		return h
+	}
+	return &Handler{next: h.next.WithAttrs(attrs), options: h.options}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{next: h.next.WithGroup(name), options: h.options}
+}
+
+// -----------------------------------------------------------------------------
+
+// traceAttrs returns the trace attributes for the SpanContext arranged per the options.
+func (h *Handler) traceAttrs(spanCtx trace.SpanContext) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(h.options.TraceIDKey, spanCtx.TraceID().String()),
+		slog.String(h.options.SpanIDKey, spanCtx.SpanID().String()),
+		slog.String(h.options.TraceFlagsKey, spanCtx.TraceFlags().String()),
+	}
+	if h.options.Group == "" {
+		return attrs
+	}
+	return []slog.Attr{{Key: h.options.Group, Value: slog.GroupValue(attrs...)}}
+}
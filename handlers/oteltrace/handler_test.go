@@ -0,0 +1,180 @@
+package oteltrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// -----------------------------------------------------------------------------
+// In-memory tracer for testing.
+
+// testTracer starts recording spans with sequential IDs.
+type testTracer struct {
+	noop.Tracer
+	mutex  sync.Mutex
+	nextID byte
+}
+
+// testSpan is a recording span that keeps events in memory.
+type testSpan struct {
+	noop.Span
+	spanCtx trace.SpanContext
+	mutex   sync.Mutex
+	events  []testEvent
+}
+
+type testEvent struct {
+	name   string
+	config trace.EventConfig
+}
+
+func (t *testTracer) Start(ctx context.Context, _ string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.nextID++
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	if !traceID.IsValid() {
+		traceID = trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, t.nextID}
+	}
+	span := &testSpan{
+		spanCtx: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, t.nextID},
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *testSpan) SpanContext() trace.SpanContext { return s.spanCtx }
+func (s *testSpan) IsRecording() bool              { return true }
+
+func (s *testSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.events = append(s.events, testEvent{name: name, config: trace.NewEventConfig(options...)})
+}
+
+// -----------------------------------------------------------------------------
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, span := (&testTracer{}).Start(context.Background(), "test")
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), nil)).
+		With("first", "one").
+		WithGroup("group")
+	logger.InfoContext(ctx, test.Message, "second", 2)
+	logMap := parseLine(t, buf.Bytes())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4701", logMap[DefaultTraceIDKey])
+	assert.Equal(t, "00f067aa0ba90201", logMap[DefaultSpanIDKey])
+	assert.Equal(t, "01", logMap[DefaultTraceFlagsKey])
+	assert.Equal(t, "one", logMap["first"])
+	assert.Equal(t, map[string]any{"second": float64(2)}, logMap["group"])
+	// No span events unless configured.
+	logger.ErrorContext(ctx, test.Message)
+	assert.Empty(t, span.(*testSpan).events)
+	// No trace attributes without a span.
+	buf.Reset()
+	logger.Info(test.Message)
+	assert.NotContains(t, buf.String(), DefaultTraceIDKey)
+}
+
+func TestHandler_Options(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, _ := (&testTracer{}).Start(context.Background(), "test")
+	slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{
+		TraceIDKey:    "traceId",
+		SpanIDKey:     "spanId",
+		TraceFlagsKey: "flags",
+		Group:         "otel",
+	})).WithGroup("group").InfoContext(ctx, test.Message, "key", "value")
+	logMap := parseLine(t, buf.Bytes())
+	assert.Equal(t, map[string]any{
+		"traceId": "4bf92f3577b34da6a3ce929d0e0e4701",
+		"spanId":  "00f067aa0ba90201",
+		"flags":   "01",
+	}, logMap["otel"])
+	assert.Equal(t, map[string]any{"key": "value"}, logMap["group"])
+}
+
+func TestHandler_NativeContext(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, _ := (&testTracer{}).Start(context.Background(), "test")
+	ctx = ctxattr.With(ctx, slog.String("request", "abc"))
+	slog.New(NewHandler(flash.NewHandler(&buf, nil, nil), &Options{NativeContext: true})).
+		WithGroup("group").InfoContext(ctx, test.Message, "key", "value")
+	logMap := parseLine(t, buf.Bytes())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4701", logMap[DefaultTraceIDKey])
+	assert.Equal(t, "00f067aa0ba90201", logMap[DefaultSpanIDKey])
+	assert.Equal(t, "abc", logMap["request"])
+	assert.Equal(t, map[string]any{"key": "value"}, logMap["group"])
+}
+
+func TestHandler_SpanEvents(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := &testTracer{}
+	ctx, span := tracer.Start(context.Background(), "test")
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{SpanEvents: true}))
+	logger.WarnContext(ctx, "not an event")
+	logger.ErrorContext(ctx, "failed",
+		"count", 3, slog.Group("request", "method", "GET", "ok", false), "elapsed", time.Second)
+	events := span.(*testSpan).events
+	require.Len(t, events, 1)
+	assert.Equal(t, DefaultEventName, events[0].name)
+	assert.False(t, events[0].config.Timestamp().IsZero())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(eventLevelKey, "ERROR"),
+		attribute.String(eventMessageKey, "failed"),
+		attribute.Int64("count", 3),
+		attribute.String("request.method", "GET"),
+		attribute.Bool("request.ok", false),
+		attribute.String("elapsed", "1s"),
+	}, events[0].config.Attributes())
+	// Level and event name are configurable.
+	ctx, span = tracer.Start(ctx, "child")
+	slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{
+		SpanEvents: true,
+		EventLevel: slog.LevelWarn,
+		EventName:  "slog",
+	})).WarnContext(ctx, "warning")
+	events = span.(*testSpan).events
+	require.Len(t, events, 1)
+	assert.Equal(t, "slog", events[0].name)
+	// Child span has the same trace ID and a new span ID.
+	assert.Equal(t, 3, strings.Count(buf.String(), "4bf92f3577b34da6a3ce929d0e0e4701"))
+	assert.Contains(t, buf.String(), "00f067aa0ba90202")
+}
+
+func TestHandler_slogtest(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, slogtest.TestHandler(NewHandler(slog.NewJSONHandler(&buf, nil), nil), func() []map[string]any {
+		var results []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte{'\n'}) {
+			results = append(results, parseLine(t, line))
+		}
+		return results
+	}))
+}
+
+func parseLine(t *testing.T, line []byte) map[string]any {
+	var logMap map[string]any
+	require.NoError(t, json.Unmarshal(line, &logMap))
+	return logMap
+}
@@ -0,0 +1,70 @@
+package oteltrace
+
+import "log/slog"
+
+const (
+	DefaultTraceIDKey    = "trace_id"
+	DefaultSpanIDKey     = "span_id"
+	DefaultTraceFlagsKey = "trace_flags"
+	DefaultEventName     = "log"
+)
+
+// Options configures an oteltrace.Handler.
+type Options struct {
+	// TraceIDKey is the attribute key for the trace ID.
+	// If not set defaults to DefaultTraceIDKey (= "trace_id").
+	TraceIDKey string
+
+	// SpanIDKey is the attribute key for the span ID.
+	// If not set defaults to DefaultSpanIDKey (= "span_id").
+	SpanIDKey string
+
+	// TraceFlagsKey is the attribute key for the trace flags (as two hex digits).
+	// If not set defaults to DefaultTraceFlagsKey (= "trace_flags").
+	TraceFlagsKey string
+
+	// Group is the name of the group under which trace attributes are placed.
+	// If not set trace attributes are placed at the top level of the log record.
+	Group string
+
+	// SpanEvents records log records at or above EventLevel as events on the current span.
+	SpanEvents bool
+
+	// EventLevel is the minimum level of log records recorded as span events.
+	// If not set defaults to slog.LevelError.
+	EventLevel slog.Leveler
+
+	// NativeContext specifies that the next handler adds context attributes natively
+	// (e.g. the flash and sloggy handlers), so it doesn't need to be wrapped in a ctxattr.Handler.
+	// Trace attributes are then placed per the next handler's context attribute configuration.
+	NativeContext bool
+
+	// EventName is the name of span events recorded for log records.
+	// The log message and level are event attributes.
+	// If not set defaults to DefaultEventName (= "log").
+	EventName string
+}
+
+// fixOptions makes certain that an Options object has been properly created and
+// configured with default values.
+func fixOptions(options *Options) *Options {
+	if options == nil {
+		options = &Options{}
+	}
+	if options.TraceIDKey == "" {
+		options.TraceIDKey = DefaultTraceIDKey
+	}
+	if options.SpanIDKey == "" {
+		options.SpanIDKey = DefaultSpanIDKey
+	}
+	if options.TraceFlagsKey == "" {
+		options.TraceFlagsKey = DefaultTraceFlagsKey
+	}
+	if options.EventLevel == nil {
+		options.EventLevel = slog.LevelError
+	}
+	if options.EventName == "" {
+		options.EventName = DefaultEventName
+	}
+	return options
+}
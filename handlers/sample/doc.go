@@ -0,0 +1,25 @@
+// Package sample provides a slog.Handler wrapper that samples and rate-limits log records.
+//
+// Log records are grouped by level and message.
+// Each group is limited by up to three stages configured by a [sample.Policy]:
+//
+//   - log the first N records in each time window and then every Mth record,
+//   - a token bucket that allows bursts but limits the long-term rate, and
+//   - probabilistic sampling that keeps a fixed fraction of records.
+//
+// A record must pass every configured stage to be passed to the wrapped handler.
+// Policies can be configured per level via [sample.Options].
+//
+// Dropped records are counted and periodically summarized by logging
+// a separate record to the wrapped handler.
+// Summaries are generated during calls to Handle or by a timer
+// when the summary interval is over and no further records have been handled.
+// Call the Close method before exiting to log the last summary.
+//
+// Sampling state is kept per level and message.
+// State that has returned to its initial condition (e.g. the window has expired)
+// is removed periodically so memory use doesn't grow when messages vary.
+//
+// [sample.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/sample#Options
+// [sample.Policy]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/sample#Policy
+package sample
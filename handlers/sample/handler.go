@@ -0,0 +1,82 @@
+package sample
+
+import (
+	"context"
+	"log/slog"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler wraps another slog.Handler and drops log records per the configured sampling policies.
+// Sampling state is shared by the handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type Handler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+// NewHandler returns a new sample handler that passes sampled records to the next handler.
+// If the options argument is nil all records are passed.
+func NewHandler(next slog.Handler, options *Options) *Handler {
+	return &Handler{
+		next:    next,
+		sampler: newSampler(next, fixOptions(options)),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Methods that implement the slog.Handler interface.
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	allow, summaryDue := h.sampler.allow(record.Level, record.Message)
+	if summaryDue {
+		if err := h.sampler.summarize(ctx); err != nil {
+			return err
+		}
+	}
+	if !allow {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{
+		next:    h.next.WithAttrs(attrs),
+		sampler: h.sampler,
+	}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{
+		next:    h.next.WithGroup(name),
+		sampler: h.sampler,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Summarize logs a summary record for any records dropped since the last summary.
+func (h *Handler) Summarize(ctx context.Context) error {
+	return h.sampler.summarize(ctx)
+}
+
+// Close stops the summary timer and logs a summary record for any records dropped
+// since the last summary unless summaries are disabled.
+// Call this before the program exits to make certain the last summary is logged.
+// Records may still be handled after Close, but summaries are only logged during Handle calls.
+func (h *Handler) Close() error {
+	return h.sampler.close(context.Background())
+}
+
+// Stats returns cumulative counts of passed and dropped records.
+func (h *Handler) Stats() Stats {
+	return h.sampler.stats()
+}
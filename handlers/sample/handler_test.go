@@ -0,0 +1,320 @@
+package sample
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/json"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// clock provides a manually advanced time source.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestHandler(buffer *bytes.Buffer, options *Options) (*Handler, *clock) {
+	clk := &clock{now: test.Now}
+	hdlr := NewHandler(flash.NewHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}, nil), options)
+	hdlr.sampler.now = clk.Now
+	hdlr.sampler.lastSummary = clk.now
+	hdlr.sampler.lastPrune = clk.now
+	return hdlr, clk
+}
+
+func messages(t *testing.T, buffer *bytes.Buffer) []string {
+	var result []string
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		logMap, err := json.Parse([]byte(line))
+		require.NoError(t, err)
+		result = append(result, logMap[slog.MessageKey].(string))
+	}
+	return result
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.String()
+}
+
+// -----------------------------------------------------------------------------
+
+func TestNoPolicy(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, nil)
+	logger := slog.New(hdlr)
+	for i := 0; i < 100; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Len(t, messages(t, &buffer), 100)
+	assert.Equal(t, Stats{Passed: 100}, hdlr.Stats())
+}
+
+func TestFirstThereafter(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, clk := newTestHandler(&buffer, &Options{
+		Default:         Policy{First: 2, Thereafter: 3},
+		SummaryInterval: -1,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 8; i++ {
+		logger.Info("alpha")
+	}
+	logger.Info("bravo") // Separate message is counted separately.
+	clk.advance(DefaultWindow)
+	logger.Info("alpha") // New window.
+	// alpha 1, 2 pass, then 5 and 8.
+	assert.Equal(t, []string{"alpha", "alpha", "alpha", "alpha", "bravo", "alpha"}, messages(t, &buffer))
+	assert.Equal(t, Stats{Passed: 6, Dropped: 4}, hdlr.Stats())
+}
+
+func TestFirstOnly(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{
+		Default:         Policy{First: 3},
+		SummaryInterval: -1,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 10; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Len(t, messages(t, &buffer), 3)
+}
+
+func TestTokenBucket(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, clk := newTestHandler(&buffer, &Options{
+		Default:         Policy{Rate: 2, Burst: 3},
+		SummaryInterval: -1,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 5; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Equal(t, Stats{Passed: 3, Dropped: 2}, hdlr.Stats())
+	clk.advance(time.Second) // Two more tokens.
+	for i := 0; i < 5; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Equal(t, Stats{Passed: 5, Dropped: 5}, hdlr.Stats())
+	clk.advance(time.Hour) // Bucket fills only to Burst.
+	for i := 0; i < 5; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Equal(t, Stats{Passed: 8, Dropped: 7}, hdlr.Stats())
+}
+
+func TestProbability(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{
+		Default:         Policy{Probability: 0.5},
+		SummaryInterval: -1,
+	})
+	values := []float64{0.1, 0.7, 0.4, 0.5, 0.9}
+	hdlr.sampler.random = func() float64 {
+		value := values[0]
+		values = values[1:]
+		return value
+	}
+	logger := slog.New(hdlr)
+	for i := 0; i < 5; i++ {
+		logger.Info(test.Message)
+	}
+	assert.Equal(t, Stats{Passed: 2, Dropped: 3}, hdlr.Stats())
+}
+
+func TestLevels(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{
+		Default: Policy{First: 1},
+		Levels: map[slog.Level]Policy{
+			slog.LevelInfo:  {First: 2},
+			slog.LevelError: {},
+		},
+		SummaryInterval: -1,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 5; i++ {
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warn("warn") // Uses the Info policy.
+		logger.Error("error")
+	}
+	counts := make(map[string]int)
+	for _, msg := range messages(t, &buffer) {
+		counts[msg]++
+	}
+	assert.Equal(t, map[string]int{"debug": 1, "info": 2, "warn": 2, "error": 5}, counts)
+}
+
+func TestOptions_Unchanged(t *testing.T) {
+	options := &Options{
+		Levels: map[slog.Level]Policy{slog.LevelInfo: {Rate: 5}},
+	}
+	var buffer bytes.Buffer
+	NewHandler(flash.NewHandler(&buffer, nil, nil), options)
+	NewHandler(flash.NewHandler(&buffer, nil, nil), options)
+	assert.Equal(t, &Options{
+		Levels: map[slog.Level]Policy{slog.LevelInfo: {Rate: 5}},
+	}, options)
+}
+
+func TestSummary(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, clk := newTestHandler(&buffer, &Options{
+		Default:         Policy{First: 1},
+		SummaryInterval: time.Minute,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 3; i++ {
+		logger.Info(test.Message)
+		logger.Debug(test.Message)
+	}
+	clk.advance(time.Minute)
+	logger.Info(test.Message) // Starts a new window so it is logged after the summary.
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 4)
+	logMap, err := json.Parse([]byte(lines[2]))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSummaryMessage, logMap[slog.MessageKey])
+	assert.Equal(t, slog.LevelWarn.String(), logMap[slog.LevelKey])
+	assert.Equal(t, float64(4), logMap["dropped"])
+	assert.Equal(t, map[string]any{"DEBUG": float64(2), "INFO": float64(2)}, logMap["levels"])
+
+	// Nothing further dropped so no summary.
+	buffer.Reset()
+	require.NoError(t, hdlr.Summarize(context.Background()))
+	assert.Empty(t, buffer.String())
+}
+
+func TestSummary_Root(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{Default: Policy{First: 1}})
+	logger := slog.New(hdlr).With("alpha", 1).WithGroup("group")
+	logger.Info(test.Message, "bravo", 2)
+	logger.Info(test.Message, "bravo", 2)
+	buffer.Reset()
+	require.NoError(t, hdlr.Summarize(context.Background()))
+	logMap, err := json.Parse(buffer.Bytes())
+	require.NoError(t, err)
+	assert.NotContains(t, logMap, "alpha")
+	assert.NotContains(t, logMap, "group")
+	assert.Equal(t, float64(1), logMap["dropped"])
+}
+
+func TestSummary_Timer(t *testing.T) {
+	// No further records are logged after records are dropped.
+	buffer := &lockedBuffer{}
+	hdlr := NewHandler(flash.NewHandler(buffer, nil, nil), &Options{
+		Default:         Policy{First: 1},
+		SummaryInterval: 20 * time.Millisecond,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 3; i++ {
+		logger.Info(test.Message)
+	}
+	require.Eventually(t, func() bool {
+		return strings.Contains(buffer.String(), DefaultSummaryMessage)
+	}, 5*time.Second, time.Millisecond)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+	logMap, err := json.Parse([]byte(lines[1]))
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), logMap["dropped"])
+	require.NoError(t, hdlr.Close())
+}
+
+func TestClose(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{Default: Policy{First: 1}})
+	logger := slog.New(hdlr)
+	logger.Info(test.Message)
+	logger.Info(test.Message)
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, []string{test.Message, DefaultSummaryMessage}, messages(t, &buffer))
+	assert.Nil(t, hdlr.sampler.summaryTimer)
+
+	// No summary if summaries are disabled.
+	buffer.Reset()
+	hdlr, _ = newTestHandler(&buffer, &Options{Default: Policy{First: 1}, SummaryInterval: -1})
+	logger = slog.New(hdlr)
+	logger.Info(test.Message)
+	logger.Info(test.Message)
+	require.NoError(t, hdlr.Close())
+	assert.Equal(t, []string{test.Message}, messages(t, &buffer))
+}
+
+func TestPrune(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, clk := newTestHandler(&buffer, &Options{
+		Default: Policy{First: 1, Window: time.Second},
+		Levels: map[slog.Level]Policy{
+			slog.LevelWarn: {Rate: 0.5, Burst: 10},
+		},
+		SummaryInterval: -1,
+	})
+	logger := slog.New(hdlr)
+	for i := 0; i < 100; i++ {
+		logger.Info(fmt.Sprintf("message %d", i))
+		logger.Warn(fmt.Sprintf("message %d", i))
+	}
+	assert.Equal(t, 200, len(hdlr.sampler.states))
+
+	// Counting windows have expired but token buckets are not yet full.
+	clk.advance(time.Second)
+	logger.Info("message 0")
+	assert.Equal(t, 101, len(hdlr.sampler.states))
+
+	// Token buckets have refilled.
+	clk.advance(10 * time.Second)
+	logger.Info("message 0")
+	assert.Equal(t, 1, len(hdlr.sampler.states))
+	assert.Equal(t, Stats{Passed: 202}, hdlr.Stats())
+}
+
+func TestWithAttrsWithGroup(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr, _ := newTestHandler(&buffer, &Options{Default: Policy{First: 1}, SummaryInterval: -1})
+	logger := slog.New(hdlr)
+	logger.With("alpha", 1).WithGroup("group").Info(test.Message, "bravo", 2)
+	logger.Info(test.Message) // Sampling state is shared.
+	logMap, err := json.Parse(bytes.TrimSpace(buffer.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), logMap["alpha"])
+	assert.Equal(t, map[string]any{"bravo": float64(2)}, logMap["group"])
+	assert.Equal(t, Stats{Passed: 1, Dropped: 1}, hdlr.Stats())
+}
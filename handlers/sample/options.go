@@ -0,0 +1,116 @@
+package sample
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultWindow          = time.Second
+	DefaultSummaryInterval = time.Minute
+	DefaultSummaryMessage  = "Sampling dropped log records"
+)
+
+// Policy defines the sampling stages for a single level.
+// Stages with zero configuration values are disabled.
+// A zero Policy passes all records.
+type Policy struct {
+	// Window is the time period during which First and Thereafter records are counted.
+	// If not set defaults to the value of sample.DefaultWindow (= 1 second).
+	Window time.Duration
+
+	// First is the number of records passed during each window
+	// before switching to the Thereafter rate.
+	First uint64
+
+	// Thereafter specifies that every Mth record is passed after the First records in a window.
+	// If First is configured and Thereafter is zero all records after the First are dropped.
+	Thereafter uint64
+
+	// Rate is the number of records per second replenishing the token bucket.
+	// The token bucket is disabled if this is zero.
+	Rate float64
+
+	// Burst is the capacity of the token bucket.
+	// If not set defaults to one more than the integer part of Rate.
+	Burst uint
+
+	// Probability that any given record is passed, between zero and one.
+	// The probabilistic stage is disabled if this is zero or one or more.
+	Probability float64
+}
+
+func (p *Policy) counting() bool {
+	return p.First > 0 || p.Thereafter > 0
+}
+
+func (p *Policy) limiting() bool {
+	return p.Rate > 0
+}
+
+func (p *Policy) sampling() bool {
+	return p.Probability > 0 && p.Probability < 1
+}
+
+// Options configures a sample.Handler.
+type Options struct {
+	// Default is the Policy used for levels not configured in Levels.
+	Default Policy
+
+	// Levels maps levels to policies.
+	// A record uses the Policy configured for the highest level that is not above its own level.
+	// Records with levels below all configured levels use the Default policy.
+	Levels map[slog.Level]Policy
+
+	// SummaryInterval is the minimum time between summary records.
+	// If not set defaults to the value of sample.DefaultSummaryInterval (= 1 minute).
+	// Summary records are disabled if this is negative.
+	SummaryInterval time.Duration
+
+	// SummaryLevel is the level for summary records.
+	// If not set defaults to slog.LevelWarn.
+	SummaryLevel slog.Leveler
+
+	// SummaryMessage is the message for summary records.
+	// If not set defaults to the value of sample.DefaultSummaryMessage.
+	SummaryMessage string
+}
+
+// fixOptions returns a copy of an Options object configured with default values.
+// The caller's Options object (including the Levels map) is not changed
+// so that it may be reused for multiple handlers.
+func fixOptions(options *Options) *Options {
+	fixed := &Options{}
+	if options != nil {
+		*fixed = *options
+	}
+	options = fixed
+	if options.SummaryInterval == 0 {
+		options.SummaryInterval = DefaultSummaryInterval
+	}
+	if options.SummaryLevel == nil {
+		options.SummaryLevel = slog.LevelWarn
+	}
+	if options.SummaryMessage == "" {
+		options.SummaryMessage = DefaultSummaryMessage
+	}
+	fixPolicy(&options.Default)
+	if options.Levels != nil {
+		levels := make(map[slog.Level]Policy, len(options.Levels))
+		for level, policy := range options.Levels {
+			fixPolicy(&policy)
+			levels[level] = policy
+		}
+		options.Levels = levels
+	}
+	return options
+}
+
+func fixPolicy(policy *Policy) {
+	if policy.Window <= 0 {
+		policy.Window = DefaultWindow
+	}
+	if policy.Burst == 0 {
+		policy.Burst = uint(policy.Rate) + 1
+	}
+}
@@ -0,0 +1,255 @@
+package sample
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// key identifies a group of log records that are sampled together.
+type key struct {
+	level   slog.Level
+	message string
+}
+
+// state holds sampling data for a single key.
+type state struct {
+	windowStart time.Time
+	count       uint64
+	tokens      float64
+	lastFill    time.Time
+}
+
+// Stats contains cumulative counters for a sample.Handler.
+type Stats struct {
+	// Passed is the number of records passed to the wrapped handler.
+	Passed uint64
+
+	// Dropped is the number of records dropped by sampling.
+	Dropped uint64
+}
+
+type levelPolicy struct {
+	level  slog.Level
+	policy Policy
+}
+
+// sampler holds sampling state shared by a handler and all handlers derived from it
+// via WithAttrs and WithGroup.
+type sampler struct {
+	options *Options
+	root    slog.Handler
+	levels  []levelPolicy // configured levels in descending order
+
+	lock        sync.Mutex
+	states      map[key]*state
+	dropped     map[slog.Level]uint64
+	lastSummary time.Time
+	closed      bool
+
+	// summaryTimer logs a summary when records have been dropped
+	// and no summary has been logged during Handle calls.
+	summaryTimer *time.Timer
+
+	// States are pruned at most once per pruneInterval.
+	pruneInterval time.Duration
+	lastPrune     time.Time
+
+	passedTotal  atomic.Uint64
+	droppedTotal atomic.Uint64
+
+	// Replaceable for testing.
+	now    func() time.Time
+	random func() float64
+}
+
+func newSampler(root slog.Handler, options *Options) *sampler {
+	s := &sampler{
+		options: options,
+		root:    root,
+		states:  make(map[key]*state),
+		dropped: make(map[slog.Level]uint64),
+		now:     time.Now,
+		random:  rand.Float64,
+	}
+	for level, policy := range options.Levels {
+		s.levels = append(s.levels, levelPolicy{level: level, policy: policy})
+	}
+	sort.Slice(s.levels, func(i, j int) bool { return s.levels[i].level > s.levels[j].level })
+	s.pruneInterval = options.Default.Window
+	for _, lp := range s.levels {
+		s.pruneInterval = min(s.pruneInterval, lp.policy.Window)
+	}
+	s.lastSummary = s.now()
+	s.lastPrune = s.lastSummary
+	return s
+}
+
+// policy returns the Policy for the specified level.
+func (s *sampler) policy(level slog.Level) *Policy {
+	for i := range s.levels {
+		if s.levels[i].level <= level {
+			return &s.levels[i].policy
+		}
+	}
+	return &s.options.Default
+}
+
+// allow returns true if the record should be passed to the wrapped handler.
+// The summaryDue result is true when it is time to log a summary record.
+func (s *sampler) allow(level slog.Level, message string) (allow bool, summaryDue bool) {
+	policy := s.policy(level)
+	if !policy.counting() && !policy.limiting() && !policy.sampling() {
+		s.passedTotal.Add(1)
+		return true, false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.now()
+	if now.Sub(s.lastPrune) >= s.pruneInterval {
+		s.prune(now)
+	}
+	allow = s.check(policy, key{level: level, message: message}, now)
+	if allow {
+		s.passedTotal.Add(1)
+	} else {
+		s.droppedTotal.Add(1)
+		s.dropped[level]++
+		s.startTimer(now)
+	}
+	summaryDue = s.options.SummaryInterval > 0 && len(s.dropped) > 0 &&
+		now.Sub(s.lastSummary) >= s.options.SummaryInterval
+	return allow, summaryDue
+}
+
+// check applies each configured stage of the policy to the record key.
+// Must be called with the lock held.
+func (s *sampler) check(policy *Policy, k key, now time.Time) bool {
+	st, found := s.states[k]
+	if !found {
+		st = &state{windowStart: now, lastFill: now, tokens: float64(policy.Burst)}
+		s.states[k] = st
+	}
+	if policy.counting() {
+		if now.Sub(st.windowStart) >= policy.Window {
+			st.windowStart = now
+			st.count = 0
+		}
+		st.count++
+		if st.count > policy.First {
+			if policy.Thereafter == 0 || (st.count-policy.First)%policy.Thereafter != 0 {
+				return false
+			}
+		}
+	}
+	if policy.limiting() {
+		st.tokens += now.Sub(st.lastFill).Seconds() * policy.Rate
+		st.lastFill = now
+		if burst := float64(policy.Burst); st.tokens > burst {
+			st.tokens = burst
+		}
+		if st.tokens < 1 {
+			return false
+		}
+		st.tokens--
+	}
+	if policy.sampling() && s.random() >= policy.Probability {
+		return false
+	}
+	return true
+}
+
+// prune removes states that are equivalent to new states
+// so that the map doesn't grow without bound when messages vary.
+// Must be called with the lock held.
+func (s *sampler) prune(now time.Time) {
+	s.lastPrune = now
+	for k, st := range s.states {
+		policy := s.policy(k.level)
+		if policy.counting() && now.Sub(st.windowStart) < policy.Window {
+			continue
+		}
+		if policy.limiting() &&
+			st.tokens+now.Sub(st.lastFill).Seconds()*policy.Rate < float64(policy.Burst) {
+			continue
+		}
+		delete(s.states, k)
+	}
+}
+
+// startTimer starts a timer to log a summary when the summary interval is over,
+// so that the summary is logged even if there are no further calls to Handle.
+// Must be called with the lock held.
+func (s *sampler) startTimer(now time.Time) {
+	if s.summaryTimer != nil || s.closed || s.options.SummaryInterval <= 0 {
+		return
+	}
+	delay := s.options.SummaryInterval - now.Sub(s.lastSummary)
+	s.summaryTimer = time.AfterFunc(max(delay, 0), func() {
+		// There is nowhere to return an error from a timer.
+		_ = s.summarize(context.Background())
+	})
+}
+
+// close stops the summary timer and logs a final summary if summaries are enabled.
+func (s *sampler) close(ctx context.Context) error {
+	s.lock.Lock()
+	s.closed = true
+	s.lock.Unlock()
+	if s.options.SummaryInterval <= 0 {
+		return nil
+	}
+	return s.summarize(ctx)
+}
+
+// summarize logs a summary of records dropped since the last summary, if any.
+func (s *sampler) summarize(ctx context.Context) error {
+	s.lock.Lock()
+	now := s.now()
+	s.lastSummary = now
+	if s.summaryTimer != nil {
+		s.summaryTimer.Stop()
+		s.summaryTimer = nil
+	}
+	if len(s.dropped) < 1 {
+		s.lock.Unlock()
+		return nil
+	}
+	dropped := s.dropped
+	s.dropped = make(map[slog.Level]uint64)
+	s.lock.Unlock()
+
+	level := s.options.SummaryLevel.Level()
+	if !s.root.Enabled(ctx, level) {
+		return nil
+	}
+	levels := make([]slog.Level, 0, len(dropped))
+	var total uint64
+	for lvl, count := range dropped {
+		levels = append(levels, lvl)
+		total += count
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	counts := make([]any, 0, len(levels))
+	for _, lvl := range levels {
+		counts = append(counts, slog.Uint64(lvl.String(), dropped[lvl]))
+	}
+	record := slog.NewRecord(now, level, s.options.SummaryMessage, 0)
+	record.AddAttrs(slog.Uint64("dropped", total), slog.Group("levels", counts...))
+	if err := s.root.Handle(ctx, record); err != nil {
+		return fmt.Errorf("handle summary: %w", err)
+	}
+	return nil
+}
+
+func (s *sampler) stats() Stats {
+	return Stats{
+		Passed:  s.passedTotal.Load(),
+		Dropped: s.droppedTotal.Load(),
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"io"
 	"log/slog"
 	"net"
+	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
 )
 
 var (
@@ -40,14 +42,20 @@ type composer struct {
 	started bool
 	replace infra.AttrFn
 	groups  []string
+	limits  *limit.Options
+
+	// depth and resolves track nested groups and slog.LogValuer values for limits.
+	depth    int
+	resolves int
 }
 
-func newComposer(writer io.Writer, started bool, replace infra.AttrFn, groups []string) *composer {
+func newComposer(writer io.Writer, started bool, replace infra.AttrFn, groups []string, limits *limit.Options) *composer {
 	return &composer{
 		Writer:  writer,
 		started: started,
 		replace: replace,
 		groups:  groups,
+		limits:  limits,
 	}
 }
 
@@ -68,7 +76,17 @@ func (c *composer) addAttribute(attr slog.Attr) error {
 	if attr.Equal(infra.EmptyAttr()) {
 		return nil
 	}
-	value := attr.Value.Resolve()
+	value := attr.Value
+	if value.Kind() == slog.KindLogValuer {
+		if !c.limits.Resolve(c.resolves + 1) {
+			value = slog.StringValue(limit.ResolveExceeded)
+		} else {
+			value = value.Resolve()
+			c.resolves++
+			defer func() { c.resolves-- }()
+		}
+		attr.Value = value
+	}
 	if c.replace != nil {
 		var groups []string
 		if !basicField[attr.Key] {
@@ -81,11 +99,15 @@ func (c *composer) addAttribute(attr slog.Attr) error {
 		return nil
 	}
 	if value.Kind() == slog.KindGroup {
-		if emptyGroup(value.Group()) {
+		if !c.limits.Group(c.depth + 1) {
+			value = slog.StringValue(limit.DepthExceeded)
+		} else if emptyGroup(value.Group(), c.limits.GroupRemaining(c.depth+1)) {
 			return nil
-		}
-		if attr.Key == "" {
-			if err := c.addAttributes(value.Group()); err != nil {
+		} else if attr.Key == "" {
+			c.depth++
+			err := c.addAttributes(value.Group())
+			c.depth--
+			if err != nil {
 				return fmt.Errorf("inline group attributes: %w", err)
 			}
 			return nil
@@ -114,7 +136,11 @@ func (c *composer) addAttribute(attr slog.Attr) error {
 	case slog.KindInt64:
 		return c.addInt64(value.Int64())
 	case slog.KindString:
-		return c.addString(value.String())
+		if basicField[attr.Key] {
+			// Basic fields are not attribute values, the message is limited separately.
+			return c.addString(value.String())
+		}
+		return c.addString(c.limits.Value(value.String()))
 	case slog.KindTime:
 		return c.addTime(value.Time())
 	case slog.KindUint64:
@@ -149,7 +175,15 @@ var boolImage = map[bool][]byte{
 	true:  boolTrue,
 }
 
-func (c *composer) addAny(a any) error {
+func (c *composer) addAny(a any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// Report the panic as the value.
+			// Unlike flash any partial output can't be discarded
+			// but values are generated before they are written.
+			err = c.addString(panicString(a, r))
+		}
+	}()
 	switch v := a.(type) {
 	case net.IP:
 		return c.addIPAddress(v)
@@ -170,7 +204,10 @@ func (c *composer) addAny(a any) error {
 		// Note: Important stuff buried in some random structure may be ignored.
 		//       For example, a LogValuer or a Stringer might show up as an empty map.
 		if b, err := json.Marshal(a); err != nil {
-			return fmt.Errorf("marshal %v: %w", a, err)
+			// Report the error as the value (e.g. a self-referential value) as does slog.JSONHandler.
+			return c.addString("!ERROR:" + err.Error())
+		} else if c.limits.ValueTooLong(len(b)) {
+			return c.addString(c.limits.Value(string(b)))
 		} else if _, err := c.Write(b); err != nil {
 			return fmt.Errorf("write bytes: %w", err)
 		} else {
@@ -194,7 +231,7 @@ func (c *composer) addDuration(d time.Duration) error {
 }
 
 func (c *composer) addError(e error) error {
-	return c.addString(e.Error())
+	return c.addString(c.limits.Value(e.Error()))
 }
 
 func (c *composer) addFloat64(f float64) error {
@@ -209,7 +246,9 @@ func (c *composer) addGroup(_ string, attrs []slog.Attr) error {
 		return fmt.Errorf("begin: %w", err)
 	}
 	// Local composer object resets started flag
-	cg := newComposer(c.Writer, false, c.replace, c.groups)
+	cg := newComposer(c.Writer, false, c.replace, c.groups, c.limits)
+	cg.depth = c.depth + 1
+	cg.resolves = c.resolves
 	if err := cg.addAttributes(attrs); err != nil {
 		return fmt.Errorf("add attributes: %w", err)
 	}
@@ -244,7 +283,7 @@ func (c *composer) addJSONMarshaler(m json.Marshaler) error {
 	if txt, err := m.MarshalJSON(); err != nil {
 		return c.addString("!ERROR:" + err.Error())
 	} else {
-		return c.addStringAsBytes(txt)
+		return c.addString(c.limits.Value(string(txt)))
 	}
 }
 
@@ -262,12 +301,8 @@ func (c *composer) addString(str string) error {
 	return nil
 }
 
-func (c *composer) addStringAsBytes(str []byte) error {
-	return c.addString(string(str))
-}
-
 func (c *composer) addStringer(s fmt.Stringer) error {
-	if err := c.addString(s.String()); err != nil {
+	if err := c.addString(c.limits.Value(s.String())); err != nil {
 		return fmt.Errorf("stringer '%v': %w", s, err)
 	}
 	return nil
@@ -277,7 +312,7 @@ func (c *composer) addTextMarshaler(m encoding.TextMarshaler) error {
 	if txt, err := m.MarshalText(); err != nil {
 		return c.addString("!ERROR:" + err.Error())
 	} else {
-		return c.addStringAsBytes(txt)
+		return c.addString(c.limits.Value(string(txt)))
 	}
 }
 
@@ -297,13 +332,16 @@ func (c *composer) addUint64(i uint64) error {
 
 // -----------------------------------------------------------------------------
 
-func emptyGroup(attrs []slog.Attr) bool {
+// emptyGroup returns true if the group attributes would not generate any output.
+// Nested groups are checked to the specified number of levels (-1 for no limit).
+// Deeper groups are not empty as they are logged as limit.DepthExceeded.
+func emptyGroup(attrs []slog.Attr, levels int) bool {
 	for _, attr := range attrs {
 		if attr.Equal(infra.EmptyAttr()) {
 			continue
 		}
 		if attr.Value.Kind() == slog.KindGroup {
-			if !emptyGroup(attr.Value.Group()) {
+			if levels == 0 || !emptyGroup(attr.Value.Group(), levels-1) {
 				return false
 			}
 		} else {
@@ -314,12 +352,22 @@ func emptyGroup(attrs []slog.Attr) bool {
 	return true
 }
 
+// panicString returns the value to be logged when generating a value panics.
+// As with slog.JSONHandler a nil pointer (e.g. to an error or fmt.Stringer)
+// is logged as "<nil>", otherwise the panic itself is logged.
+func panicString(a any, r any) string {
+	if v := reflect.ValueOf(a); v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("!PANIC: %v", r)
+}
+
 // -----------------------------------------------------------------------------
 
 // ComposeAttributes is a public function provided to support benchmark testing
 // in the flash package. It is not intended for any other use.
 func ComposeAttributes(buffer *bytes.Buffer, attrs []slog.Attr) error {
-	c := newComposer(buffer, false, nil, nil)
+	c := newComposer(buffer, false, nil, nil, nil)
 	if err := c.addAttributes(attrs); err != nil {
 		return fmt.Errorf("add attributes: %w", err)
 	}
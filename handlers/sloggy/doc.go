@@ -12,6 +12,13 @@
 // otherwise this was a green field build with performance left until later.
 // The [flash] handler, originally a copy of this one, has been tweaked for performance.
 //
+// Attributes attached to the context via [ctxattr.With] are added to each log record
+// at the top level or, using the Extras field Context with NewHandlerWithExtras, under a named group.
+// Output limits from [limit.Options] can also be configured via Extras.
+// Nesting of groups and slog.LogValuer values is always bounded by default limits.
+//
+// [ctxattr.With]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/ctxattr#With
+// [limit.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/infra/limit#Options
 // [flash]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/flash
 // [hubris]: https://wiki.c2.com/?LazinessImpatienceHubris
 package sloggy
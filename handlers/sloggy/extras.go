@@ -0,0 +1,28 @@
+package sloggy
+
+import (
+	"github.com/madkins23/go-slog/handlers/ctxattr"
+	"github.com/madkins23/go-slog/infra/limit"
+)
+
+// Extras defines extra options specific to a sloggy.Handler.
+type Extras struct {
+	// Context configures placement of attributes attached to the context via ctxattr.With.
+	// If not set context attributes are placed at the top level of the log record.
+	Context *ctxattr.Options
+
+	// Limits configures safety limits on the output for each log record.
+	// If not set only the default group and slog.LogValuer nesting limits apply,
+	// otherwise matching slog.JSONHandler.
+	Limits *limit.Options
+}
+
+// fixExtras makes certain that an Extras object has been properly created and
+// configured with default values.
+func fixExtras(extras *Extras) *Extras {
+	if extras == nil {
+		extras = &Extras{}
+	}
+	extras.Limits = limit.Fix(extras.Limits)
+	return extras
+}
@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"runtime"
 	"sync"
+
+	"github.com/madkins23/go-slog/handlers/ctxattr"
 )
 
 var _ slog.Handler = &Handler{}
@@ -19,15 +21,24 @@ type Handler struct {
 	mutex          *sync.Mutex
 	prefix, suffix bytes.Buffer
 	groups         []string
+	extras         *Extras
 }
 
 // NewHandler returns a new sloggy handler with the specified output writer and slog.HandlerOptions.
 // If the options argument is nil it will be set to a level of slog.LevelInfo and nothing else.
 func NewHandler(writer io.Writer, options *slog.HandlerOptions) *Handler {
+	return NewHandlerWithExtras(writer, options, nil)
+}
+
+// NewHandlerWithExtras returns a new sloggy handler with the specified output writer,
+// slog.HandlerOptions, and Extras options.
+// If the extras argument is nil there are no extra options.
+func NewHandlerWithExtras(writer io.Writer, options *slog.HandlerOptions, extras *Extras) *Handler {
 	hdlr := &Handler{
 		options: fixOptions(options),
 		writer:  writer,
 		mutex:   &sync.Mutex{},
+		extras:  fixExtras(extras),
 	}
 	return hdlr
 }
@@ -38,8 +49,8 @@ func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= h.options.Level.Level()
 }
 
-func (h *Handler) Handle(_ context.Context, record slog.Record) error {
-	c := newComposer(h.writer, false, h.options.ReplaceAttr, h.groups)
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	c := newComposer(h.writer, false, h.options.ReplaceAttr, h.groups, h.extras.Limits)
 	if err := c.begin(); err != nil {
 		return fmt.Errorf("begin: %w", err)
 	}
@@ -49,7 +60,7 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 		basic = append(basic, slog.Time(slog.TimeKey, record.Time))
 	}
 	basic = append(basic, slog.String(slog.LevelKey, record.Level.String()))
-	basic = append(basic, slog.String(slog.MessageKey, record.Message))
+	basic = append(basic, slog.String(slog.MessageKey, h.extras.Limits.Message(record.Message)))
 	if h.options.AddSource && record.PC != 0 {
 		fs := runtime.CallersFrames([]uintptr{record.PC})
 		f, _ := fs.Next()
@@ -63,6 +74,16 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 	if err := c.addAttributes(basic); err != nil {
 		return fmt.Errorf("add basic attributes: %w", err)
 	}
+	if attrs := ctxattr.Resolve(ctx, h.extras.Context); len(attrs) > 0 {
+		// Context attributes are never in a group.
+		groups := c.groups
+		c.groups = nil
+		err := c.addAttributes(attrs)
+		c.groups = groups
+		if err != nil {
+			return fmt.Errorf("add context attributes: %w", err)
+		}
+	}
 
 	if h.prefix.Len() > 0 {
 		if _, err := c.Write(commaSpace); err != nil {
@@ -77,7 +98,12 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 	}
 
 	var err error
+	var count int
 	record.Attrs(func(attr slog.Attr) bool {
+		if !h.extras.Limits.Attrs(count) {
+			return false
+		}
+		count++
 		if err = c.addAttribute(attr); err != nil {
 			return false
 		}
@@ -86,6 +112,11 @@ func (h *Handler) Handle(_ context.Context, record slog.Record) error {
 	if err != nil {
 		return fmt.Errorf("add attributes: %w", err)
 	}
+	if dropped := record.NumAttrs() - count; dropped > 0 {
+		if err = c.addAttribute(h.extras.Limits.Dropped(dropped)); err != nil {
+			return fmt.Errorf("add dropped attribute count: %w", err)
+		}
+	}
 
 	if _, err := c.Write(h.suffix.Bytes()); err != nil {
 		return fmt.Errorf("write suffix: %w", err)
@@ -111,6 +142,7 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		writer:  h.writer,
 		mutex:   h.mutex,
 		groups:  h.groups,
+		extras:  h.extras,
 		prefix:  bytes.Buffer{},
 		suffix:  bytes.Buffer{},
 	}
@@ -124,7 +156,7 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	if h.suffix.Len() > 0 {
 		hdlr.suffix.Write(h.suffix.Bytes())
 	}
-	c := newComposer(&hdlr.prefix, prefixStarted, h.options.ReplaceAttr, h.groups)
+	c := newComposer(&hdlr.prefix, prefixStarted, h.options.ReplaceAttr, h.groups, h.extras.Limits)
 	if err := c.addAttributes(attrs); err != nil {
 		slog.Error("adding with attributes", "err", err)
 	}
@@ -145,6 +177,7 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 			prefix:  bytes.Buffer{},
 			suffix:  bytes.Buffer{},
 			groups:  append(h.groups, name),
+			extras:  h.extras,
 		},
 		name:   name,
 		parent: h,
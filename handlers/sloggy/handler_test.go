@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/madkins23/go-slog/handlers/ctxattr"
 	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/limit"
 	"github.com/madkins23/go-slog/internal/test"
 )
 
@@ -223,6 +226,75 @@ func (suite *HandlerTestSuite) TestWithGroupAttrSubEmpty() {
 	suite.Assert().Equal("3", group["third"])
 }
 
+func (suite *HandlerTestSuite) TestContextAttrs() {
+	ctx := ctxattr.With(context.Background(), slog.String("requestID", "r-1"), slog.Int("tenant", 7))
+	var replaceGroups []string
+	options := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "requestID" {
+				replaceGroups = groups
+			}
+			return a
+		},
+	}
+	hdlr := suite.newHandler(options).
+		WithAttrs([]slog.Attr{slog.String("first", "one")}).
+		WithGroup("group")
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, test.Message, 0)
+	record.AddAttrs(slog.String("second", "two"))
+	suite.Assert().NoError(hdlr.Handle(ctx, record))
+	suite.Assert().Regexp(`"msg": "[^"]+", "requestID": "r-1", "tenant": 7, "first": "one", "group"`, suite.String())
+	logMap := suite.logMap()
+	suite.Assert().Equal("r-1", logMap["requestID"])
+	suite.Assert().Equal(float64(7), logMap["tenant"])
+	suite.Assert().Equal(map[string]any{"second": "two"}, logMap["group"])
+	suite.Assert().Nil(replaceGroups)
+	// Under a named group.
+	suite.Reset()
+	hdlr = NewHandlerWithExtras(suite.Buffer, nil, &Extras{Context: &ctxattr.Options{Group: "ctx"}}).WithGroup("group")
+	suite.Assert().NoError(hdlr.Handle(ctx, record))
+	logMap = suite.logMap()
+	suite.Assert().Equal(map[string]any{"requestID": "r-1", "tenant": float64(7)}, logMap["ctx"])
+	suite.Assert().Equal(map[string]any{"second": "two"}, logMap["group"])
+}
+
+func (suite *HandlerTestSuite) TestLimits() {
+	hdlr := NewHandlerWithExtras(suite.Buffer, nil, &Extras{Limits: &limit.Options{
+		MaxMessageLength: 8,
+		MaxValueLength:   6,
+		MaxGroupDepth:    2,
+		MaxAttrs:         5,
+		MaxResolveDepth:  1,
+	}})
+	slog.New(hdlr).Info("message too long",
+		"string", "value too long",
+		"list", []int{1, 2, 3, 4, 5},
+		"loop", test.LoopValuer{},
+		test.SelfGroup("self"),
+		"stringer", test.PanicStringer{},
+		"extra1", 1,
+		"extra2", 2)
+	logMap := suite.logMap()
+	delete(logMap, slog.TimeKey)
+	suite.Assert().Equal(map[string]any{
+		slog.LevelKey:   "INFO",
+		slog.MessageKey: "message ...",
+		"string":        "value ...",
+		"list":          "[1,2,3...",
+		"loop":          map[string]any{"loop": limit.ResolveExceeded},
+		"self":          map[string]any{"self": map[string]any{"self": limit.DepthExceeded}},
+		"stringer":      "!PANIC: PanicStringer",
+		"!DROPPED":      float64(2),
+	}, logMap)
+}
+
+func (suite *HandlerTestSuite) TestDefaultLimits() {
+	slog.New(NewHandler(suite.Buffer, nil)).Info(test.Message, "loop", test.LoopValuer{}, test.SelfGroup("self"))
+	suite.Assert().Equal(1, strings.Count(suite.Buffer.String(), limit.ResolveExceeded))
+	suite.Assert().Equal(1, strings.Count(suite.Buffer.String(), limit.DepthExceeded))
+	suite.Assert().NotNil(suite.logMap())
+}
+
 // -----------------------------------------------------------------------------
 
 func ExampleHandler() {
@@ -0,0 +1,303 @@
+package syslog
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/madkins23/go-slog/infra"
+)
+
+// Maximum lengths of header fields and SD names per RFC 5424.
+const (
+	maxHostname = 255
+	maxAppName  = 48
+	maxProcID   = 128
+	maxMsgID    = 32
+	maxSDName   = 32
+	maxTag      = 32
+)
+
+const (
+	layout5424 = "2006-01-02T15:04:05.000000Z07:00"
+	layout3164 = time.Stamp
+)
+
+// param holds a single attribute converted for output.
+type param struct {
+	// sdID is the SD-ID of the SD-ELEMENT for RFC5424.
+	sdID string
+	// name is the PARAM-NAME within the SD-ELEMENT for RFC5424.
+	name string
+	// path is the dotted key including all groups for RFC3164 and Extras.MsgIDKey.
+	path string
+	// value is the string form of the attribute value.
+	value string
+}
+
+// addAttr converts an attribute to params, resolving values and applying any ReplaceAttr function.
+// Groups are flattened: the first group determines the SD-ID for RFC5424
+// and any further groups are prefixed to the PARAM-NAME with dots.
+func (h *Handler) addAttr(params []param, groups []string, attr slog.Attr) []param {
+	attr.Value = attr.Value.Resolve()
+	if h.options.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.options.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(infra.EmptyAttr()) {
+		return params
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			params = h.addAttr(params, groups, a)
+		}
+		return params
+	}
+	p := param{value: valueString(attr.Value)}
+	if len(groups) < 1 {
+		p.sdID = h.defaultSDID
+		p.name = sanitizeName(attr.Key, maxSDName)
+		p.path = attr.Key
+	} else {
+		p.sdID = h.sdID(groups[0])
+		p.name = sanitizeName(strings.Join(append(slices.Clip(groups[1:]), attr.Key), "."), maxSDName)
+		p.path = strings.Join(append(slices.Clip(groups), attr.Key), ".")
+	}
+	return append(params, p)
+}
+
+// sdID returns the SD-ID for the specified group name.
+// Names containing '@' are assumed to already contain an enterprise number.
+func (h *Handler) sdID(name string) string {
+	if strings.Contains(name, "@") {
+		return sanitizeName(name, maxSDName)
+	}
+	suffix := "@" + h.extras.EnterpriseID
+	return sanitizeName(name, maxSDName-len(suffix)) + suffix
+}
+
+// msgID extracts the MSGID from the top-level param named by Extras.MsgIDKey, if any.
+// The last such param is used and all such params are removed.
+func (h *Handler) msgID(params []param) (string, []param) {
+	if h.extras.MsgIDKey == "" {
+		return "", params
+	}
+	var msgID string
+	for _, p := range params {
+		if p.path == h.extras.MsgIDKey {
+			msgID = p.value
+		}
+	}
+	return msgID, slices.DeleteFunc(params, func(p param) bool {
+		return p.path == h.extras.MsgIDKey
+	})
+}
+
+// -----------------------------------------------------------------------------
+
+// compose5424 appends an RFC 5424 message to the buffer:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+func (h *Handler) compose5424(buffer []byte, record slog.Record, params []param) []byte {
+	msgID, params := h.msgID(params)
+	buffer = append(buffer, '<')
+	buffer = strconv.AppendInt(buffer, int64(priority(h.extras.Facility, h.extras.Severity(record.Level))), 10)
+	buffer = append(buffer, ">1 "...)
+	if record.Time.IsZero() {
+		buffer = append(buffer, '-')
+	} else {
+		buffer = record.Time.AppendFormat(buffer, layout5424)
+	}
+	buffer = append(buffer, ' ')
+	buffer = appendHeader(buffer, h.extras.Hostname, maxHostname)
+	buffer = append(buffer, ' ')
+	buffer = appendHeader(buffer, h.extras.AppName, maxAppName)
+	buffer = append(buffer, ' ')
+	buffer = appendHeader(buffer, h.extras.ProcID, maxProcID)
+	buffer = append(buffer, ' ')
+	buffer = appendHeader(buffer, msgID, maxMsgID)
+	buffer = append(buffer, ' ')
+	if len(params) < 1 {
+		buffer = append(buffer, '-')
+	} else {
+		// Each SD-ID may only occur once so params are collected by SD-ID in order of first appearance.
+		done := make([]string, 0, 4)
+		for i, first := range params {
+			if slices.Contains(done, first.sdID) {
+				continue
+			}
+			done = append(done, first.sdID)
+			buffer = append(buffer, '[')
+			buffer = append(buffer, first.sdID...)
+			for _, p := range params[i:] {
+				if p.sdID == first.sdID {
+					buffer = append(buffer, ' ')
+					buffer = append(buffer, p.name...)
+					buffer = append(buffer, '=', '"')
+					buffer = appendEscaped(buffer, p.value)
+					buffer = append(buffer, '"')
+				}
+			}
+			buffer = append(buffer, ']')
+		}
+	}
+	if record.Message != "" {
+		buffer = append(buffer, ' ')
+		buffer = append(buffer, record.Message...)
+	}
+	return buffer
+}
+
+// compose3164 appends an RFC 3164 (BSD) message to the buffer:
+//
+//	<PRI>TIMESTAMP HOSTNAME TAG[PROCID]: MSG key=value ...
+//
+// As there is no structured data in RFC 3164 the attributes are appended to the message.
+func (h *Handler) compose3164(buffer []byte, record slog.Record, params []param) []byte {
+	buffer = append(buffer, '<')
+	buffer = strconv.AppendInt(buffer, int64(priority(h.extras.Facility, h.extras.Severity(record.Level))), 10)
+	buffer = append(buffer, '>')
+	when := record.Time
+	if when.IsZero() {
+		// The timestamp is required.
+		when = time.Now()
+	}
+	buffer = when.AppendFormat(buffer, layout3164)
+	buffer = append(buffer, ' ')
+	buffer = appendHeader(buffer, h.extras.Hostname, maxHostname)
+	buffer = append(buffer, ' ')
+	buffer = appendTag(buffer, h.extras.AppName)
+	if h.extras.ProcID != "" {
+		buffer = append(buffer, '[')
+		buffer = append(buffer, h.extras.ProcID...)
+		buffer = append(buffer, ']')
+	}
+	buffer = append(buffer, ':', ' ')
+	buffer = append(buffer, record.Message...)
+	for _, p := range params {
+		buffer = append(buffer, ' ')
+		buffer = append(buffer, p.path...)
+		buffer = append(buffer, '=')
+		if p.value == "" || strings.ContainsAny(p.value, " \"=") {
+			buffer = strconv.AppendQuote(buffer, p.value)
+		} else {
+			buffer = append(buffer, p.value...)
+		}
+	}
+	return buffer
+}
+
+// frame applies the specified framing to a complete message.
+func frame(message []byte, framing Framing) []byte {
+	switch framing {
+	case FramingNewline:
+		return append(message, '\n')
+	case FramingOctet:
+		framed := make([]byte, 0, len(message)+8)
+		framed = strconv.AppendInt(framed, int64(len(message)), 10)
+		framed = append(framed, ' ')
+		return append(framed, message...)
+	default:
+		return message
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// appendHeader appends a header field restricted to printable US-ASCII characters
+// (any others are replaced with '_') and truncated to the specified maximum length.
+// Empty fields are represented as "-" (the NILVALUE).
+func appendHeader(buffer []byte, field string, maximum int) []byte {
+	if field == "" {
+		return append(buffer, '-')
+	}
+	if len(field) > maximum {
+		field = field[:maximum]
+	}
+	for i := 0; i < len(field); i++ {
+		if ch := field[i]; ch >= 33 && ch <= 126 {
+			buffer = append(buffer, ch)
+		} else {
+			buffer = append(buffer, '_')
+		}
+	}
+	return buffer
+}
+
+// appendTag appends an RFC 3164 TAG restricted to alphanumeric characters,
+// '-', '_', and '.' (any others are replaced with '_').
+func appendTag(buffer []byte, tag string) []byte {
+	if len(tag) > maxTag {
+		tag = tag[:maxTag]
+	}
+	for i := 0; i < len(tag); i++ {
+		switch ch := tag[i]; {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9', ch == '-', ch == '_', ch == '.':
+			buffer = append(buffer, ch)
+		default:
+			buffer = append(buffer, '_')
+		}
+	}
+	return buffer
+}
+
+// appendEscaped appends a PARAM-VALUE with '"', '\', and ']' escaped by a backslash.
+// Invalid UTF-8 sequences are replaced with the Unicode replacement character.
+func appendEscaped(buffer []byte, value string) []byte {
+	for _, ch := range value {
+		switch ch {
+		case '"', '\\', ']':
+			buffer = append(buffer, '\\', byte(ch))
+		default:
+			buffer = utf8.AppendRune(buffer, ch)
+		}
+	}
+	return buffer
+}
+
+// sanitizeName returns an SD-NAME restricted to printable US-ASCII characters
+// other than '=', ' ', ']', and '"' (any others are replaced with '_')
+// and truncated to the specified maximum length.
+func sanitizeName(name string, maximum int) string {
+	if name == "" {
+		return "_"
+	}
+	if len(name) > maximum {
+		name = name[:maximum]
+	}
+	return strings.Map(func(ch rune) rune {
+		if ch < 33 || ch > 126 || ch == '=' || ch == ']' || ch == '"' {
+			return '_'
+		}
+		return ch
+	}, name)
+}
+
+// valueString returns the string form of a resolved attribute value.
+func valueString(value slog.Value) string {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String()
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		// Uses the Error or String method if available and recovers from panics.
+		return fmt.Sprint(value.Any())
+	default:
+		return value.String()
+	}
+}
+
+// source returns the "file:line" location for the specified program counter.
+func source(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame.File + ":" + strconv.Itoa(frame.Line)
+}
@@ -0,0 +1,30 @@
+// Package syslog provides a slog.Handler that renders log records as syslog messages.
+//
+// By default messages are formatted per RFC 5424:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+//
+// The PRI value combines the configured [syslog.Facility] with
+// the [syslog.Severity] mapped from the slog.Level (see SeverityOf).
+// Attributes are rendered as SD-PARAMs within SD-ELEMENTs:
+//
+//   - attributes not in a group are in the "attrs@32473" SD-ELEMENT,
+//   - attributes in a group are in an SD-ELEMENT with the group name as the SD-ID (e.g. "request@32473"),
+//   - attributes in nested groups have PARAM-NAMEs prefixed by the inner group names (e.g. "headers.accept").
+//
+// Group names that contain '@' are used as SD-IDs as is.
+// The enterprise number 32473 is reserved for documentation and should be replaced
+// with a real private enterprise number via [syslog.Extras].
+// The older BSD format per RFC 3164 is also available, in which case attributes
+// are appended to the message as key=value pairs.
+//
+// # Transports
+//
+// Messages can be written to any io.Writer via NewHandler (one message per line by default),
+// sent to a unix datagram socket such as "/dev/log" via DialUnix (one message per datagram),
+// or sent to a TCP server via DialTCP with octet-counting framing per RFC 6587.
+//
+// [syslog.Extras]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/syslog#Extras
+// [syslog.Facility]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/syslog#Facility
+// [syslog.Severity]: https://pkg.go.dev/github.com/madkins23/go-slog/handlers/syslog#Severity
+package syslog
@@ -0,0 +1,139 @@
+package syslog
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	// DefaultEnterpriseID is the private enterprise number appended to SD-IDs.
+	// This is the number reserved for documentation in RFC 5424 and
+	// should be replaced with a real private enterprise number in production.
+	DefaultEnterpriseID = "32473"
+
+	// DefaultSDName is the SD-ID name for attributes that are not in a group.
+	DefaultSDName = "attrs"
+)
+
+// Format specifies the syslog message format.
+//
+//go:generate go run github.com/dmarkham/enumer -type=Format
+type Format uint8
+
+const (
+	// RFC5424 generates messages with structured data per RFC 5424.
+	RFC5424 Format = iota
+	// RFC3164 generates BSD syslog messages per RFC 3164,
+	// with attributes appended to the message as key=value pairs.
+	RFC3164
+)
+
+// Framing specifies how messages are separated in the output.
+//
+//go:generate go run github.com/dmarkham/enumer -type=Framing
+type Framing uint8
+
+const (
+	// FramingAuto uses FramingNewline for handlers created via NewHandler,
+	// FramingNone for those created via DialUnix, and FramingOctet for those created via DialTCP.
+	FramingAuto Framing = iota
+	// FramingNewline terminates each message with a newline (e.g. for files).
+	FramingNewline
+	// FramingNone writes each message as is in a single Write call (e.g. for datagram sockets).
+	FramingNone
+	// FramingOctet prefixes each message with its length in bytes and a space
+	// (octet-counting per RFC 6587, e.g. for TCP).
+	FramingOctet
+)
+
+// Extras defines extra options specific to a syslog.Handler.
+type Extras struct {
+	// Format specifies the syslog message format.
+	// If not set defaults to RFC5424.
+	Format Format
+
+	// Framing specifies how messages are separated in the output.
+	// If not set defaults to FramingAuto.
+	Framing Framing
+
+	// Facility is the syslog facility for all messages.
+	// The zero value is FacilityKern which is not meant for applications,
+	// so if not set defaults to FacilityUser.
+	Facility Facility
+
+	// Severity maps slog.Level values to syslog severities.
+	// If not set defaults to SeverityOf.
+	Severity func(level slog.Level) Severity
+
+	// Hostname is the HOSTNAME header field.
+	// If not set defaults to the result of os.Hostname.
+	Hostname string
+
+	// AppName is the APP-NAME header field (the TAG for RFC3164).
+	// If not set defaults to the base name of the program.
+	AppName string
+
+	// ProcID is the PROCID header field.
+	// If not set defaults to the process ID.
+	ProcID string
+
+	// MsgIDKey specifies a top-level attribute used as the MSGID header field
+	// instead of as an SD-PARAM. If not set the MSGID field is always empty ("-").
+	MsgIDKey string
+
+	// EnterpriseID is the private enterprise number appended to SD-IDs (e.g. "attrs@32473").
+	// Group names that already contain '@' are used as SD-IDs as is.
+	// If not set defaults to the value of syslog.DefaultEnterpriseID.
+	EnterpriseID string
+
+	// SDName is the SD-ID name (before the '@') for attributes that are not in a group.
+	// If not set defaults to the value of syslog.DefaultSDName.
+	SDName string
+}
+
+// fixExtras makes certain that an Extras object has been properly created and
+// configured with default values.
+// FramingAuto is left as is since it depends on how the handler is created.
+func fixExtras(extras *Extras) *Extras {
+	if extras == nil {
+		extras = &Extras{}
+	}
+	if extras.Facility == FacilityKern {
+		extras.Facility = FacilityUser
+	}
+	if extras.Severity == nil {
+		extras.Severity = SeverityOf
+	}
+	if extras.Hostname == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			extras.Hostname = hostname
+		}
+	}
+	if extras.AppName == "" && len(os.Args) > 0 {
+		extras.AppName = filepath.Base(os.Args[0])
+	}
+	if extras.ProcID == "" {
+		extras.ProcID = strconv.Itoa(os.Getpid())
+	}
+	if extras.EnterpriseID == "" {
+		extras.EnterpriseID = DefaultEnterpriseID
+	}
+	if extras.SDName == "" {
+		extras.SDName = DefaultSDName
+	}
+	return extras
+}
+
+// fixOptions makes certain that a slog.HandlerOptions object has been properly created and
+// configured with default values.
+func fixOptions(options *slog.HandlerOptions) *slog.HandlerOptions {
+	if options == nil {
+		options = &slog.HandlerOptions{}
+	}
+	if options.Level == nil {
+		options.Level = slog.LevelInfo
+	}
+	return options
+}
@@ -0,0 +1,78 @@
+// Code generated by "enumer -type=Format"; DO NOT EDIT.
+
+package syslog
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _FormatName = "RFC5424RFC3164"
+
+var _FormatIndex = [...]uint8{0, 7, 14}
+
+const _FormatLowerName = "rfc5424rfc3164"
+
+func (i Format) String() string {
+	if i >= Format(len(_FormatIndex)-1) {
+		return fmt.Sprintf("Format(%d)", i)
+	}
+	return _FormatName[_FormatIndex[i]:_FormatIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _FormatNoOp() {
+	var x [1]struct{}
+	_ = x[RFC5424-(0)]
+	_ = x[RFC3164-(1)]
+}
+
+var _FormatValues = []Format{RFC5424, RFC3164}
+
+var _FormatNameToValueMap = map[string]Format{
+	_FormatName[0:7]:       RFC5424,
+	_FormatLowerName[0:7]:  RFC5424,
+	_FormatName[7:14]:      RFC3164,
+	_FormatLowerName[7:14]: RFC3164,
+}
+
+var _FormatNames = []string{
+	_FormatName[0:7],
+	_FormatName[7:14],
+}
+
+// FormatString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func FormatString(s string) (Format, error) {
+	if val, ok := _FormatNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _FormatNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Format values", s)
+}
+
+// FormatValues returns all values of the enum
+func FormatValues() []Format {
+	return _FormatValues
+}
+
+// FormatStrings returns a slice of all String values of the enum
+func FormatStrings() []string {
+	strs := make([]string, len(_FormatNames))
+	copy(strs, _FormatNames)
+	return strs
+}
+
+// IsAFormat returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Format) IsAFormat() bool {
+	for _, v := range _FormatValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+)
+
+var _ slog.Handler = &Handler{}
+
+// Handler renders log records as syslog messages.
+type Handler struct {
+	options *slog.HandlerOptions
+	extras  *Extras
+	framing Framing
+	writer  io.Writer
+	closer  io.Closer
+	mutex   *sync.Mutex
+	params  []param
+	groups  []string
+
+	// defaultSDID is the SD-ID for attributes not in a group.
+	defaultSDID string
+}
+
+// NewHandler returns a new syslog handler with the specified output writer and slog.HandlerOptions.
+// If the options argument is nil it will be set to a level of slog.LevelInfo and nothing else.
+// If the extras argument is nil it will be set to defaults (RFC5424 with FramingNewline).
+//
+// Each message is written to the writer in a single Write call.
+// The ReplaceAttr function, if any, is applied to attributes but not to the header fields.
+func NewHandler(writer io.Writer, options *slog.HandlerOptions, extras *Extras) *Handler {
+	return newHandler(writer, nil, options, extras, FramingNewline)
+}
+
+// DialUnix returns a new syslog handler that sends messages to
+// the unix datagram socket at the specified path (e.g. "/dev/log").
+// Each message is sent as a single datagram, by default without framing.
+// The Close method should be called when the handler is no longer needed.
+func DialUnix(path string, options *slog.HandlerOptions, extras *Extras) (*Handler, error) {
+	conn, err := net.Dial("unixgram", path)
+	if err != nil {
+		return nil, fmt.Errorf("dial unix datagram socket: %w", err)
+	}
+	return newHandler(conn, conn, options, extras, FramingNone), nil
+}
+
+// DialTCP returns a new syslog handler that sends messages to
+// the TCP server at the specified address (e.g. "localhost:601"),
+// by default with octet-counting framing per RFC 6587.
+// The Close method should be called when the handler is no longer needed.
+func DialTCP(address string, options *slog.HandlerOptions, extras *Extras) (*Handler, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dial tcp: %w", err)
+	}
+	return newHandler(conn, conn, options, extras, FramingOctet), nil
+}
+
+func newHandler(writer io.Writer, closer io.Closer, options *slog.HandlerOptions, extras *Extras, framing Framing) *Handler {
+	hdlr := &Handler{
+		options: fixOptions(options),
+		extras:  fixExtras(extras),
+		framing: framing,
+		writer:  writer,
+		closer:  closer,
+		mutex:   &sync.Mutex{},
+	}
+	if hdlr.extras.Framing != FramingAuto {
+		hdlr.framing = hdlr.extras.Framing
+	}
+	hdlr.defaultSDID = hdlr.sdID(hdlr.extras.SDName)
+	return hdlr
+}
+
+// -----------------------------------------------------------------------------
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.options.Level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	params := make([]param, 0, len(h.params)+record.NumAttrs()+1)
+	if h.options.AddSource && record.PC != 0 {
+		params = append(params, param{
+			sdID:  h.defaultSDID,
+			name:  slog.SourceKey,
+			path:  slog.SourceKey,
+			value: source(record.PC),
+		})
+	}
+	params = append(params, h.params...)
+	record.Attrs(func(attr slog.Attr) bool {
+		params = h.addAttr(params, h.groups, attr)
+		return true // keep going
+	})
+
+	buffer := make([]byte, 0, 256)
+	if h.extras.Format == RFC3164 {
+		buffer = h.compose3164(buffer, record, params)
+	} else {
+		buffer = h.compose5424(buffer, record, params)
+	}
+	buffer = frame(buffer, h.framing)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, err := h.writer.Write(buffer); err != nil {
+		return fmt.Errorf("write syslog message: %w", err)
+	}
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	params := slices.Clip(h.params)
+	for _, attr := range attrs {
+		params = h.addAttr(params, h.groups, attr)
+	}
+	hdlr := h.clone()
+	hdlr.params = params
+	return hdlr
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		// Groups with empty names are to be inlined.
+		return h
+	}
+	hdlr := h.clone()
+	hdlr.groups = append(slices.Clip(h.groups), name)
+	return hdlr
+}
+
+// Close closes the connection for handlers created via DialUnix or DialTCP.
+// Closing any handler derived from such a handler via WithAttrs or WithGroup
+// closes the shared connection.
+// For handlers created via NewHandler this method does nothing,
+// the io.Writer provided to NewHandler is not closed.
+func (h *Handler) Close() error {
+	if h.closer == nil {
+		return nil
+	}
+	return h.closer.Close()
+}
+
+// -----------------------------------------------------------------------------
+
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		options:     h.options,
+		extras:      h.extras,
+		framing:     h.framing,
+		writer:      h.writer,
+		closer:      h.closer,
+		mutex:       h.mutex,
+		params:      h.params,
+		groups:      h.groups,
+		defaultSDID: h.defaultSDID,
+	}
+}
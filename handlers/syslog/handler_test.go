@@ -0,0 +1,199 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var when = time.Date(2024, 8, 21, 13, 14, 15, 123456789, time.UTC)
+
+func testExtras() *Extras {
+	return &Extras{
+		Hostname: "host.example.com",
+		AppName:  "app",
+		ProcID:   "1234",
+	}
+}
+
+func handle(t *testing.T, hdlr slog.Handler, level slog.Level, msg string, attrs ...slog.Attr) {
+	record := slog.NewRecord(when, level, msg, 0)
+	record.AddAttrs(attrs...)
+	require.NoError(t, hdlr.Handle(context.Background(), record))
+}
+
+// -----------------------------------------------------------------------------
+
+func TestRFC5424(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, nil, testExtras())
+	handle(t, hdlr, slog.LevelInfo, "no attributes")
+	handle(t, hdlr, slog.LevelWarn, "attributes",
+		slog.Int("count", 3),
+		slog.String("quote", `say "hi" \ [ok]`),
+		slog.Group("request", slog.String("method", "GET"),
+			slog.Group("headers", slog.String("accept", "*/*"))),
+		slog.Bool("done", true))
+	assert.Equal(t,
+		"<14>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - - no attributes\n"+
+			`<12>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - `+
+			`[attrs@32473 count="3" quote="say \"hi\" \\ [ok\]" done="true"]`+
+			`[request@32473 method="GET" headers.accept="*/*"] attributes`+"\n",
+		buffer.String())
+}
+
+func TestWith(t *testing.T) {
+	var buffer bytes.Buffer
+	extras := testExtras()
+	extras.Facility = FacilityLocal3
+	extras.EnterpriseID = "99999"
+	extras.MsgIDKey = "msgid"
+	hdlr := NewHandler(&buffer, nil, extras).
+		WithAttrs([]slog.Attr{slog.String("msgid", "ID47"), slog.String("alpha", "one")}).
+		WithGroup("exampleSDID@32473").
+		WithAttrs([]slog.Attr{slog.String("iut", "3")}).
+		WithGroup("inner")
+	handle(t, hdlr, slog.LevelError, "with", slog.String("eventID", "1011"))
+	assert.Equal(t,
+		`<155>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 ID47 `+
+			`[attrs@99999 alpha="one"][exampleSDID@32473 iut="3" inner.eventID="1011"] with`+"\n",
+		buffer.String())
+}
+
+func TestRFC3164(t *testing.T) {
+	var buffer bytes.Buffer
+	extras := testExtras()
+	extras.Format = RFC3164
+	extras.AppName = "my app"
+	hdlr := NewHandler(&buffer, nil, extras).WithGroup("group")
+	handle(t, hdlr, slog.LevelDebug+4, "message", slog.String("text", "two words"), slog.Int("n", 1))
+	assert.Equal(t,
+		`<14>Aug 21 13:14:15 host.example.com my_app[1234]: message group.text="two words" group.n=1`+"\n",
+		buffer.String())
+}
+
+func TestReplaceAttr(t *testing.T) {
+	var buffer bytes.Buffer
+	hdlr := NewHandler(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "secret" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}, testExtras())
+	handle(t, hdlr, slog.LevelInfo, "", slog.String("secret", "xyzzy"))
+	assert.Equal(t, "<14>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - -\n", buffer.String())
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "a_b_c_d_e", sanitizeName(`a b=c]d"e`, maxSDName))
+	assert.Equal(t, "_", sanitizeName("", maxSDName))
+	assert.Equal(t, strings.Repeat("x", maxSDName), sanitizeName(strings.Repeat("x", 40), maxSDName))
+	assert.Equal(t, "host_name", string(appendHeader(nil, "host name", maxHostname)))
+	assert.Equal(t, "-", string(appendHeader(nil, "", maxHostname)))
+}
+
+func TestSeverityOf(t *testing.T) {
+	assert.Equal(t, SeverityDebug, SeverityOf(slog.LevelDebug))
+	assert.Equal(t, SeverityInfo, SeverityOf(slog.LevelInfo))
+	assert.Equal(t, SeverityInfo, SeverityOf(slog.LevelInfo+2))
+	assert.Equal(t, SeverityWarning, SeverityOf(slog.LevelWarn))
+	assert.Equal(t, SeverityError, SeverityOf(slog.LevelError))
+	assert.Equal(t, SeverityCritical, SeverityOf(slog.LevelError+4))
+}
+
+// -----------------------------------------------------------------------------
+// Transports are verified byte-for-byte via local socket listeners.
+
+func TestDialUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	hdlr, err := DialUnix(path, nil, testExtras())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, hdlr.Close()) }()
+	handle(t, hdlr, slog.LevelInfo, "first")
+	handle(t, hdlr, slog.LevelInfo, "second", slog.Int("n", 2))
+
+	datagram := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	size, err := conn.Read(datagram)
+	require.NoError(t, err)
+	assert.Equal(t, "<14>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - - first",
+		string(datagram[:size]))
+	size, err = conn.Read(datagram)
+	require.NoError(t, err)
+	assert.Equal(t, `<14>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - [attrs@32473 n="2"] second`,
+		string(datagram[:size]))
+}
+
+func TestDialTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	hdlr, err := DialTCP(listener.Addr().String(), nil, testExtras())
+	require.NoError(t, err)
+	handle(t, hdlr, slog.LevelInfo, "first")
+	handle(t, hdlr, slog.LevelError, "second line\nwith newline")
+	require.NoError(t, hdlr.Close())
+
+	first := "<14>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - - first"
+	second := "<11>1 2024-08-21T13:14:15.123456Z host.example.com app 1234 - - second line\nwith newline"
+	expected := strconv.Itoa(len(first)) + " " + first + strconv.Itoa(len(second)) + " " + second
+	select {
+	case data := <-received:
+		assert.Equal(t, expected, string(data))
+		// Verify that the octet counts frame the messages.
+		reader := bufio.NewReader(bytes.NewReader(data))
+		for _, message := range []string{first, second} {
+			count, err := reader.ReadString(' ')
+			require.NoError(t, err)
+			size, err := strconv.Atoi(strings.TrimSpace(count))
+			require.NoError(t, err)
+			frame := make([]byte, size)
+			_, err = io.ReadFull(reader, frame)
+			require.NoError(t, err)
+			assert.Equal(t, message, string(frame))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for TCP data")
+	}
+}
+
+type errorWriter struct{}
+
+func (ew errorWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteError(t *testing.T) {
+	hdlr := NewHandler(errorWriter{}, nil, testExtras())
+	record := slog.NewRecord(when, slog.LevelInfo, "message", 0)
+	assert.ErrorContains(t, hdlr.Handle(context.Background(), record), "write failed")
+}
@@ -0,0 +1,76 @@
+package syslog
+
+import "log/slog"
+
+// Facility is a syslog facility code per RFC 5424.
+type Facility uint8
+
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityNTP
+	FacilityAudit
+	FacilityAlert
+	FacilityClock
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Severity is a syslog severity code per RFC 5424.
+type Severity uint8
+
+const (
+	SeverityEmergency Severity = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInfo
+	SeverityDebug
+)
+
+// SeverityOf returns the syslog severity for the specified level:
+//
+//   - levels below slog.LevelInfo are SeverityDebug,
+//   - levels from slog.LevelInfo up to slog.LevelWarn are SeverityInfo,
+//   - levels from slog.LevelWarn up to slog.LevelError are SeverityWarning,
+//   - levels from slog.LevelError up to slog.LevelError+4 are SeverityError, and
+//   - higher levels are SeverityCritical.
+//
+// SeverityNotice, SeverityAlert, and SeverityEmergency are only available via Extras.Severity.
+func SeverityOf(level slog.Level) Severity {
+	switch {
+	case level < slog.LevelInfo:
+		return SeverityDebug
+	case level < slog.LevelWarn:
+		return SeverityInfo
+	case level < slog.LevelError:
+		return SeverityWarning
+	case level < slog.LevelError+4:
+		return SeverityError
+	default:
+		return SeverityCritical
+	}
+}
+
+// priority returns the PRI value for the facility and severity.
+func priority(facility Facility, severity Severity) int {
+	return int(facility)*8 + int(severity&7)
+}
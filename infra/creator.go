@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -12,6 +13,11 @@ type CreateLoggerFn func(w io.Writer, options *slog.HandlerOptions) *slog.Logger
 // CreateHandlerFn is a function that can create new slog.Handler objects.
 type CreateHandlerFn func(w io.Writer, options *slog.HandlerOptions) slog.Handler
 
+// DecodeFn is a function that converts the output of a handler into JSON lines.
+// This is only required for handlers that don't generate JSON (e.g. binary output)
+// and is only used to verify the output, never during benchmark timing.
+type DecodeFn func(data []byte) ([]byte, error)
+
 // A Creator object encapsulates the creation of new slog.Handler objects.
 // This includes both the name of the handler and a CreateHandlerFn and/or CreateLoggerFn.
 // The reason for two functions is the possibility that a slog.Logger is available but a slog.Handler is not.
@@ -31,6 +37,7 @@ type Creator struct {
 	links     map[string]string
 	handlerFn CreateHandlerFn
 	loggerFn  CreateLoggerFn
+	decodeFn  DecodeFn
 }
 
 type Links map[string]string
@@ -82,6 +89,36 @@ func (c *Creator) CanMakeHandler() bool {
 	return c.handlerFn != nil
 }
 
+// SetDecodeFn configures a DecodeFn for handler output that isn't JSON.
+func (c *Creator) SetDecodeFn(fn DecodeFn) {
+	c.decodeFn = fn
+}
+
+// HasDecodeFn returns true if the handler output must be decoded before verification.
+func (c *Creator) HasDecodeFn() bool {
+	return c.decodeFn != nil
+}
+
+// Decode converts handler output into JSON lines via the configured DecodeFn.
+// Without a DecodeFn the output is returned as is.
+func (c *Creator) Decode(data []byte) ([]byte, error) {
+	if c.decodeFn == nil {
+		return data, nil
+	}
+	return c.decodeFn(data)
+}
+
+// DecodeWriter returns an io.Writer that converts handler output into JSON lines
+// via the configured DecodeFn before writing it to the specified io.Writer.
+// Each Write must contain complete log records.
+// Without a DecodeFn the specified io.Writer is returned as is.
+func (c *Creator) DecodeWriter(w io.Writer) io.Writer {
+	if c.decodeFn == nil {
+		return w
+	}
+	return &decodeWriter{writer: w, decodeFn: c.decodeFn}
+}
+
 // Name returns the name of the slog package.
 func (c *Creator) Name() string {
 	return c.name
@@ -102,3 +139,25 @@ func (c *Creator) HasSummary() bool {
 func (c *Creator) Summary() string {
 	return c.summary
 }
+
+// -----------------------------------------------------------------------------
+
+var _ io.Writer = &decodeWriter{}
+
+// decodeWriter is an io.Writer that converts handler output via a DecodeFn.
+type decodeWriter struct {
+	writer   io.Writer
+	decodeFn DecodeFn
+}
+
+// Write supplies the required io.Writer interface method.
+func (dw *decodeWriter) Write(p []byte) (int, error) {
+	lines, err := dw.decodeFn(p)
+	if err != nil {
+		return 0, fmt.Errorf("decode: %w", err)
+	}
+	if _, err := dw.writer.Write(lines); err != nil {
+		return 0, fmt.Errorf("write decoded: %w", err)
+	}
+	return len(p), nil
+}
@@ -23,6 +23,11 @@
 //   - SourceOptions() provides a simple set of options that adds source file data.
 //   - ReplaceAttrOptions() provides a simple set of options with the specified AttrFn.
 //
+// # Output Limits
+//
+// The [limit] sub-package defines safety limits on the output of a single log record
+// which are applied by the flash and sloggy handlers.
+//
 // # Warnings
 //
 // The [warning] sub-package provides the warning manager and predefined warnings.
@@ -30,5 +35,6 @@
 // [Creator]: https://pkg.go.dev/github.com/madkins23/go-slog/infra#Creator
 // [creator package]: https://pkg.go.dev/github.com/madkins23/go-slog/creator
 // [internal]: https://pkg.go.dev/github.com/madkins23/go-slog/internal
+// [limit]: https://pkg.go.dev/github.com/madkins23/go-slog/infra/limit
 // [warning]: https://pkg.go.dev/github.com/madkins23/go-slog/warning
 package infra
@@ -0,0 +1,22 @@
+// Package limit defines safety limits on the output of a single log record.
+//
+// Without limits a giant string, a deeply recursive slog.LogValuer,
+// or a self-referential group can produce multi-megabyte log lines or
+// recurse until the program crashes.
+// The flash and sloggy handlers apply these limits when configured with a [limit.Options] object:
+//
+//   - MaxMessageLength truncates the log message,
+//   - MaxValueLength truncates attribute values,
+//   - MaxGroupDepth replaces overly nested group values with "!DEPTH",
+//   - MaxAttrs drops attributes after the limit and reports the number dropped, and
+//   - MaxResolveDepth replaces overly nested slog.LogValuer values with "!RESOLVE".
+//
+// Truncated strings end with a marker ("..." by default).
+// Message, value, and attribute limits are not applied by default
+// in order to match the behavior of slog.JSONHandler.
+// Group and slog.LogValuer nesting is always limited
+// (to DefaultMaxGroupDepth and DefaultMaxResolveDepth unless configured otherwise)
+// so that self-referential data degrades gracefully instead of crashing the program.
+//
+// [limit.Options]: https://pkg.go.dev/github.com/madkins23/go-slog/infra/limit#Options
+package limit
@@ -0,0 +1,180 @@
+package limit
+
+import (
+	"log/slog"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMarker is appended to truncated strings if Options.Marker is not set.
+	DefaultMarker = "..."
+
+	// DefaultDroppedKey is the key for the count of dropped attributes if Options.DroppedKey is not set.
+	DefaultDroppedKey = "!DROPPED"
+
+	// DefaultMaxGroupDepth limits the nesting of group values if Options.MaxGroupDepth is not set.
+	DefaultMaxGroupDepth = 100
+
+	// DefaultMaxResolveDepth limits the nesting of slog.LogValuer values if Options.MaxResolveDepth is not set.
+	// This matches the limit used by slog.Value.Resolve for a chain of slog.LogValuer values.
+	DefaultMaxResolveDepth = 100
+
+	// DepthExceeded replaces group values nested more deeply than Options.MaxGroupDepth.
+	DepthExceeded = "!DEPTH"
+
+	// ResolveExceeded replaces slog.LogValuer values nested more deeply than Options.MaxResolveDepth.
+	ResolveExceeded = "!RESOLVE"
+)
+
+// Options defines limits on the output generated for a single log record.
+// Zero values for MaxMessageLength, MaxValueLength, and MaxAttrs mean there is no limit.
+// Zero values for MaxGroupDepth and MaxResolveDepth mean the default limits
+// so that self-referential data can never recurse without bound;
+// negative values for these two fields mean there is no limit.
+//
+// Methods on a nil *Options pointer are valid and only apply
+// the default MaxGroupDepth and MaxResolveDepth limits.
+type Options struct {
+	// MaxMessageLength limits the length of the log message in bytes.
+	// Longer messages are truncated and the Marker appended.
+	MaxMessageLength int
+
+	// MaxValueLength limits the length of attribute values in bytes.
+	// Longer string values are truncated and the Marker appended.
+	// Other values are limited by the length of their encoded form,
+	// in which case the truncated encoding is logged as a string.
+	MaxValueLength int
+
+	// Marker is appended to truncated strings.
+	// If not set defaults to DefaultMarker.
+	Marker string
+
+	// MaxGroupDepth limits the nesting of group values within an attribute.
+	// Deeper groups are replaced by the DepthExceeded string.
+	// Groups opened via slog.Logger.WithGroup are not counted.
+	// This also protects against self-referential groups.
+	// If not set defaults to DefaultMaxGroupDepth, if negative there is no limit.
+	MaxGroupDepth int
+
+	// MaxAttrs limits the number of attributes added to each log record
+	// by the slog.Logger message methods (e.g. Info).
+	// Any attributes after the limit are dropped and replaced by
+	// a single attribute with the DroppedKey and the number of dropped attributes.
+	MaxAttrs int
+
+	// DroppedKey is the key for the number of dropped attributes.
+	// If not set defaults to DefaultDroppedKey.
+	DroppedKey string
+
+	// MaxResolveDepth limits the nesting of slog.LogValuer values,
+	// each of which may resolve to a group containing more slog.LogValuer values.
+	// Deeper values are not resolved but replaced by the ResolveExceeded string.
+	// If not set defaults to DefaultMaxResolveDepth, if negative there is no limit.
+	MaxResolveDepth int
+}
+
+// Fix makes certain that an Options object has been configured with default values.
+// A nil Options pointer is returned as is.
+func Fix(options *Options) *Options {
+	if options == nil {
+		return nil
+	}
+	if options.Marker == "" {
+		options.Marker = DefaultMarker
+	}
+	if options.DroppedKey == "" {
+		options.DroppedKey = DefaultDroppedKey
+	}
+	return options
+}
+
+// -----------------------------------------------------------------------------
+
+// Message returns the specified log message truncated to MaxMessageLength.
+func (o *Options) Message(msg string) string {
+	if o == nil {
+		return msg
+	}
+	return o.truncate(msg, o.MaxMessageLength)
+}
+
+// Value returns the specified string value truncated to MaxValueLength.
+// The DepthExceeded and ResolveExceeded markers are never truncated.
+func (o *Options) Value(str string) string {
+	if o == nil || str == DepthExceeded || str == ResolveExceeded {
+		return str
+	}
+	return o.truncate(str, o.MaxValueLength)
+}
+
+// ValueTooLong returns true if a value of the specified length must be truncated.
+// This is used to check encoded values that are not strings.
+func (o *Options) ValueTooLong(length int) bool {
+	return o != nil && o.MaxValueLength > 0 && length > o.MaxValueLength
+}
+
+// Attrs returns true if another attribute may be added after the specified count.
+func (o *Options) Attrs(count int) bool {
+	return o == nil || o.MaxAttrs < 1 || count < o.MaxAttrs
+}
+
+// Dropped returns an attribute reporting the number of dropped attributes.
+func (o *Options) Dropped(count int) slog.Attr {
+	return slog.Int(o.DroppedKey, count)
+}
+
+// Group returns true if a group value may be nested at the specified depth,
+// where the outermost group value in an attribute is at depth 1.
+func (o *Options) Group(depth int) bool {
+	limit := o.maxGroupDepth()
+	return limit < 0 || depth <= limit
+}
+
+// GroupRemaining returns the number of additional group levels
+// that may be nested inside a group at the specified depth or -1 if there is no limit.
+func (o *Options) GroupRemaining(depth int) int {
+	limit := o.maxGroupDepth()
+	if limit < 0 {
+		return -1
+	}
+	return max(limit-depth, 0)
+}
+
+// Resolve returns true if a slog.LogValuer may be resolved at the specified depth,
+// where the outermost slog.LogValuer in an attribute is at depth 1.
+func (o *Options) Resolve(depth int) bool {
+	limit := o.maxResolveDepth()
+	return limit < 0 || depth <= limit
+}
+
+// -----------------------------------------------------------------------------
+
+// maxGroupDepth returns the effective group depth limit or a negative number if there is none.
+func (o *Options) maxGroupDepth() int {
+	if o == nil || o.MaxGroupDepth == 0 {
+		return DefaultMaxGroupDepth
+	}
+	return o.MaxGroupDepth
+}
+
+// maxResolveDepth returns the effective slog.LogValuer depth limit or a negative number if there is none.
+func (o *Options) maxResolveDepth() int {
+	if o == nil || o.MaxResolveDepth == 0 {
+		return DefaultMaxResolveDepth
+	}
+	return o.MaxResolveDepth
+}
+
+// -----------------------------------------------------------------------------
+
+// truncate the specified string to the specified number of bytes (if non-zero)
+// without splitting a UTF-8 sequence, appending the Marker if truncated.
+func (o *Options) truncate(str string, length int) string {
+	if length < 1 || len(str) <= length {
+		return str
+	}
+	for length > 0 && !utf8.RuneStart(str[length]) {
+		length--
+	}
+	return str[:length] + o.Marker
+}
@@ -0,0 +1,56 @@
+package limit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNil(t *testing.T) {
+	var options *Options
+	assert.Equal(t, "message", options.Message("message"))
+	assert.Equal(t, "value", options.Value("value"))
+	assert.False(t, options.ValueTooLong(1_000_000))
+	assert.True(t, options.Attrs(1_000_000))
+	assert.True(t, options.Group(DefaultMaxGroupDepth))
+	assert.False(t, options.Group(DefaultMaxGroupDepth+1))
+	assert.Equal(t, DefaultMaxGroupDepth-1, options.GroupRemaining(1))
+	assert.True(t, options.Resolve(DefaultMaxResolveDepth))
+	assert.False(t, options.Resolve(DefaultMaxResolveDepth+1))
+	assert.Nil(t, Fix(nil))
+}
+
+func TestUnlimited(t *testing.T) {
+	options := Fix(&Options{MaxGroupDepth: -1, MaxResolveDepth: -1})
+	assert.True(t, options.Group(1_000_000))
+	assert.Equal(t, -1, options.GroupRemaining(1))
+	assert.True(t, options.Resolve(1_000_000))
+}
+
+func TestTruncate(t *testing.T) {
+	options := Fix(&Options{MaxMessageLength: 5, MaxValueLength: 4})
+	assert.Equal(t, "short", options.Message("short"))
+	assert.Equal(t, "short...", options.Message("shorter"))
+	assert.Equal(t, "shor...", options.Value("short"))
+	// UTF-8 sequences are not split.
+	assert.Equal(t, "aç...", options.Value("aççd"))
+	assert.Equal(t, "日...", options.Message("日本語"))
+	// Markers are not truncated.
+	assert.Equal(t, ResolveExceeded, options.Value(ResolveExceeded))
+	options.Marker = "…"
+	assert.Equal(t, "abcd…", options.Value("abcdef"))
+}
+
+func TestLimits(t *testing.T) {
+	options := Fix(&Options{MaxAttrs: 2, MaxGroupDepth: 3, MaxResolveDepth: 1})
+	assert.True(t, options.Attrs(1))
+	assert.False(t, options.Attrs(2))
+	assert.Equal(t, DefaultDroppedKey, options.Dropped(3).Key)
+	assert.Equal(t, int64(3), options.Dropped(3).Value.Int64())
+	assert.True(t, options.Group(3))
+	assert.False(t, options.Group(4))
+	assert.Equal(t, 2, options.GroupRemaining(1))
+	assert.Equal(t, 0, options.GroupRemaining(5))
+	assert.True(t, options.Resolve(1))
+	assert.False(t, options.Resolve(2))
+}
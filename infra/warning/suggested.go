@@ -59,6 +59,13 @@ var (
 		The ^slog.JSONHandler^ converts ^Any^ objects that are ^map[string]any^ into JSON maps.
 		Some handlers convert these ^Any^ objects into strings instead of maps.`)
 
+	Ungraceful = NewWarning(LevelSuggested, "Ungraceful", "Handler does not degrade gracefully on pathological input", `
+		Handlers should not crash or emit invalid log records when presented with pathological input,
+		such as very large strings, deeply nested groups or ^slog.LogValuer^ chains,
+		thousands of attributes, a panicking ^slog.LogValuer^, or a self-referential ^Any^ value.
+		The ^slog.JSONHandler^ logs all of these as valid JSON,
+		reporting values it can't handle as ^"!ERROR:..."^ or ^"!PANIC: ..."^ strings.`)
+
 	TimeMillis = NewWarning(LevelSuggested, "TimeMillis", "slog.Time() logs milliseconds instead of nanoseconds", `
 		The ^slog.JSONHandler^ uses nanoseconds for ^time.Time^ but some other handlers use milliseconds.
 		This does _not_ apply to the basic ^time^ field, only attribute fields.
@@ -76,7 +83,7 @@ var (
 
 func init() {
 	// Always update this number when adding or removing Warning objects.
-	addTestCount(LevelSuggested, 13)
+	addTestCount(LevelSuggested, 14)
 }
 
 // Suggested returns an array of all LevelSuggested warnings.
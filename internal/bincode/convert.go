@@ -0,0 +1,528 @@
+package bincode
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	intJSON "github.com/madkins23/go-slog/internal/json"
+)
+
+// Format specifies the binary format of the data to be converted.
+type Format uint8
+
+const (
+	CBOR Format = iota
+	MsgPack
+)
+
+func (f Format) String() string {
+	switch f {
+	case CBOR:
+		return "CBOR"
+	case MsgPack:
+		return "MsgPack"
+	default:
+		return "Format(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// ErrIncomplete is returned when the data ends in the middle of a data item.
+var ErrIncomplete = errors.New("incomplete data item")
+
+// maxDepth limits nesting of arrays and maps so that bad data can't exhaust the stack.
+// This is the same limit used by encoding/json.
+const maxDepth = 10_000
+
+// -----------------------------------------------------------------------------
+
+// ToJSON converts the first data item in the binary data into JSON.
+// The remaining data after the first data item is also returned.
+// Map keys are kept in order, including duplicates, so that the resulting JSON
+// reflects the original data as closely as possible.
+// Byte strings are converted to base64 strings as with encoding/json,
+// CBOR tags are dropped, and MessagePack timestamps are converted to RFC 3339 strings.
+func ToJSON(format Format, data []byte) ([]byte, []byte, error) {
+	d := &decoder{data: data}
+	var err error
+	if format == MsgPack {
+		err = d.msgpack(0)
+	} else {
+		err = d.cbor(0)
+	}
+	if err != nil {
+		return nil, data, err
+	}
+	return d.out, data[d.pos:], nil
+}
+
+// Lines converts a series of binary log records into JSON lines.
+// The data must end at the end of a data item.
+func Lines(format Format, data []byte) ([]byte, error) {
+	var lines []byte
+	for len(data) > 0 {
+		line, rest, err := ToJSON(format, data)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s: %w", format, err)
+		}
+		lines = append(append(lines, line...), '\n')
+		data = rest
+	}
+	return lines, nil
+}
+
+// Parse converts a single binary log record into a map[string]any
+// with the same values as would be parsed from the equivalent JSON.
+func Parse(format Format, data []byte) (map[string]any, error) {
+	line, rest, err := ToJSON(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("convert %s: %w", format, err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%d bytes after data item", len(rest))
+	}
+	return intJSON.Parse(line)
+}
+
+// -----------------------------------------------------------------------------
+
+// decoder converts binary data items into JSON text.
+type decoder struct {
+	data []byte
+	pos  int
+	out  []byte
+}
+
+// next returns the next n bytes of data.
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, ErrIncomplete
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// uint returns the next n (1, 2, 4, or 8) bytes as a big-endian unsigned integer.
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.next(uint64(n))
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *decoder) appendBytes(b []byte) {
+	d.out = append(d.out, '"')
+	d.out = base64.StdEncoding.AppendEncode(d.out, b)
+	d.out = append(d.out, '"')
+}
+
+func (d *decoder) appendFloat(f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// Not representable in JSON.
+		d.appendString(strconv.FormatFloat(f, 'f', -1, 64))
+		return
+	}
+	d.out = strconv.AppendFloat(d.out, f, 'f', -1, 64)
+}
+
+func (d *decoder) appendString(s string) {
+	b, _ := json.Marshal(s)
+	d.out = append(d.out, b...)
+}
+
+// appendKey appends a map key and colon.
+// Keys that are not strings are converted into strings from their JSON form.
+func (d *decoder) appendKey(mark int) {
+	if d.out[mark] != '"' {
+		key := string(d.out[mark:])
+		d.out = d.out[:mark]
+		d.appendString(key)
+	}
+	d.out = append(d.out, ':')
+}
+
+// -----------------------------------------------------------------------------
+// CBOR (RFC 8949)
+
+const (
+	cborBreak      = 0xff
+	cborIndefinite = 31
+)
+
+// head returns the major type, additional information, and argument of the next CBOR data item.
+// Additional information cborIndefinite marks indefinite length items.
+func (d *decoder) head() (major, info byte, arg uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		arg, err = d.uint(1 << (info - 24))
+	case info != cborIndefinite:
+		err = fmt.Errorf("reserved additional information %d", info)
+	}
+	return major, info, arg, err
+}
+
+func (d *decoder) cbor(depth int) error {
+	if depth > maxDepth {
+		return errors.New("nested too deeply")
+	}
+	major, info, arg, err := d.head()
+	if err != nil {
+		return err
+	}
+	indefinite := info == cborIndefinite
+	if indefinite && (major < 2 || major == 6) {
+		return fmt.Errorf("indefinite length for major type %d", major)
+	}
+	switch major {
+	case 0:
+		d.out = strconv.AppendUint(d.out, arg, 10)
+	case 1:
+		if arg == math.MaxUint64 {
+			d.out = append(d.out, "-18446744073709551616"...)
+		} else {
+			d.out = append(d.out, '-')
+			d.out = strconv.AppendUint(d.out, arg+1, 10)
+		}
+	case 2, 3:
+		str, err := d.cborString(major, arg, indefinite)
+		if err != nil {
+			return err
+		}
+		if major == 2 {
+			d.appendBytes(str)
+		} else {
+			d.appendString(string(str))
+		}
+	case 4:
+		d.out = append(d.out, '[')
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if done, err := d.cborBreak(indefinite); err != nil {
+				return err
+			} else if done {
+				break
+			}
+			if i > 0 {
+				d.out = append(d.out, ',')
+			}
+			if err := d.cbor(depth + 1); err != nil {
+				return err
+			}
+		}
+		d.out = append(d.out, ']')
+	case 5:
+		d.out = append(d.out, '{')
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if done, err := d.cborBreak(indefinite); err != nil {
+				return err
+			} else if done {
+				break
+			}
+			if i > 0 {
+				d.out = append(d.out, ',')
+			}
+			mark := len(d.out)
+			if err := d.cbor(depth + 1); err != nil {
+				return err
+			}
+			d.appendKey(mark)
+			if err := d.cbor(depth + 1); err != nil {
+				return err
+			}
+		}
+		d.out = append(d.out, '}')
+	case 6:
+		// Tags only add meaning to the tagged data item.
+		return d.cbor(depth + 1)
+	case 7:
+		return d.cborSimple(info, arg)
+	}
+	return nil
+}
+
+// cborBreak checks for the break code at the end of an indefinite length item.
+func (d *decoder) cborBreak(indefinite bool) (bool, error) {
+	if !indefinite {
+		return false, nil
+	}
+	if d.pos >= len(d.data) {
+		return false, ErrIncomplete
+	}
+	if d.data[d.pos] == cborBreak {
+		d.pos++
+		return true, nil
+	}
+	return false, nil
+}
+
+// cborString returns the content of a byte or text string,
+// concatenating the chunks of an indefinite length string.
+func (d *decoder) cborString(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.next(arg)
+	}
+	var str []byte
+	for {
+		if done, err := d.cborBreak(true); err != nil {
+			return nil, err
+		} else if done {
+			return str, nil
+		}
+		chunkMajor, chunkInfo, chunkArg, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == cborIndefinite {
+			return nil, errors.New("bad indefinite length string chunk")
+		}
+		chunk, err := d.next(chunkArg)
+		if err != nil {
+			return nil, err
+		}
+		str = append(str, chunk...)
+	}
+}
+
+// cborSimple converts simple values and floats, for which the argument is the bit pattern.
+func (d *decoder) cborSimple(info byte, arg uint64) error {
+	switch {
+	case info == cborIndefinite:
+		return errors.New("unexpected break")
+	case info == 25:
+		d.appendFloat(halfFloat(uint16(arg)))
+	case info == 26:
+		d.appendFloat(float64(math.Float32frombits(uint32(arg))))
+	case info == 27:
+		d.appendFloat(math.Float64frombits(arg))
+	case arg == 20:
+		d.out = append(d.out, "false"...)
+	case arg == 21:
+		d.out = append(d.out, "true"...)
+	default:
+		// Null, undefined, and unassigned simple values.
+		d.out = append(d.out, "null"...)
+	}
+	return nil
+}
+
+// halfFloat converts an IEEE 754 half-precision float to a float64.
+func halfFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1.0
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(frac+1024, exp-25)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// MessagePack
+
+// msgpackTimestamp is the extension type for timestamps.
+const msgpackTimestamp = -1
+
+func (d *decoder) msgpack(depth int) error {
+	if depth > maxDepth {
+		return errors.New("nested too deeply")
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return err
+	}
+	code := b[0]
+	switch {
+	case code <= 0x7f:
+		d.out = strconv.AppendUint(d.out, uint64(code), 10)
+		return nil
+	case code >= 0xe0:
+		d.out = strconv.AppendInt(d.out, int64(int8(code)), 10)
+		return nil
+	case code <= 0x8f:
+		return d.msgpackMap(uint64(code&0x0f), depth)
+	case code <= 0x9f:
+		return d.msgpackArray(uint64(code&0x0f), depth)
+	case code <= 0xbf:
+		return d.msgpackString(uint64(code & 0x1f))
+	}
+	switch code {
+	case 0xc0:
+		d.out = append(d.out, "null"...)
+	case 0xc2:
+		d.out = append(d.out, "false"...)
+	case 0xc3:
+		d.out = append(d.out, "true"...)
+	case 0xc4, 0xc5, 0xc6:
+		length, err := d.uint(1 << (code - 0xc4))
+		if err != nil {
+			return err
+		}
+		bytes, err := d.next(length)
+		if err != nil {
+			return err
+		}
+		d.appendBytes(bytes)
+	case 0xc7, 0xc8, 0xc9:
+		length, err := d.uint(1 << (code - 0xc7))
+		if err != nil {
+			return err
+		}
+		return d.msgpackExt(length)
+	case 0xca:
+		bits, err := d.uint(4)
+		if err != nil {
+			return err
+		}
+		d.appendFloat(float64(math.Float32frombits(uint32(bits))))
+	case 0xcb:
+		bits, err := d.uint(8)
+		if err != nil {
+			return err
+		}
+		d.appendFloat(math.Float64frombits(bits))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (code - 0xcc))
+		if err != nil {
+			return err
+		}
+		d.out = strconv.AppendUint(d.out, u, 10)
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		u, err := d.uint(size)
+		if err != nil {
+			return err
+		}
+		// Sign extend from the encoded size.
+		shift := 64 - 8*size
+		d.out = strconv.AppendInt(d.out, int64(u<<shift)>>shift, 10)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.msgpackExt(1 << (code - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		length, err := d.uint(1 << (code - 0xd9))
+		if err != nil {
+			return err
+		}
+		return d.msgpackString(length)
+	case 0xdc, 0xdd:
+		length, err := d.uint(2 << (code - 0xdc))
+		if err != nil {
+			return err
+		}
+		return d.msgpackArray(length, depth)
+	case 0xde, 0xdf:
+		length, err := d.uint(2 << (code - 0xde))
+		if err != nil {
+			return err
+		}
+		return d.msgpackMap(length, depth)
+	default:
+		return fmt.Errorf("unused type code %#x", code)
+	}
+	return nil
+}
+
+func (d *decoder) msgpackArray(length uint64, depth int) error {
+	d.out = append(d.out, '[')
+	for i := uint64(0); i < length; i++ {
+		if i > 0 {
+			d.out = append(d.out, ',')
+		}
+		if err := d.msgpack(depth + 1); err != nil {
+			return err
+		}
+	}
+	d.out = append(d.out, ']')
+	return nil
+}
+
+func (d *decoder) msgpackMap(length uint64, depth int) error {
+	d.out = append(d.out, '{')
+	for i := uint64(0); i < length; i++ {
+		if i > 0 {
+			d.out = append(d.out, ',')
+		}
+		mark := len(d.out)
+		if err := d.msgpack(depth + 1); err != nil {
+			return err
+		}
+		d.appendKey(mark)
+		if err := d.msgpack(depth + 1); err != nil {
+			return err
+		}
+	}
+	d.out = append(d.out, '}')
+	return nil
+}
+
+func (d *decoder) msgpackString(length uint64) error {
+	str, err := d.next(length)
+	if err != nil {
+		return err
+	}
+	d.appendString(string(str))
+	return nil
+}
+
+// msgpackExt converts extension data with the specified length following the type.
+// Timestamps are converted to RFC 3339 strings, other extensions to base64 strings.
+func (d *decoder) msgpackExt(length uint64) error {
+	b, err := d.next(1)
+	if err != nil {
+		return err
+	}
+	extType := int8(b[0])
+	data, err := d.next(length)
+	if err != nil {
+		return err
+	}
+	if extType != msgpackTimestamp {
+		d.appendBytes(data)
+		return nil
+	}
+	var t time.Time
+	switch length {
+	case 4:
+		t = time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		bits := binary.BigEndian.Uint64(data)
+		t = time.Unix(int64(bits&0x3ffffffff), int64(bits>>34))
+	case 12:
+		t = time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data)))
+	default:
+		return fmt.Errorf("bad timestamp length %d", length)
+	}
+	d.appendString(t.UTC().Format(time.RFC3339Nano))
+	return nil
+}
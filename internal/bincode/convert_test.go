@@ -0,0 +1,145 @@
+package bincode
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSON_CBOR(t *testing.T) {
+	// Most examples are from RFC 8949 Appendix A.
+	for _, tc := range []struct {
+		hex, json string
+	}{
+		{`00`, `0`},
+		{`17`, `23`},
+		{`1818`, `24`},
+		{`1903e8`, `1000`},
+		{`1bffffffffffffffff`, `18446744073709551615`},
+		{`20`, `-1`},
+		{`3903e7`, `-1000`},
+		{`3bffffffffffffffff`, `-18446744073709551616`},
+		{`f93e00`, `1.5`},
+		{`f97bff`, `65504`},
+		{`fa47c35000`, `100000`},
+		{`fb3ff199999999999a`, `1.1`},
+		{`f97c00`, `"+Inf"`},
+		{`f4`, `false`},
+		{`f5`, `true`},
+		{`f6`, `null`},
+		{`f7`, `null`},
+		{`4401020304`, `"AQIDBA=="`},
+		{`6449455446`, `"IETF"`},
+		{`62225c`, `"\"\\"`},
+		{`c074323031332d30332d32315432303a30343a30305a`, `"2013-03-21T20:04:00Z"`},
+		{`83010203`, `[1,2,3]`},
+		{`a201020304`, `{"1":2,"3":4}`},
+		{`a26161016162820203`, `{"a":1,"b":[2,3]}`},
+		{`bf61610161629f0203ffff`, `{"a":1,"b":[2,3]}`},
+		{`7f657374726561646d696e67ff`, `"streaming"`},
+		{`ba00000002616101616102`, `{"a":1,"a":2}`},
+	} {
+		t.Run(tc.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+			line, rest, err := ToJSON(CBOR, data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(line))
+			assert.Empty(t, rest)
+		})
+	}
+}
+
+func TestToJSON_MsgPack(t *testing.T) {
+	for _, tc := range []struct {
+		hex, json string
+	}{
+		{`00`, `0`},
+		{`7f`, `127`},
+		{`ff`, `-1`},
+		{`e0`, `-32`},
+		{`cc80`, `128`},
+		{`cdffff`, `65535`},
+		{`d080`, `-128`},
+		{`d1fc18`, `-1000`},
+		{`d3ffffffffffffffff`, `-1`},
+		{`cb3ff199999999999a`, `1.1`},
+		{`ca3fc00000`, `1.5`},
+		{`c0`, `null`},
+		{`c2`, `false`},
+		{`c3`, `true`},
+		{`a449455446`, `"IETF"`},
+		{`d90449455446`, `"IETF"`},
+		{`c40401020304`, `"AQIDBA=="`},
+		{`93010203`, `[1,2,3]`},
+		{`82a16101a162920203`, `{"a":1,"b":[2,3]}`},
+		{`df00000002a16101a16102`, `{"a":1,"a":2}`},
+		{`d6ff514b67b0`, `"2013-03-21T20:04:00Z"`},
+		{`d40102`, `"Ag=="`},
+	} {
+		t.Run(tc.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+			line, rest, err := ToJSON(MsgPack, data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(line))
+			assert.Empty(t, rest)
+		})
+	}
+}
+
+func TestToJSON_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		format     Format
+		hex        string
+		incomplete bool
+	}{
+		{CBOR, ``, true},
+		{CBOR, `19`, true},
+		{CBOR, `a26161`, true},
+		{CBOR, `bf6161`, true},
+		{CBOR, `1c`, false},
+		{CBOR, `1f`, false},
+		{CBOR, `ff`, false},
+		{MsgPack, ``, true},
+		{MsgPack, `cd01`, true},
+		{MsgPack, `82a161`, true},
+		{MsgPack, `c1`, false},
+	} {
+		t.Run(tc.format.String()+":"+tc.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+			_, rest, err := ToJSON(tc.format, data)
+			require.Error(t, err)
+			assert.Equal(t, tc.incomplete, err == ErrIncomplete)
+			assert.Equal(t, data, rest)
+		})
+	}
+	_, err := Parse(CBOR, bytes.Repeat([]byte{0x81}, maxDepth+2))
+	assert.ErrorContains(t, err, "nested too deeply")
+}
+
+func TestParse(t *testing.T) {
+	data, err := hex.DecodeString(`a2616101616282f5f6`)
+	require.NoError(t, err)
+	logMap, err := Parse(CBOR, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": float64(1), "b": []any{true, nil}}, logMap)
+	_, err = Parse(CBOR, append(data, 0x00))
+	assert.ErrorContains(t, err, "1 bytes after data item")
+}
+
+func TestLines(t *testing.T) {
+	data, err := hex.DecodeString(`81a16101` + `81a16102` + `81a16103`)
+	require.NoError(t, err)
+	lines, err := Lines(MsgPack, data)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n", string(lines))
+	_, err = Lines(MsgPack, data[:6])
+	assert.ErrorIs(t, err, ErrIncomplete)
+	_, err = Lines(MsgPack, []byte{0xc1})
+	assert.ErrorContains(t, err, "convert MsgPack")
+}
@@ -0,0 +1,5 @@
+// Package bincode converts binary (CBOR and MessagePack) log records into JSON.
+//
+// This allows handlers that generate binary output to be tested by
+// the verification suite, which expects JSON log records.
+package bincode
@@ -10,6 +10,14 @@
 //
 // JSON functionality used by various tests.
 //
+// # Bincode
+//
+// Conversion of binary (CBOR and MessagePack) log records into JSON for verification of binary handlers.
+//
+// # Logfmt
+//
+// Conversion of key=value (logfmt) log lines into JSON for verification of text handlers.
+//
 // # Language
 //
 // Provide language-appropriate formatting of numbers.
@@ -0,0 +1,163 @@
+package logfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// field is a node in the tree of fields built from dotted keys.
+// Fields are kept in order, including duplicates, so that the resulting JSON
+// reflects the original text as closely as possible.
+type field struct {
+	key    string
+	value  []byte
+	fields []*field
+}
+
+// group returns the subgroup with the specified key, creating one if necessary.
+// The most recent field with the key is reused if it is a group.
+func (f *field) group(key string) *field {
+	for i := len(f.fields) - 1; i >= 0; i-- {
+		if f.fields[i].key == key {
+			if f.fields[i].value == nil {
+				return f.fields[i]
+			}
+			break
+		}
+	}
+	grp := &field{key: key}
+	f.fields = append(f.fields, grp)
+	return grp
+}
+
+func (f *field) appendJSON(buffer []byte) []byte {
+	if f.value != nil {
+		return append(buffer, f.value...)
+	}
+	buffer = append(buffer, '{')
+	for i, fld := range f.fields {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		key, _ := json.Marshal(fld.key)
+		buffer = append(buffer, key...)
+		buffer = append(buffer, ':')
+		buffer = fld.appendJSON(buffer)
+	}
+	return append(buffer, '}')
+}
+
+// -----------------------------------------------------------------------------
+
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// ToJSON converts a single line of logfmt text into a JSON object.
+// Dotted keys are converted into nested JSON objects.
+// Quoted values and bare values that are valid JSON are kept as is,
+// other bare values are converted into JSON strings.
+// ANSI color codes are removed before parsing.
+func ToJSON(line []byte) ([]byte, error) {
+	line = ansiCodes.ReplaceAll(bytes.TrimRight(line, "\r\n"), nil)
+	root := &field{}
+	for i := 0; ; {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i >= len(line) {
+			break
+		}
+		key, next, err := parseKey(line, i)
+		if err != nil {
+			return nil, fmt.Errorf("parse key at %d: %w", i, err)
+		}
+		value, next, err := parseValue(line, next)
+		if err != nil {
+			return nil, fmt.Errorf("parse value for '%s': %w", key, err)
+		}
+		parent := root
+		names := strings.Split(key, ".")
+		for _, name := range names[:len(names)-1] {
+			parent = parent.group(name)
+		}
+		parent.fields = append(parent.fields, &field{key: names[len(names)-1], value: value})
+		i = next
+	}
+	return root.appendJSON(make([]byte, 0, 2*len(line))), nil
+}
+
+// parseKey parses a key, quoted or bare, followed by an equals sign.
+func parseKey(line []byte, i int) (string, int, error) {
+	var key string
+	if line[i] == '"' {
+		end, err := scanQuoted(line, i)
+		if err != nil {
+			return "", 0, err
+		}
+		if err := json.Unmarshal(line[i:end], &key); err != nil {
+			return "", 0, fmt.Errorf("unmarshal quoted key: %w", err)
+		}
+		i = end
+	} else {
+		end := bytes.IndexByte(line[i:], '=')
+		if end < 0 {
+			return "", 0, fmt.Errorf("no equals sign")
+		}
+		key = string(line[i : i+end])
+		i += end
+	}
+	if i >= len(line) || line[i] != '=' {
+		return "", 0, fmt.Errorf("no equals sign")
+	}
+	return key, i + 1, nil
+}
+
+// parseValue parses a value and returns it as JSON.
+func parseValue(line []byte, i int) ([]byte, int, error) {
+	if i >= len(line) || line[i] == ' ' {
+		return []byte(`""`), i, nil
+	}
+	switch line[i] {
+	case '"':
+		end, err := scanQuoted(line, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		return line[i:end], end, nil
+	case '{', '[':
+		var raw json.RawMessage
+		decoder := json.NewDecoder(bytes.NewReader(line[i:]))
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, 0, fmt.Errorf("decode JSON value: %w", err)
+		}
+		return raw, i + int(decoder.InputOffset()), nil
+	}
+	end := bytes.IndexByte(line[i:], ' ')
+	if end < 0 {
+		end = len(line)
+	} else {
+		end += i
+	}
+	if token := line[i:end]; json.Valid(token) {
+		return token, end, nil
+	} else if value, err := json.Marshal(string(token)); err != nil {
+		return nil, 0, fmt.Errorf("marshal bare value: %w", err)
+	} else {
+		return value, end, nil
+	}
+}
+
+// scanQuoted returns the index just past the end of the quoted string starting at i.
+func scanQuoted(line []byte, i int) (int, error) {
+	for j := i + 1; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated quoted string")
+}
@@ -0,0 +1,58 @@
+package logfmt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSON(t *testing.T) {
+	for _, tc := range []struct {
+		text, json string
+	}{
+		{``, `{}`},
+		{`msg=hello`, `{"msg":"hello"}`},
+		{`msg="hello world" n=23 f=3.14 b=true z=null`, `{"msg":"hello world","n":23,"f":3.14,"b":true,"z":null}`},
+		{`s="23" t="true"`, `{"s":"23","t":"true"}`},
+		{`time=2024-01-02T03:04:05Z`, `{"time":"2024-01-02T03:04:05Z"}`},
+		{`m={"x": [1, 2]} a=[1,2]`, `{"m":{"x": [1, 2]},"a":[1,2]}`},
+		{`g.a=1 g.h.b=2 g.c=3`, `{"g":{"a":1,"h":{"b":2},"c":3}}`},
+		{`"key with space"=1 "a.b"=2`, `{"key with space":1,"a":{"b":2}}`},
+		{`a=1 a=2`, `{"a":1,"a":2}`},
+		{`q="say \"hi\"\n"`, `{"q":"say \"hi\"\n"}`},
+		{"level=\x1b[32mINFO\x1b[0m  msg=x   ", `{"level":"INFO","msg":"x"}`},
+		{`e=`, `{"e":""}`},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			line, err := ToJSON([]byte(tc.text))
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(line))
+		})
+	}
+}
+
+func TestToJSON_Errors(t *testing.T) {
+	for _, text := range []string{
+		`bare`,
+		`q="unterminated`,
+		`m={"x":`,
+		`"key"`,
+	} {
+		_, err := ToJSON([]byte(text))
+		assert.Error(t, err, text)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	w := NewWriter(&buffer)
+	n, err := w.Write([]byte("a=1 b="))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Empty(t, buffer.String())
+	_, err = w.Write([]byte("two\nc.d=3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":1,\"b\":\"two\"}\n{\"c\":{\"d\":3}}\n", buffer.String())
+}
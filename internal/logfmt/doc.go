@@ -0,0 +1,5 @@
+// Package logfmt converts key=value (logfmt) log lines into JSON.
+//
+// This allows handlers that generate text output to be tested by
+// the verification suite, which expects JSON log records.
+package logfmt
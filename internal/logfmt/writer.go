@@ -0,0 +1,46 @@
+package logfmt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var _ io.Writer = &Writer{}
+
+// Writer is an io.Writer that converts logfmt lines into JSON lines
+// before writing them to the wrapped io.Writer.
+// Partial lines are buffered until the end of the line is written.
+type Writer struct {
+	writer  io.Writer
+	partial []byte
+	mutex   sync.Mutex
+}
+
+// NewWriter returns a Writer that writes JSON lines to the specified io.Writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{writer: w}
+}
+
+// Write supplies the required io.Writer interface method.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		end := bytes.IndexByte(w.partial, '\n')
+		if end < 0 {
+			break
+		}
+		line, err := ToJSON(w.partial[:end])
+		w.partial = w.partial[end+1:]
+		if err != nil {
+			return 0, fmt.Errorf("convert logfmt: %w", err)
+		}
+		if _, err := w.writer.Write(append(line, '\n')); err != nil {
+			return 0, fmt.Errorf("write JSON: %w", err)
+		}
+	}
+	return len(p), nil
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/madkins23/go-slog/creator/madkinsflash"
+	"github.com/madkins23/go-slog/creator/madkinsflashdedup"
 	"github.com/madkins23/go-slog/creator/madkinsreplattr"
 	"github.com/madkins23/go-slog/creator/slogjson"
 	"github.com/madkins23/go-slog/creator/veqryndedup"
+	"github.com/madkins23/go-slog/handlers/flash"
 )
 
 func TestBasic(t *testing.T) {
@@ -20,4 +22,5 @@ func TestBasic(t *testing.T) {
 	assert.False(t, filter.Keep(madkinsreplattr.Name))
 	assert.False(t, filter.Keep(veqryndedup.Name(veqryndedup.Append)))
 	assert.False(t, filter.Keep(veqryndedup.Name(veqryndedup.Overwrite)))
+	assert.False(t, filter.Keep(madkinsflashdedup.Name(flash.DedupOverwrite)))
 }
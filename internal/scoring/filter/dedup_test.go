@@ -7,8 +7,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/madkins23/go-slog/creator/madkinsflash"
+	"github.com/madkins23/go-slog/creator/madkinsflashdedup"
 	"github.com/madkins23/go-slog/creator/slogjson"
 	"github.com/madkins23/go-slog/creator/veqryndedup"
+	"github.com/madkins23/go-slog/handlers/flash"
 )
 
 func TestDedup(t *testing.T) {
@@ -16,6 +18,8 @@ func TestDedup(t *testing.T) {
 	require.NotNil(t, filter)
 	assert.False(t, filter.Keep(madkinsflash.Name))
 	assert.True(t, filter.Keep(slogjson.Name))
+	assert.True(t, filter.Keep(madkinsflashdedup.Name(flash.DedupAppend)))
+	assert.True(t, filter.Keep(madkinsflashdedup.Name(flash.DedupOverwrite)))
 	assert.True(t, filter.Keep(veqryndedup.Name(veqryndedup.Append)))
 	assert.True(t, filter.Keep(veqryndedup.Name(veqryndedup.Ignore)))
 	assert.True(t, filter.Keep(veqryndedup.Name(veqryndedup.Increment)))
@@ -1,8 +1,10 @@
 package group
 
 import (
+	"github.com/madkins23/go-slog/creator/madkinsflashdedup"
 	"github.com/madkins23/go-slog/creator/snqkmeld"
 	"github.com/madkins23/go-slog/creator/veqryndedup"
+	"github.com/madkins23/go-slog/handlers/flash"
 	"github.com/madkins23/go-slog/internal/scoring/score"
 )
 
@@ -13,6 +15,10 @@ var dedup *score.Group
 func Dedup() *score.Group {
 	if dedup == nil {
 		dedup = score.NewFilterGroup(
+			madkinsflashdedup.Name(flash.DedupAppend),
+			madkinsflashdedup.Name(flash.DedupIgnore),
+			madkinsflashdedup.Name(flash.DedupIncrement),
+			madkinsflashdedup.Name(flash.DedupOverwrite),
 			snqkmeld.Name,
 			veqryndedup.Name(veqryndedup.Append),
 			veqryndedup.Name(veqryndedup.Ignore),
@@ -3,5 +3,6 @@
 //   - Case defines test cases stored in JSON files.
 //   - CountWriter is an io.Writer that counts lines and bytes and then throws them away.
 //   - Debugf provides a simplistic logging for use in test cases.
+//   - LoopValuer, PanicStringer, Cycle, and SelfGroup provide pathological values.
 //   - Various constants and variables for use in multiple testing files.
 package test
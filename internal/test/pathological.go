@@ -0,0 +1,47 @@
+package test
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// -----------------------------------------------------------------------------
+// Pathological values used for testing graceful degradation and limits.
+
+var _ slog.LogValuer = LoopValuer{}
+
+// LoopValuer resolves to a group containing another LoopValuer, forever.
+type LoopValuer struct{}
+
+func (lv LoopValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("loop", LoopValuer{}))
+}
+
+var _ fmt.Stringer = PanicStringer{}
+
+// PanicStringer is a fmt.Stringer that panics.
+type PanicStringer struct{}
+
+func (ps PanicStringer) String() string {
+	panic("PanicStringer")
+}
+
+// Cycle is a linked list node that can be made to refer to itself.
+type Cycle struct {
+	Name string
+	Next *Cycle
+}
+
+// NewCycle returns a Cycle node that refers to itself.
+func NewCycle(name string) *Cycle {
+	cycle := &Cycle{Name: name}
+	cycle.Next = cycle
+	return cycle
+}
+
+// SelfGroup returns a group attribute with the specified key that contains itself.
+func SelfGroup(key string) slog.Attr {
+	self := make([]slog.Attr, 1)
+	self[0] = slog.Attr{Key: key, Value: slog.GroupValue(self...)}
+	return self[0]
+}
@@ -11,12 +11,16 @@ var _ io.Writer = &CountWriter{}
 // counts `Write` calls and the number of bytes that would have been written.
 // This is used during benchmarking.
 type CountWriter struct {
+	// EveryWrite counts all `Write` calls instead of only those that end a line.
+	// This is used for handlers with binary output.
+	EveryWrite bool
+
 	count atomic.Uint64
 }
 
 // Write supplies the required io.Writer interface method.
 func (cw *CountWriter) Write(p []byte) (n int, err error) {
-	if len(p) > 0 && p[len(p)-1] == '\n' {
+	if cw.EveryWrite || len(p) > 0 && p[len(p)-1] == '\n' {
 		cw.count.Add(1)
 	}
 	return len(p), nil
@@ -0,0 +1,338 @@
+package replace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/madkins23/go-slog/infra"
+)
+
+// Config rule actions.
+const (
+	ActionRename = "rename"
+	ActionRemove = "remove"
+	ActionCase   = "case"
+	ActionSet    = "set"
+	ActionRedact = "redact"
+)
+
+// ConfigError describes a problem with a configuration document.
+type ConfigError struct {
+	Line, Column int
+	Message      string
+}
+
+func (ce *ConfigError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", ce.Line, ce.Column, ce.Message)
+}
+
+func configError(node *yaml.Node, format string, args ...any) error {
+	return &ConfigError{Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+// -----------------------------------------------------------------------------
+
+// LoadConfigFile loads a ReplaceAttr pipeline from a YAML or JSON file.
+// See LoadConfig for details.
+func LoadConfigFile(filePath string) (infra.AttrFn, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	fn, err := LoadConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", filePath, err)
+	}
+	return fn, nil
+}
+
+// LoadConfig loads a ReplaceAttr pipeline from a YAML or JSON document.
+// The document contains a list of rules which are chained via Multiple:
+//
+//	rules:
+//	  - action: rename              # rename, remove, case, set, or redact
+//	    key: message                # attribute key to match
+//	    to: msg                     # new key (rename)
+//	    groups: ""                  # optional group path expression (see Path), "" is top level only
+//	  - action: remove
+//	    key: time
+//	    groups: ""
+//	  - action: case
+//	    key: level
+//	    case: upper                 # upper or lower (case)
+//	  - action: set
+//	    key: env
+//	    value: production           # any scalar value (set)
+//	  - action: redact
+//	    keys: ["*password*"]        # glob patterns instead of a single key (redact)
+//	    caseInsensitive: true       # match keys without regard to case
+//	  - action: redact
+//	    key: card
+//	    keepLast: 4                 # partial mask (redact)
+//	  - action: redact
+//	    key: email
+//	    hashSalt: "pepper"          # salted hash (redact)
+//	  - action: redact
+//	    patterns: [creditCards, bearerTokens, emails]  # value patterns (redact)
+//
+// Rules without a groups field apply to attributes in any group.
+// All errors found in the document are returned,
+// each as a *ConfigError with line and column information.
+func LoadConfig(reader io.Reader) (infra.AttrFn, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(reader).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("empty config document")
+		}
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, configError(root, "config must be a mapping with a rules list")
+	}
+	var rules *yaml.Node
+	var errs []error
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "rules" {
+			rules = root.Content[i+1]
+		} else {
+			errs = append(errs, configError(root.Content[i], "unknown field %q", root.Content[i].Value))
+		}
+	}
+	if rules == nil {
+		errs = append(errs, configError(root, "missing rules list"))
+	} else if rules.Kind != yaml.SequenceNode {
+		errs = append(errs, configError(rules, "rules must be a list"))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	fns := make([]infra.AttrFn, 0, len(rules.Content))
+	for _, node := range rules.Content {
+		if fn, err := buildRule(node); err != nil {
+			errs = append(errs, err)
+		} else {
+			fns = append(fns, fn)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return Multiple(fns...), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// configRule is the decoded form of a single rule.
+type configRule struct {
+	Action          string   `yaml:"action"`
+	Key             string   `yaml:"key"`
+	Keys            []string `yaml:"keys"`
+	CaseInsensitive bool     `yaml:"caseInsensitive"`
+	Groups          *string  `yaml:"groups"`
+	To              string   `yaml:"to"`
+	Case            string   `yaml:"case"`
+	Value           any      `yaml:"value"`
+	KeepLast        int      `yaml:"keepLast"`
+	HashSalt        string   `yaml:"hashSalt"`
+	Patterns        []string `yaml:"patterns"`
+}
+
+// ruleFields lists the fields allowed for each action in addition to action, groups, and caseInsensitive.
+var ruleFields = map[string][]string{
+	ActionRename: {"key", "to"},
+	ActionRemove: {"key"},
+	ActionCase:   {"key", "case"},
+	ActionSet:    {"key", "value"},
+	ActionRedact: {"key", "keys", "keepLast", "hashSalt", "patterns"},
+}
+
+var valuePatterns = map[string]ValuePattern{
+	"creditCards":  CreditCards,
+	"bearerTokens": BearerTokens,
+	"emails":       Emails,
+}
+
+var changeCases = map[string]ChangeCases{
+	"lower": CaseLower,
+	"upper": CaseUpper,
+}
+
+// buildRule validates a single rule node and returns the matching ReplaceAttr function.
+func buildRule(node *yaml.Node) (infra.AttrFn, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, configError(node, "rule must be a mapping")
+	}
+	var rule configRule
+	if err := node.Decode(&rule); err != nil {
+		return nil, configError(node, "decode rule: %s", typeErrorMessage(err))
+	}
+	allowed, found := ruleFields[rule.Action]
+	if !found {
+		actions := make([]string, 0, len(ruleFields))
+		for action := range ruleFields {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		if rule.Action == "" {
+			return nil, configError(node, "missing action")
+		}
+		return nil, configError(fieldNode(node, "action"), "unknown action %q (expected one of %s)",
+			rule.Action, strings.Join(actions, ", "))
+	}
+	var errs []error
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name := node.Content[i].Value
+		if name != "action" && name != "groups" && name != "caseInsensitive" && !contains(allowed, name) {
+			errs = append(errs, configError(node.Content[i], "field %q not allowed for action %q", name, rule.Action))
+		}
+	}
+	var grpChk GroupCheckFn
+	if rule.Groups != nil {
+		grpChk = Path(*rule.Groups)
+	}
+	required := func(field, value string) {
+		if value == "" {
+			errs = append(errs, configError(node, "missing %s for action %q", field, rule.Action))
+		}
+	}
+
+	var fn infra.AttrFn
+	switch rule.Action {
+	case ActionRename:
+		required("key", rule.Key)
+		required("to", rule.To)
+		fn = ChangeKey(rule.Key, rule.To, rule.CaseInsensitive, grpChk)
+	case ActionRemove:
+		required("key", rule.Key)
+		fn = RemoveKey(rule.Key, rule.CaseInsensitive, grpChk)
+	case ActionCase:
+		required("key", rule.Key)
+		chgCase, ok := changeCases[rule.Case]
+		if !ok {
+			errs = append(errs, configError(fieldNodeOr(node, "case"), "case must be upper or lower"))
+		}
+		fn = ChangeCase(rule.Key, chgCase, rule.CaseInsensitive, grpChk)
+	case ActionSet:
+		required("key", rule.Key)
+		if fieldNode(node, "value") == nil {
+			errs = append(errs, configError(node, "missing value for action %q", rule.Action))
+		}
+		fn = ChangeValue(rule.Key, SetValueTo(slog.AnyValue(rule.Value)), rule.CaseInsensitive, grpChk)
+	case ActionRedact:
+		var err error
+		if fn, err = buildRedact(node, &rule, grpChk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return fn, nil
+}
+
+// buildRedact validates a redact rule and returns the matching ReplaceAttr function.
+func buildRedact(node *yaml.Node, rule *configRule, grpChk GroupCheckFn) (infra.AttrFn, error) {
+	var targets int
+	for _, present := range []bool{rule.Key != "", len(rule.Keys) > 0, len(rule.Patterns) > 0} {
+		if present {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, configError(node, "redact requires exactly one of key, keys, or patterns")
+	}
+	if len(rule.Patterns) > 0 {
+		if rule.KeepLast != 0 || rule.HashSalt != "" {
+			return nil, configError(node, "keepLast and hashSalt are not supported with patterns")
+		}
+		patterns := make([]ValuePattern, 0, len(rule.Patterns))
+		list := fieldNode(node, "patterns")
+		for i, name := range rule.Patterns {
+			pattern, found := valuePatterns[name]
+			if !found {
+				return nil, configError(list.Content[i], "unknown pattern %q", name)
+			}
+			patterns = append(patterns, pattern)
+		}
+		return RedactValues(grpChk, patterns...), nil
+	}
+	chgFn := Mask()
+	switch {
+	case rule.KeepLast < 0:
+		return nil, configError(fieldNode(node, "keepLast"), "keepLast must not be negative")
+	case rule.KeepLast > 0 && rule.HashSalt != "":
+		return nil, configError(node, "keepLast and hashSalt can not both be used")
+	case rule.KeepLast > 0:
+		chgFn = KeepLast(rule.KeepLast, '*')
+	case rule.HashSalt != "":
+		chgFn = Hash([]byte(rule.HashSalt))
+	}
+	if rule.Key != "" {
+		return ChangeValue(rule.Key, chgFn, rule.CaseInsensitive, grpChk), nil
+	}
+	list := fieldNode(node, "keys")
+	for i, pattern := range rule.Keys {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, configError(list.Content[i], "invalid key pattern %q", pattern)
+		}
+	}
+	return ChangeKeysMatching(rule.Keys, chgFn, rule.CaseInsensitive, grpChk), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// fieldNode returns the value node for the named field in a mapping node or nil.
+func fieldNode(node *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// fieldNodeOr returns the value node for the named field or the mapping node if the field is missing.
+func fieldNodeOr(node *yaml.Node, name string) *yaml.Node {
+	if field := fieldNode(node, name); field != nil {
+		return field
+	}
+	return node
+}
+
+var yamlLinePrefix = regexp.MustCompile(`^line \d+: `)
+
+// typeErrorMessage removes redundant line prefixes from yaml.TypeError messages.
+func typeErrorMessage(err error) string {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		msgs := make([]string, len(typeErr.Errors))
+		for i, msg := range typeErr.Errors {
+			msgs[i] = yamlLinePrefix.ReplaceAllString(msg, "")
+		}
+		return strings.Join(msgs, "; ")
+	}
+	return err.Error()
+}
+
+func contains(list []string, item string) bool {
+	for _, element := range list {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package replace
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+//go:embed testdata/pipeline.yaml
+var pipelineYAML string
+
+//go:embed testdata/pipeline.json
+var pipelineJSON string
+
+//go:embed testdata/pipeline.golden
+var pipelineGolden string
+
+// handWritten is the ReplaceAttr pipeline equivalent to testdata/pipeline.yaml and testdata/pipeline.json.
+func handWritten() infra.AttrFn {
+	return Multiple(
+		RemoveKey(slog.TimeKey, false, TopCheck),
+		ChangeKey(slog.MessageKey, "message", false, TopCheck),
+		ChangeCase(slog.LevelKey, CaseLower, false, TopCheck),
+		ChangeValue("env", SetValueTo(slog.StringValue("production")), false, nil),
+		ChangeKeysMatching([]string{"*password*", "secret"}, Mask(), true, nil),
+		ChangeValue("card", KeepLast(4, '*'), false, Path("user")),
+		ChangeValue("email", Hash([]byte("salt")), false, nil),
+		RedactValues(Path("**"), BearerTokens),
+	)
+}
+
+// logPipeline logs a fixed set of records through a madkins/flash handler using the pipeline.
+func logPipeline(fn infra.AttrFn) string {
+	var buf bytes.Buffer
+	log := slog.New(flash.NewHandler(&buf, &slog.HandlerOptions{ReplaceAttr: fn}, nil))
+	log.Info(test.Message, "env", "test", "Password", "hunter2", "count", 3)
+	log.Warn(test.Message, "SECRET", "xyzzy", "header", "Bearer abc.def", "email", "joe@example.com")
+	log.WithGroup("user").Error(test.Message,
+		"card", "4111111111111111", "db_password", "hunter2", "env", "test", "auth", "Bearer abc.def")
+	log.WithGroup("other").Info(test.Message, "card", "4111111111111111", "Secret", "xyzzy")
+	return buf.String()
+}
+
+func TestLoadConfig_Golden(t *testing.T) {
+	expected := logPipeline(handWritten())
+	assert.Equal(t, pipelineGolden, expected)
+	for name, config := range map[string]string{"yaml": pipelineYAML, "json": pipelineJSON} {
+		fn, err := LoadConfig(strings.NewReader(config))
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, logPipeline(fn), name)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	fn, err := LoadConfigFile(filepath.Join("testdata", "pipeline.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, pipelineGolden, logPipeline(fn))
+	_, err = LoadConfigFile(filepath.Join("testdata", "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   string
+		messages []string
+	}{
+		"empty":       {"", []string{"empty config document"}},
+		"syntax":      {"rules: [", []string{"parse config"}},
+		"not mapping": {"- a\n- b\n", []string{"line 1, column 1: config must be a mapping"}},
+		"no rules":    {"other: 1\n", []string{"line 1, column 1: unknown field \"other\"", "missing rules list"}},
+		"rules type":  {"rules: 1\n", []string{"line 1, column 8: rules must be a list"}},
+		"unknown action": {"rules:\n  - action: zap\n    key: x\n", []string{
+			"line 2, column 13: unknown action \"zap\" (expected one of case, redact, remove, rename, set)"}},
+		"missing action": {"rules:\n  - key: x\n", []string{"line 2, column 5: missing action"}},
+		"bad field": {"rules:\n  - action: remove\n    key: x\n    to: y\n", []string{
+			"line 4, column 5: field \"to\" not allowed for action \"remove\""}},
+		"missing fields": {"rules:\n  - action: rename\n", []string{
+			"line 2, column 5: missing key for action \"rename\"",
+			"line 2, column 5: missing to for action \"rename\""}},
+		"bad case": {"rules:\n  - action: case\n    key: level\n    case: title\n", []string{
+			"line 4, column 11: case must be upper or lower"}},
+		"missing value": {"rules:\n  - action: set\n    key: x\n", []string{"missing value for action \"set\""}},
+		"bad type": {"rules:\n  - action: redact\n    key: x\n    keepLast: many\n", []string{
+			"line 2, column 5: decode rule: cannot unmarshal !!str `many` into int"}},
+		"redact targets": {"rules:\n  - action: redact\n    key: x\n    keys: [y]\n", []string{
+			"redact requires exactly one of key, keys, or patterns"}},
+		"redact pattern": {"rules:\n  - action: redact\n    patterns: [emails, phones]\n", []string{
+			"line 3, column 24: unknown pattern \"phones\""}},
+		"redact glob": {"rules:\n  - action: redact\n    keys: [\"[bad\"]\n", []string{
+			"line 3, column 12: invalid key pattern \"[bad\""}},
+		"redact both": {"rules:\n  - action: redact\n    key: x\n    keepLast: 4\n    hashSalt: y\n", []string{
+			"keepLast and hashSalt can not both be used"}},
+		"multiple rules": {"rules:\n  - action: remove\n  - action: case\n    key: x\n    case: upper\n  - action: set\n", []string{
+			"line 2, column 5: missing key for action \"remove\"",
+			"line 6, column 5: missing key for action \"set\""}},
+	} {
+		fn, err := LoadConfig(strings.NewReader(tc.config))
+		assert.Nil(t, fn, name)
+		require.Error(t, err, name)
+		for _, message := range tc.messages {
+			assert.Contains(t, err.Error(), message, name)
+		}
+	}
+}
+
+func TestConfigError(t *testing.T) {
+	_, err := LoadConfig(strings.NewReader("rules:\n  - action: remove\n"))
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, 2, configErr.Line)
+	assert.Equal(t, 5, configErr.Column)
+	assert.Equal(t, "missing key for action \"remove\"", configErr.Message)
+}
@@ -5,6 +5,7 @@
 //   - Change level attributes named "lvl" to be named slog.LevelKey.
 //   - Change message attributes named "message" to be named slog.MessageKey.
 //   - Remove the "time" basic attribute.
+//   - Redact secrets and personal information.
 //
 // An early intention was to "fix" non-conformant handlers by providing
 // ReplaceAttr functions that could make them conformant.
@@ -45,6 +46,34 @@
 // A function that determines if the current stack of group names is acceptable.
 // For example, replace.TopCheck returns true only if the stack is empty,
 // indicating the attribute is not inside a group.
+// Nested groups can be targeted precisely with path expressions via replace.Path,
+// for example "request.headers", "request.*", "db.**", or "!db.**".
+//
+// # Redaction
+//
+// Sensitive attribute values can be scrubbed before they leave the process:
+//
+//   - by key, using glob patterns (ChangeKeysMatching, RedactKeys) or regular expressions (ChangeKeysRegexp),
+//   - by value, using patterns for credit card numbers, bearer tokens, and email addresses (RedactValues).
+//
+// Key-based functions take a ChangeFn so values can be masked completely (Mask),
+// partially masked (KeepLast), or replaced with a salted hash (Hash).
+// All of these return infra.AttrFn values that can be combined with Multiple.
+//
+// # Errors
+//
+// Errors are normally logged as the single string returned by their Error method.
+// ExpandErrors (or ChangeValue with ErrorValue for specific keys) logs them as structured groups
+// containing the message, Go type, any stack trace, and any slog.LogValuer fields,
+// with the chain of wrapped errors (including errors.Join branches) as nested groups.
+//
+// # Configuration
+//
+// A pipeline of these functions can also be declared in a YAML or JSON document
+// and loaded via LoadConfig or LoadConfigFile.
+// Each rule in the document names an action (rename, remove, case, set, or redact),
+// the key(s) to match, and optional caseInsensitive and groups (path expression) settings.
+// Configuration errors are returned as ConfigError values with line and column numbers.
 //
 // [infra.AttrFn]: https://pkg.go.dev/github.com/madkins23/go-slog/infra#AttrFn
 // [infra.EmptyAttr]: https://pkg.go.dev/github.com/madkins23/go-slog/infra#EmptyAttr
@@ -0,0 +1,174 @@
+package replace
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strconv"
+
+	"github.com/madkins23/go-slog/infra"
+)
+
+// Keys for the fields of the structured error groups generated by ErrorValue.
+const (
+	ErrorMessageKey = "msg"
+	ErrorTypeKey    = "type"
+	ErrorCauseKey   = "cause"
+	ErrorCausesKey  = "causes"
+	ErrorStackKey   = "stack"
+	ErrorFieldsKey  = "fields"
+)
+
+// DefaultErrorDepth is the maximum number of errors in a chain
+// rendered by ErrorValue if a depth is not specified.
+const DefaultErrorDepth = 8
+
+// -----------------------------------------------------------------------------
+
+// ErrorValue returns a ChangeFn that converts an error value into a structured group:
+//
+//   - ErrorMessageKey holds the result of the Error method,
+//   - ErrorTypeKey holds the Go type of the error,
+//   - ErrorStackKey holds the stack trace as a list of strings if the error carries one,
+//   - ErrorFieldsKey holds the resolved value if the error is also a slog.LogValuer,
+//   - ErrorCauseKey holds the group for the error returned by an Unwrap() error method, or
+//   - ErrorCausesKey holds a group of groups (keyed "0", "1", ...) for the errors
+//     returned by an Unwrap() []error method (e.g. errors.Join).
+//
+// The error chain is rendered to the specified maximum depth
+// (DefaultErrorDepth if not greater than zero).
+// Values that are not errors are returned as is.
+//
+// Stack traces are recognized for errors with a Callers() []uintptr method (e.g. go-errors/errors)
+// or a StackTrace method returning a slice of uintptr-based frames (e.g. pkg/errors).
+//
+// Handlers resolve slog.LogValuer values before calling ReplaceAttr,
+// so a top-level error that is a slog.LogValuer will never be seen by this function.
+// Errors that are slog.LogValuer objects further down the chain are included.
+func ErrorValue(maxDepth int) ChangeFn {
+	if maxDepth < 1 {
+		maxDepth = DefaultErrorDepth
+	}
+	return func(value slog.Value) slog.Value {
+		if value.Kind() == slog.KindAny {
+			if err, ok := value.Any().(error); ok && !nilError(err) {
+				return errorGroup(err, maxDepth)
+			}
+		}
+		return value
+	}
+}
+
+// ExpandErrors changes the value of any attribute that holds an error
+// into a structured group via ErrorValue, regardless of the attribute key.
+// For example:
+//
+//	options := &slog.HandlerOptions{
+//		ReplaceAttr: replace.ExpandErrors(0, nil)
+//	}
+//
+// returns an infra.AttrFn that will log errors in any group as structured groups.
+// Use ChangeValue with ErrorValue to limit this to specific keys.
+func ExpandErrors(maxDepth int, grpChk GroupCheckFn) infra.AttrFn {
+	chgFn := ErrorValue(maxDepth)
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindAny && (grpChk == nil || grpChk(groups)) {
+			a.Value = chgFn(a.Value)
+		}
+		return a
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// errorGroup returns the structured group for the specified error,
+// including its chain of wrapped errors up to the specified depth.
+func errorGroup(err error, depth int) slog.Value {
+	attrs := make([]slog.Attr, 0, 6)
+	attrs = append(attrs,
+		slog.String(ErrorMessageKey, err.Error()),
+		slog.String(ErrorTypeKey, fmt.Sprintf("%T", err)))
+	if stack := errorStack(err); len(stack) > 0 {
+		attrs = append(attrs, slog.Any(ErrorStackKey, stack))
+	}
+	if valuer, ok := err.(slog.LogValuer); ok {
+		// Resolve recovers from any panic in the LogValue method.
+		attrs = append(attrs, slog.Attr{Key: ErrorFieldsKey, Value: slog.AnyValue(valuer).Resolve()})
+	}
+	if depth > 1 {
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			causes := make([]slog.Attr, 0, 4)
+			for i, cause := range wrapper.Unwrap() {
+				if !nilError(cause) {
+					causes = append(causes, slog.Attr{Key: strconv.Itoa(i), Value: errorGroup(cause, depth-1)})
+				}
+			}
+			if len(causes) > 0 {
+				attrs = append(attrs, slog.Attr{Key: ErrorCausesKey, Value: slog.GroupValue(causes...)})
+			}
+		case interface{ Unwrap() error }:
+			if cause := wrapper.Unwrap(); !nilError(cause) {
+				attrs = append(attrs, slog.Attr{Key: ErrorCauseKey, Value: errorGroup(cause, depth-1)})
+			}
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// errorStack returns the stack trace carried by the error, if any,
+// as a list of "function file:line" strings.
+func errorStack(err error) []string {
+	var pcs []uintptr
+	if callers, ok := err.(interface{ Callers() []uintptr }); ok {
+		pcs = callers.Callers()
+	} else {
+		pcs = stackTrace(err)
+	}
+	if len(pcs) < 1 {
+		return nil
+	}
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// stackTrace returns the program counters from an error with a StackTrace method
+// that returns a slice of uintptr-based frames (e.g. github.com/pkg/errors).
+// Reflection is used to avoid a dependency on any specific error package.
+func stackTrace(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+	if fnType := method.Type(); fnType.NumIn() != 0 || fnType.NumOut() != 1 ||
+		fnType.Out(0).Kind() != reflect.Slice || fnType.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return pcs
+}
+
+// nilError returns true if the error is nil or a nil pointer,
+// either of which would likely panic when its Error method is called.
+func nilError(err error) bool {
+	if err == nil {
+		return true
+	}
+	value := reflect.ValueOf(err)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
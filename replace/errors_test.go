@@ -0,0 +1,130 @@
+package replace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/json"
+)
+
+// callersError carries a stack trace like github.com/go-errors/errors.
+type callersError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newCallersError(msg string) *callersError {
+	pcs := make([]uintptr, 8)
+	return &callersError{msg: msg, pcs: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (ce *callersError) Error() string      { return ce.msg }
+func (ce *callersError) Callers() []uintptr { return ce.pcs }
+
+// frame and stackError mimic the stack trace types of github.com/pkg/errors.
+type frame uintptr
+
+type stackError struct {
+	cause error
+	pcs   []frame
+}
+
+func newStackError(cause error) *stackError {
+	pcs := make([]uintptr, 8)
+	se := &stackError{cause: cause}
+	for _, pc := range pcs[:runtime.Callers(1, pcs)] {
+		se.pcs = append(se.pcs, frame(pc))
+	}
+	return se
+}
+
+func (se *stackError) Error() string       { return "stack: " + se.cause.Error() }
+func (se *stackError) Unwrap() error       { return se.cause }
+func (se *stackError) StackTrace() []frame { return se.pcs }
+
+// valuerError is an error that is also a slog.LogValuer.
+type valuerError struct{}
+
+func (ve valuerError) Error() string { return "valuer" }
+func (ve valuerError) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("code", 404), slog.String("path", "/missing"))
+}
+
+func TestErrorValue(t *testing.T) {
+	base := errors.New("base")
+	wrapped := fmt.Errorf("wrapped: %w", base)
+	chgFn := ErrorValue(0)
+	assert.Equal(t, slog.StringValue("string"), chgFn(slog.StringValue("string")))
+	assert.Equal(t, slog.IntValue(13), chgFn(slog.IntValue(13)))
+	var nilErr *callersError
+	assert.Equal(t, slog.AnyValue(nilErr), chgFn(slog.AnyValue(nilErr)))
+	assert.True(t, slog.GroupValue(
+		slog.String(ErrorMessageKey, "wrapped: base"),
+		slog.String(ErrorTypeKey, "*fmt.wrapError"),
+		slog.Any(ErrorCauseKey, slog.GroupValue(
+			slog.String(ErrorMessageKey, "base"),
+			slog.String(ErrorTypeKey, "*errors.errorString"))),
+	).Equal(chgFn(slog.AnyValue(wrapped))))
+	// Depth limits the chain.
+	assert.True(t, slog.GroupValue(
+		slog.String(ErrorMessageKey, "wrapped: base"),
+		slog.String(ErrorTypeKey, "*fmt.wrapError"),
+	).Equal(ErrorValue(1)(slog.AnyValue(wrapped))))
+}
+
+func TestExpandErrors(t *testing.T) {
+	joined := errors.Join(
+		newCallersError("callers"),
+		newStackError(valuerError{}),
+		nil)
+	var buf bytes.Buffer
+	logger := slog.New(flash.NewHandler(&buf, &slog.HandlerOptions{ReplaceAttr: ExpandErrors(0, nil)}, nil))
+	logger.Info("message", "err", fmt.Errorf("top: %w", joined), "text", "not an error")
+	logMap, err := json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "not an error", logMap["text"])
+	top, ok := logMap["err"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "top: callers\nstack: valuer", top[ErrorMessageKey])
+	assert.Equal(t, "*fmt.wrapError", top[ErrorTypeKey])
+	cause, ok := top[ErrorCauseKey].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "*errors.joinError", cause[ErrorTypeKey])
+	causes, ok := cause[ErrorCausesKey].(map[string]any)
+	require.True(t, ok)
+	require.Len(t, causes, 2)
+
+	callers, ok := causes["0"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "callers", callers[ErrorMessageKey])
+	checkStack(t, callers[ErrorStackKey])
+
+	stack, ok := causes["1"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "*replace.stackError", stack[ErrorTypeKey])
+	checkStack(t, stack[ErrorStackKey])
+	valuer, ok := stack[ErrorCauseKey].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "replace.valuerError", valuer[ErrorTypeKey])
+	assert.Equal(t, map[string]any{"code": float64(404), "path": "/missing"}, valuer[ErrorFieldsKey])
+	assert.NotContains(t, valuer, ErrorStackKey)
+}
+
+func checkStack(t *testing.T, value any) {
+	stack, ok := value.([]any)
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	first, ok := stack[0].(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(first, "github.com/madkins23/go-slog/replace.new"), first)
+	assert.Contains(t, first, "errors_test.go:")
+}
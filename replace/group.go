@@ -1,5 +1,7 @@
 package replace
 
+import "strings"
+
 // -----------------------------------------------------------------------------
 
 // GroupCheckFn returns true if the specified stack of groups (most likely the end value)
@@ -31,3 +33,90 @@ func Current(name string) GroupCheckFn {
 		return len(groups) > 0 && groups[len(groups)-1] == name
 	}
 }
+
+// -----------------------------------------------------------------------------
+
+const (
+	pathNegate    = "!"
+	pathSeparator = "."
+	pathAny       = "*"
+	pathAnyDepth  = "**"
+)
+
+// Path returns a GroupCheckFn that will return true if the groups stack matches
+// the specified path expression.
+// Path expressions are group names separated by dots, matched against the entire stack
+// from the outermost group (lowest indexed item in the array) inwards:
+//
+//   - "request.headers" matches only the groups "request" and then "headers",
+//   - "*" matches any single group name, so "request.*" matches "request.headers" but not "request",
+//   - "**" matches zero or more group names, so "db.**" matches "db" and any depth of groups under it,
+//   - "**.headers" matches any groups stack ending with "headers", and
+//   - a leading "!" negates the rest of the expression, so "!db.**" matches anything not in "db".
+//
+// The empty expression matches only the empty stack, the same as TopCheck.
+// Use the result of executing this function with a path expression.
+func Path(expr string) GroupCheckFn {
+	negate := strings.HasPrefix(expr, pathNegate)
+	if negate {
+		expr = strings.TrimPrefix(expr, pathNegate)
+	}
+	var segments []string
+	if expr != "" {
+		segments = strings.Split(expr, pathSeparator)
+	}
+	return func(groups []string) bool {
+		return matchPath(segments, groups) != negate
+	}
+}
+
+// Prefix returns a GroupCheckFn that will return true if the groups stack
+// begins with the groups in the specified path expression.
+// This is the same as Path(expr + ".**").
+// Path wildcards may also be used in the prefix expression.
+func Prefix(expr string) GroupCheckFn {
+	if expr == "" || expr == pathNegate {
+		return Path(expr + pathAnyDepth)
+	}
+	return Path(expr + pathSeparator + pathAnyDepth)
+}
+
+// Not returns a GroupCheckFn that returns the opposite of the specified GroupCheckFn.
+func Not(grpChk GroupCheckFn) GroupCheckFn {
+	return func(groups []string) bool {
+		return !grpChk(groups)
+	}
+}
+
+// matchPath returns true if the groups stack matches the path segments.
+func matchPath(segments, groups []string) bool {
+	for len(segments) > 0 {
+		switch segments[0] {
+		case pathAnyDepth:
+			// Collapse adjacent multi-segment wildcards.
+			for len(segments) > 0 && segments[0] == pathAnyDepth {
+				segments = segments[1:]
+			}
+			if len(segments) == 0 {
+				return true
+			}
+			for i := 0; i <= len(groups); i++ {
+				if matchPath(segments, groups[i:]) {
+					return true
+				}
+			}
+			return false
+		case pathAny:
+			if len(groups) == 0 {
+				return false
+			}
+		default:
+			if len(groups) == 0 || groups[0] != segments[0] {
+				return false
+			}
+		}
+		segments = segments[1:]
+		groups = groups[1:]
+	}
+	return len(groups) == 0
+}
@@ -0,0 +1,93 @@
+package replace
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/json"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+func TestPath(t *testing.T) {
+	var (
+		top     []string
+		db      = []string{"db"}
+		dbConn  = []string{"db", "conn"}
+		dbDeep  = []string{"db", "conn", "pool"}
+		headers = []string{"request", "headers"}
+		request = []string{"request"}
+		nested  = []string{"outer", "request", "headers"}
+	)
+	for expr, expected := range map[string]map[*[]string]bool{
+		"":                   {&top: true, &db: false, &headers: false},
+		"request.headers":    {&top: false, &request: false, &headers: true, &nested: false},
+		"request.*":          {&request: false, &headers: true, &nested: false},
+		"*":                  {&top: false, &db: true, &request: true, &dbConn: false},
+		"*.headers":          {&headers: true, &nested: false},
+		"db.**":              {&top: false, &db: true, &dbConn: true, &dbDeep: true, &request: false},
+		"**.headers":         {&headers: true, &nested: true, &request: false},
+		"**":                 {&top: true, &db: true, &dbDeep: true},
+		"**.**.request.**":   {&request: true, &nested: true, &db: false},
+		"db.**.pool":         {&dbDeep: true, &dbConn: false},
+		"!db.**":             {&top: true, &db: false, &dbDeep: false, &request: true},
+		"!":                  {&top: false, &db: true},
+		"!request.headers":   {&headers: false, &nested: true},
+		"outer.*.headers":    {&nested: true, &headers: false},
+		"db.conn.pool.extra": {&dbDeep: false},
+	} {
+		fn := Path(expr)
+		for groups, result := range expected {
+			assert.Equal(t, result, fn(*groups), "%q %v", expr, *groups)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	assert.True(t, Prefix("db")([]string{"db"}))
+	assert.True(t, Prefix("db")([]string{"db", "conn"}))
+	assert.False(t, Prefix("db")([]string{"request"}))
+	assert.True(t, Prefix("")(nil))
+	assert.True(t, Prefix("")([]string{"any"}))
+	assert.True(t, Prefix("*.headers")([]string{"request", "headers", "x"}))
+	assert.True(t, Prefix("!db")([]string{"request"}))
+	assert.False(t, Prefix("!db")([]string{"db", "conn"}))
+}
+
+func TestNot(t *testing.T) {
+	assert.False(t, Not(TopCheck)(nil))
+	assert.True(t, Not(TopCheck)([]string{"group"}))
+}
+
+// TestPathReplace tests Path with existing ReplaceAttr functions on a madkins/flash handler.
+func TestPathReplace(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(flash.NewHandler(&buf,
+		&slog.HandlerOptions{
+			ReplaceAttr: Multiple(
+				RemoveKey(slog.TimeKey, false, TopCheck),
+				RemoveKey("password", false, Prefix("db")),
+				ChangeValue("token", Mask(), false, Path("request.*")),
+			),
+		}, nil))
+	log.Info(test.Message, "password", "top", "token", "top")
+	log.WithGroup("db").WithGroup("conn").Info(test.Message, "password", "hidden", "user", "me")
+	log.WithGroup("request").WithGroup("headers").Info(test.Message, "token", "hidden")
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte{'\n'})
+	require.Len(t, lines, 3)
+	for i, expected := range []map[string]any{
+		{"password": "top", "token": "top"},
+		{"db": map[string]any{"conn": map[string]any{"user": "me"}}},
+		{"request": map[string]any{"headers": map[string]any{"token": DefaultMask}}},
+	} {
+		logMap, err := json.Parse(lines[i])
+		require.NoError(t, err)
+		delete(logMap, slog.LevelKey)
+		delete(logMap, slog.MessageKey)
+		assert.Equal(t, expected, logMap, "line %d", i)
+	}
+}
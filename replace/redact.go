@@ -0,0 +1,224 @@
+package replace
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/madkins23/go-slog/infra"
+)
+
+// DefaultMask is the replacement for redacted values.
+const DefaultMask = "[REDACTED]"
+
+// -----------------------------------------------------------------------------
+// Change functions for redaction.
+
+// Mask returns a ChangeFn that replaces any value with DefaultMask.
+func Mask() ChangeFn {
+	return SetValueTo(slog.StringValue(DefaultMask))
+}
+
+// KeepLast returns a ChangeFn that replaces all but the last keep characters
+// of the string form of a value with the specified mask character.
+// Values no longer than keep characters are masked completely.
+// For example, KeepLast(4, '*') converts "4111111111111111" to "************1111".
+func KeepLast(keep int, maskChar rune) ChangeFn {
+	return func(value slog.Value) slog.Value {
+		runes := []rune(value.String())
+		if len(runes) <= keep {
+			return slog.StringValue(strings.Repeat(string(maskChar), len(runes)))
+		}
+		for i := 0; i < len(runes)-keep; i++ {
+			runes[i] = maskChar
+		}
+		return slog.StringValue(string(runes))
+	}
+}
+
+// Hash returns a ChangeFn that replaces the string form of a value with
+// a salted hash (HMAC-SHA256 with the salt as the key) shown as "sha256:" and 16 hex digits.
+// The same value and salt always generate the same result,
+// so records can still be correlated without showing the value.
+func Hash(salt []byte) ChangeFn {
+	return func(value slog.Value) slog.Value {
+		mac := hmac.New(sha256.New, salt)
+		mac.Write([]byte(value.String()))
+		return slog.StringValue("sha256:" + hex.EncodeToString(mac.Sum(nil)[:8]))
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Key pattern functions.
+
+// ChangeKeysMatching changes the value of any attribute with a key matching
+// any of the specified glob patterns (see path.Match).
+// The new value is generated by executing the specified ChangeFn.
+// For example:
+//
+//	options := &slog.HandlerOptions{
+//		ReplaceAttr: replace.ChangeKeysMatching(
+//			[]string{"*password*", "*secret*", "api?key"}, replace.Mask(), true, nil)
+//	}
+//
+// returns an infra.AttrFn that will mask the values of attributes with keys like
+// "password", "DB_Password", or "apiKey" in any group.
+// Invalid patterns are logged as errors and ignored.
+func ChangeKeysMatching(patterns []string, chgFn ChangeFn, caseInsensitive bool, grpChk GroupCheckFn) infra.AttrFn {
+	valid := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if caseInsensitive {
+			pattern = strings.ToLower(pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			slog.Error("invalid key pattern", "pattern", pattern, "err", err)
+			continue
+		}
+		valid = append(valid, pattern)
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		key := a.Key
+		if caseInsensitive {
+			key = strings.ToLower(key)
+		}
+		for _, pattern := range valid {
+			if matched, _ := path.Match(pattern, key); matched {
+				if grpChk == nil || grpChk(groups) {
+					a.Value = chgFn(a.Value)
+				}
+				break
+			}
+		}
+		return a
+	}
+}
+
+// ChangeKeysRegexp changes the value of any attribute with a key matching the regular expression.
+// The new value is generated by executing the specified ChangeFn.
+// Use the (?i) flag in the expression for case-insensitive matching.
+func ChangeKeysRegexp(re *regexp.Regexp, chgFn ChangeFn, grpChk GroupCheckFn) infra.AttrFn {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if re.MatchString(a.Key) && (grpChk == nil || grpChk(groups)) {
+			a.Value = chgFn(a.Value)
+		}
+		return a
+	}
+}
+
+// RedactKeys replaces the value of any attribute with a key matching any of
+// the specified glob patterns with DefaultMask.
+// This is shorthand for ChangeKeysMatching with Mask().
+func RedactKeys(patterns []string, caseInsensitive bool, grpChk GroupCheckFn) infra.AttrFn {
+	return ChangeKeysMatching(patterns, Mask(), caseInsensitive, grpChk)
+}
+
+// -----------------------------------------------------------------------------
+// Value pattern functions.
+
+// ValuePattern matches sensitive text within string values.
+type ValuePattern struct {
+	// Regexp matches the sensitive text.
+	Regexp *regexp.Regexp
+
+	// Check optionally validates each match (e.g. a checksum) to avoid false positives.
+	Check func(match string) bool
+}
+
+var (
+	// CreditCards matches credit card numbers with optional space or dash separators
+	// which pass the Luhn checksum.
+	CreditCards = ValuePattern{
+		Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Check:  luhn,
+	}
+
+	// BearerTokens matches HTTP bearer authorization tokens including the "Bearer" prefix.
+	BearerTokens = ValuePattern{
+		Regexp: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	}
+
+	// Emails matches email addresses.
+	Emails = ValuePattern{
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+)
+
+// RedactValues replaces text matching any of the specified patterns within
+// attribute values with DefaultMask, regardless of the attribute key.
+// String values are checked as well as the string forms of error and fmt.Stringer values.
+// Values of other kinds are not changed. For example:
+//
+//	options := &slog.HandlerOptions{
+//		ReplaceAttr: replace.RedactValues(nil,
+//			replace.CreditCards, replace.BearerTokens, replace.Emails)
+//	}
+//
+// changes "paid with 4111 1111 1111 1111" to "paid with [REDACTED]".
+func RedactValues(grpChk GroupCheckFn, patterns ...ValuePattern) infra.AttrFn {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		var str string
+		switch a.Value.Kind() {
+		case slog.KindString:
+			str = a.Value.String()
+		case slog.KindAny:
+			switch v := a.Value.Any().(type) {
+			case error:
+				str = v.Error()
+			case fmt.Stringer:
+				str = v.String()
+			default:
+				return a
+			}
+		default:
+			return a
+		}
+		if grpChk != nil && !grpChk(groups) {
+			return a
+		}
+		if redacted, changed := redactString(str, patterns); changed {
+			a.Value = slog.StringValue(redacted)
+		}
+		return a
+	}
+}
+
+// redactString replaces all pattern matches in the string with DefaultMask.
+func redactString(str string, patterns []ValuePattern) (string, bool) {
+	var changed bool
+	for _, pattern := range patterns {
+		str = pattern.Regexp.ReplaceAllStringFunc(str, func(match string) string {
+			if pattern.Check != nil && !pattern.Check(match) {
+				return match
+			}
+			changed = true
+			return DefaultMask
+		})
+	}
+	return str, changed
+}
+
+// luhn returns true if the digits in the string pass the Luhn checksum.
+// Non-digit characters are ignored.
+func luhn(number string) bool {
+	var sum, count int
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if count%2 == 1 {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		count++
+	}
+	return count > 0 && sum%10 == 0
+}
@@ -0,0 +1,116 @@
+package replace
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/internal/json"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+func TestKeepLast(t *testing.T) {
+	fn := KeepLast(4, '*')
+	assert.Equal(t, "************1111", fn(slog.StringValue("4111111111111111")).String())
+	assert.Equal(t, "**", fn(slog.StringValue("ab")).String())
+	assert.Equal(t, "*****6789", fn(slog.Int64Value(123456789)).String())
+	assert.Equal(t, "##éèê", KeepLast(3, '#')(slog.StringValue("abéèê")).String())
+}
+
+func TestHash(t *testing.T) {
+	fn := Hash([]byte("salt"))
+	first := fn(slog.StringValue("user@example.com")).String()
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, first)
+	assert.Equal(t, first, fn(slog.StringValue("user@example.com")).String())
+	assert.NotEqual(t, first, fn(slog.StringValue("other@example.com")).String())
+	assert.NotEqual(t, first, Hash([]byte("pepper"))(slog.StringValue("user@example.com")).String())
+}
+
+func TestChangeKeysMatching(t *testing.T) {
+	fn := RedactKeys([]string{"*password*", "api?key", "[bad"}, true, nil)
+	for _, key := range []string{"password", "DB_Password", "api-key", "API_KEY"} {
+		assert.Equal(t, DefaultMask, fn([]string{"group"}, slog.String(key, "secret")).Value.String(), key)
+	}
+	for _, key := range []string{"user", "apikeys", "pass"} {
+		assert.Equal(t, "secret", fn(nil, slog.String(key, "secret")).Value.String(), key)
+	}
+	exact := RedactKeys([]string{"token"}, false, TopCheck)
+	assert.Equal(t, DefaultMask, exact(nil, slog.String("token", "x")).Value.String())
+	assert.Equal(t, "x", exact(nil, slog.String("Token", "x")).Value.String())
+	assert.Equal(t, "x", exact([]string{"group"}, slog.String("token", "x")).Value.String())
+}
+
+func TestChangeKeysRegexp(t *testing.T) {
+	fn := ChangeKeysRegexp(regexp.MustCompile(`(?i)^(ssn|card)$`), KeepLast(4, '*'), Current("user"))
+	assert.Equal(t, "*****6789", fn([]string{"user"}, slog.String("SSN", "123456789")).Value.String())
+	assert.Equal(t, "123456789", fn([]string{"other"}, slog.String("ssn", "123456789")).Value.String())
+	assert.Equal(t, "123456789", fn([]string{"user"}, slog.String("ssns", "123456789")).Value.String())
+}
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestRedactValues(t *testing.T) {
+	fn := RedactValues(nil, CreditCards, BearerTokens, Emails)
+	for value, expected := range map[string]string{
+		"paid with 4111 1111 1111 1111 today":  "paid with " + DefaultMask + " today",
+		"card 4111-1111-1111-1111":             "card " + DefaultMask,
+		"order 4111111111111112 is not a card": "order 4111111111111112 is not a card",
+		"Authorization: Bearer abc.DEF-123_x=": "Authorization: " + DefaultMask,
+		"mail joe.bloggs+tag@mail.example.org": "mail " + DefaultMask,
+		"nothing to see here":                  "nothing to see here",
+	} {
+		assert.Equal(t, expected, fn(nil, slog.String("text", value)).Value.String(), value)
+	}
+	assert.Equal(t, "failed for "+DefaultMask,
+		fn(nil, slog.Any("err", errors.New("failed for joe@example.com"))).Value.String())
+	assert.Equal(t, DefaultMask, fn(nil, slog.Any("who", stringer("joe@example.com"))).Value.String())
+	assert.Equal(t, int64(4111111111111111), fn(nil, slog.Int64("number", 4111111111111111)).Value.Int64())
+	// Unchanged values keep their kind.
+	assert.Equal(t, slog.KindAny, fn(nil, slog.Any("err", errors.New("fine"))).Value.Kind())
+	top := RedactValues(TopCheck, Emails)
+	assert.Equal(t, "joe@example.com", top([]string{"group"}, slog.String("email", "joe@example.com")).Value.String())
+}
+
+func TestLuhn(t *testing.T) {
+	assert.True(t, luhn("4111 1111 1111 1111"))
+	assert.True(t, luhn("5500-0000-0000-0004"))
+	assert.False(t, luhn("4111111111111112"))
+	assert.False(t, luhn("----"))
+}
+
+// TestRedaction tests redaction functions composed with Multiple on a madkins/flash handler.
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(flash.NewHandler(&buf,
+		&slog.HandlerOptions{
+			ReplaceAttr: Multiple(
+				RemoveKey(slog.TimeKey, false, TopCheck),
+				RedactKeys([]string{"*password*"}, true, nil),
+				ChangeKeysMatching([]string{"card"}, KeepLast(4, '*'), false, Current("user")),
+				ChangeKeysMatching([]string{"email"}, Hash([]byte("salt")), false, nil),
+				RedactValues(nil, BearerTokens),
+			),
+		}, nil))
+	log.WithGroup("user").Info(test.Message,
+		"Password", "hunter2",
+		"email", "joe@example.com",
+		"card", "4111111111111111",
+		"header", "Bearer abcdef")
+	logMap, err := json.Parse(buf.Bytes())
+	require.NoError(t, err)
+	assert.NotContains(t, logMap, slog.TimeKey)
+	user, ok := logMap["user"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, DefaultMask, user["Password"])
+	assert.Regexp(t, `^sha256:`, user["email"])
+	assert.Equal(t, "************1111", user["card"])
+	assert.Equal(t, DefaultMask, user["header"])
+}
@@ -0,0 +1,4 @@
+// Package testdata provides test data via text files.
+//
+// There should not be any .go files in this directory.
+package testdata
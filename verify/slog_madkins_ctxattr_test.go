@@ -0,0 +1,18 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsctxattr"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsCtxAttr runs tests for the madkins/ctxattr handler wrapping slog/JSONHandler.
+func TestVerifyMadkinsCtxAttr(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsctxattr.Creator())
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
@@ -0,0 +1,18 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsfanout"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsFanout runs tests for the madkins/fanout handler.
+func TestVerifyMadkinsFanout(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsfanout.Creator())
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
@@ -0,0 +1,26 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsflashbinary"
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsFlashBinaryCBOR runs tests for the madkins/flash handler with CBOR output.
+func TestVerifyMadkinsFlashBinaryCBOR(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashbinary.Creator(flash.FormatCBOR))
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
+
+// TestVerifyMadkinsFlashBinaryMsgPack runs tests for the madkins/flash handler with MessagePack output.
+func TestVerifyMadkinsFlashBinaryMsgPack(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashbinary.Creator(flash.FormatMsgPack))
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
@@ -0,0 +1,39 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsflashdedup"
+	"github.com/madkins23/go-slog/handlers/flash"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsFlashDedupAppend runs tests for the madkins/flash handler in DedupAppend mode.
+func TestVerifyMadkinsFlashDedupAppend(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashdedup.Creator(flash.DedupAppend))
+	slogSuite.WarnOnly(warning.SkipDedup)
+	suite.Run(t, slogSuite)
+}
+
+// TestVerifyMadkinsFlashDedupIgnore runs tests for the madkins/flash handler in DedupIgnore mode.
+func TestVerifyMadkinsFlashDedupIgnore(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashdedup.Creator(flash.DedupIgnore))
+	slogSuite.WarnOnly(warning.SkipDedup)
+	suite.Run(t, slogSuite)
+}
+
+// TestVerifyMadkinsFlashDedupIncrement runs tests for the madkins/flash handler in DedupIncrement mode.
+func TestVerifyMadkinsFlashDedupIncrement(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashdedup.Creator(flash.DedupIncrement))
+	slogSuite.WarnOnly(warning.SkipDedup)
+	suite.Run(t, slogSuite)
+}
+
+// TestVerifyMadkinsFlashDedupOverwrite runs tests for the madkins/flash handler in DedupOverwrite mode.
+func TestVerifyMadkinsFlashDedupOverwrite(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashdedup.Creator(flash.DedupOverwrite))
+	suite.Run(t, slogSuite)
+}
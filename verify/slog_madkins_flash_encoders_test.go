@@ -0,0 +1,18 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsflashencoders"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsFlashEncoders runs tests for the madkins/flash handler with typed encoders.
+func TestVerifyMadkinsFlashEncoders(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashencoders.Creator())
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
@@ -0,0 +1,18 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsflashtext"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsFlashText runs tests for the madkins/flash handler with text output.
+func TestVerifyMadkinsFlashText(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsflashtext.Creator())
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
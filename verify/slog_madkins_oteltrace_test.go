@@ -0,0 +1,18 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-slog/creator/madkinsoteltrace"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/verify/tests"
+)
+
+// TestVerifyMadkinsOTelTrace runs tests for the madkins/oteltrace handler wrapping madkins/flash.
+func TestVerifyMadkinsOTelTrace(t *testing.T) {
+	slogSuite := tests.NewSlogTestSuite(madkinsoteltrace.Creator())
+	slogSuite.WarnOnly(warning.Duplicates)
+	suite.Run(t, slogSuite)
+}
@@ -24,6 +24,7 @@ func TestVerifyPhsymZerolog(t *testing.T) {
 	slogSuite.WarnOnly(warning.SlogTest)
 	slogSuite.WarnOnly(warning.SourceCaller)
 	slogSuite.WarnOnly(warning.TimeSeconds)
+	slogSuite.WarnOnly(warning.Ungraceful)
 	slogSuite.WarnOnly(warning.WithGroupEmpty)
 	slogSuite.WarnOnly(warning.ZeroTime)
 	suite.Run(t, slogSuite)
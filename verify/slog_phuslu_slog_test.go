@@ -14,6 +14,7 @@ import (
 func TestVerifyPhusluSlog(t *testing.T) {
 	slogSuite := tests.NewSlogTestSuite(phusluslog.Creator())
 	slogSuite.WarnOnly(warning.Duplicates)
+	slogSuite.WarnOnly(warning.Ungraceful)
 
 	// For use when back testing with v1.93.0:
 	//   go get github.com/phuslu/log@v1.0.93
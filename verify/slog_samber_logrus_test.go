@@ -20,6 +20,7 @@ func TestVerifySamberLogrus(t *testing.T) {
 	slogSuite.WarnOnly(warning.NoReplAttrBasic)
 	slogSuite.WarnOnly(warning.Resolver)
 	slogSuite.WarnOnly(warning.SlogTest)
+	slogSuite.WarnOnly(warning.Ungraceful)
 	slogSuite.WarnOnly(warning.ZeroPC)
 	slogSuite.WarnOnly(warning.ZeroTime)
 	suite.Run(t, slogSuite)
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/madkins23/go-slog/infra"
+	"github.com/madkins23/go-slog/infra/warning"
+	"github.com/madkins23/go-slog/internal/test"
+)
+
+// -----------------------------------------------------------------------------
+// Tests of handler behavior with pathological input.
+//
+// The input for these tests is large but finite.
+// Truly unbounded input (e.g. a self-referential group) would crash
+// most handlers outright, taking the entire test run with it.
+// Handlers that bound such input by default (e.g. flash and sloggy)
+// test it in their own unit tests.
+
+const (
+	gracefulDepth  = 500
+	gracefulAttrs  = 10_000
+	gracefulLength = 1 << 20
+	gracefulRecord = 256
+)
+
+// TestGracefulDeepGroup tests logging of deeply nested groups.
+func (suite *SlogTestSuite) TestGracefulDeepGroup() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		attr := slog.String("bottom", "value")
+		for depth := 0; depth < gracefulDepth; depth++ {
+			attr = slog.Group("group"+strconv.Itoa(depth), attr)
+		}
+		logger.Info(message, attr)
+	})
+}
+
+// TestGracefulDeepValuer tests logging of deeply recursive slog.LogValuer objects.
+func (suite *SlogTestSuite) TestGracefulDeepValuer() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		logger.Info(message, "valuer", &deepValuer{depth: gracefulDepth})
+	})
+}
+
+// TestGracefulLongString tests logging of very long message and attribute strings.
+func (suite *SlogTestSuite) TestGracefulLongString() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		long := strings.Repeat("x", gracefulLength)
+		logger.Info(long, "long", long)
+	})
+}
+
+// TestGracefulManyAttrs tests logging of a very large number of attributes.
+func (suite *SlogTestSuite) TestGracefulManyAttrs() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		attrs := make([]any, gracefulAttrs)
+		for i := range attrs {
+			attrs[i] = slog.Int("attr"+strconv.Itoa(i), i)
+		}
+		logger.Info(message, attrs...)
+	})
+}
+
+// TestGracefulPanicStringer tests logging of a fmt.Stringer that panics.
+func (suite *SlogTestSuite) TestGracefulPanicStringer() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		logger.Info(message, "stringer", test.PanicStringer{})
+	})
+}
+
+// TestGracefulPanicValuer tests logging of a slog.LogValuer that panics.
+func (suite *SlogTestSuite) TestGracefulPanicValuer() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		logger.Info(message, "valuer", &panicValuer{})
+	})
+}
+
+// TestGracefulSelfReference tests logging of a self-referential value.
+func (suite *SlogTestSuite) TestGracefulSelfReference() {
+	suite.checkGraceful(func(logger *slog.Logger) {
+		logger.Info(message, "cycle", test.NewCycle("loop"))
+	})
+}
+
+// -----------------------------------------------------------------------------
+
+// checkGraceful logs pathological input via the specified function and
+// verifies that the handler neither panics nor emits an invalid log record.
+func (suite *SlogTestSuite) checkGraceful(logFn func(logger *slog.Logger)) {
+	problem := suite.graceful(logFn)
+	if !suite.HasWarning(warning.Ungraceful) {
+		suite.Assert().Empty(problem)
+	} else if problem == "" {
+		suite.AddUnused(warning.Ungraceful, suite.record())
+	} else {
+		suite.AddWarning(warning.Ungraceful, problem, suite.record())
+	}
+}
+
+// graceful executes the specified logging function and returns a description of
+// any problem (a panic or an invalid log record) or the empty string.
+func (suite *SlogTestSuite) graceful(logFn func(logger *slog.Logger)) (problem string) {
+	defer func() {
+		if r := recover(); r != nil {
+			problem = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	logFn(suite.Logger(infra.SimpleOptions()))
+	if suite.Buffer.Len() < 1 {
+		return "no log record"
+	}
+	var logMap map[string]any
+	if err := json.Unmarshal(suite.Buffer.Bytes(), &logMap); err != nil {
+		return "invalid log record: " + err.Error()
+	}
+	return ""
+}
+
+// record returns the log record in the output capture buffer,
+// shortened if necessary to keep warning data readable.
+func (suite *SlogTestSuite) record() string {
+	record := suite.Buffer.String()
+	if len(record) > gracefulRecord {
+		record = record[:gracefulRecord] + "..."
+	}
+	return record
+}
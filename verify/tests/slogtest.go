@@ -23,7 +23,7 @@ func (suite *SlogTestSuite) TestSlogTest() {
 	if suite.Creator.CanMakeHandler() {
 		var buf bytes.Buffer
 		err := slogtest.TestHandler(
-			suite.Creator.NewHandler(&buf, infra.SimpleOptions()),
+			suite.Creator.NewHandler(suite.Creator.DecodeWriter(&buf), infra.SimpleOptions()),
 			func() []map[string]any {
 				var ms []map[string]any
 				for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
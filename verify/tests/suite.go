@@ -59,5 +59,5 @@ func (suite *SlogTestSuite) SetupTest() {
 
 // Logger returns a new slog.Logger with the specified options.
 func (suite *SlogTestSuite) Logger(options *slog.HandlerOptions) *slog.Logger {
-	return suite.Creator.NewLogger(suite.Buffer, options)
+	return suite.Creator.NewLogger(suite.Creator.DecodeWriter(suite.Buffer), options)
 }
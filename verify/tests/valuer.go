@@ -14,3 +14,31 @@ type hiddenValuer struct {
 func (r *hiddenValuer) LogValue() slog.Value {
 	return slog.AnyValue(r.v)
 }
+
+// -----------------------------------------------------------------------------
+// Pathological instances of slog.LogValuer used for testing.
+
+var _ slog.LogValuer = &deepValuer{}
+
+// deepValuer resolves to a group containing another deepValuer until depth runs out.
+type deepValuer struct {
+	depth int
+}
+
+func (r *deepValuer) LogValue() slog.Value {
+	if r.depth < 1 {
+		return slog.StringValue("bottom")
+	}
+	return slog.GroupValue(
+		slog.Int("depth", r.depth),
+		slog.Any("next", &deepValuer{depth: r.depth - 1}))
+}
+
+var _ slog.LogValuer = &panicValuer{}
+
+// panicValuer panics when resolved.
+type panicValuer struct{}
+
+func (r *panicValuer) LogValue() slog.Value {
+	panic("panicValuer")
+}
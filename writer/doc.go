@@ -0,0 +1,24 @@
+// Package writer provides io.Writer implementations for log destinations.
+//
+// # Rotating Files
+//
+// A [writer.Rotator] writes to a file which is rotated when it reaches a maximum size
+// or when a time interval has passed.
+// Rotated files are renamed with a timestamp, optionally compressed with gzip,
+// and the number of rotated files kept can be limited.
+// The Reopen method supports external rotation tools
+// which rename the file and then send a signal (usually SIGHUP) to the program.
+//
+// # Network Sink
+//
+// A [writer.Network] sends records to a TCP, UDP, or unix domain socket
+// with newline or length-prefix framing.
+// Records are buffered in memory (up to a limit) while the connection is down
+// and the connection is retried in the background with exponential backoff.
+// Records that repeatedly fail to be written are dropped rather than blocking later records.
+// The Stats method returns counts of written, dropped, and retried records.
+// The Close method flushes buffered records with a deadline.
+//
+// [writer.Network]: https://pkg.go.dev/github.com/madkins23/go-slog/writer#Network
+// [writer.Rotator]: https://pkg.go.dev/github.com/madkins23/go-slog/writer#Rotator
+package writer
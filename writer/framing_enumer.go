@@ -0,0 +1,78 @@
+// Code generated by "enumer -type=Framing"; DO NOT EDIT.
+
+package writer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _FramingName = "FramingNewlineFramingLength"
+
+var _FramingIndex = [...]uint8{0, 14, 27}
+
+const _FramingLowerName = "framingnewlineframinglength"
+
+func (i Framing) String() string {
+	if i >= Framing(len(_FramingIndex)-1) {
+		return fmt.Sprintf("Framing(%d)", i)
+	}
+	return _FramingName[_FramingIndex[i]:_FramingIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _FramingNoOp() {
+	var x [1]struct{}
+	_ = x[FramingNewline-(0)]
+	_ = x[FramingLength-(1)]
+}
+
+var _FramingValues = []Framing{FramingNewline, FramingLength}
+
+var _FramingNameToValueMap = map[string]Framing{
+	_FramingName[0:14]:       FramingNewline,
+	_FramingLowerName[0:14]:  FramingNewline,
+	_FramingName[14:27]:      FramingLength,
+	_FramingLowerName[14:27]: FramingLength,
+}
+
+var _FramingNames = []string{
+	_FramingName[0:14],
+	_FramingName[14:27],
+}
+
+// FramingString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func FramingString(s string) (Framing, error) {
+	if val, ok := _FramingNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _FramingNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to Framing values", s)
+}
+
+// FramingValues returns all values of the enum
+func FramingValues() []Framing {
+	return _FramingValues
+}
+
+// FramingStrings returns a slice of all String values of the enum
+func FramingStrings() []string {
+	strs := make([]string, len(_FramingNames))
+	copy(strs, _FramingNames)
+	return strs
+}
+
+// IsAFraming returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i Framing) IsAFraming() bool {
+	for _, v := range _FramingValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
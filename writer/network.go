@@ -0,0 +1,410 @@
+package writer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultBufferSize   = 1 << 20
+	DefaultDialTimeout  = 5 * time.Second
+	DefaultWriteTimeout = 5 * time.Second
+	DefaultCloseTimeout = 5 * time.Second
+	DefaultMinBackoff   = 100 * time.Millisecond
+	DefaultMaxBackoff   = 30 * time.Second
+	DefaultMaxAttempts  = 10
+
+	// DefaultDatagramSize is the maximum payload of a UDP datagram over IPv4.
+	DefaultDatagramSize = 65507
+)
+
+// Framing specifies how records are delimited on a Network connection.
+//
+//go:generate go run github.com/dmarkham/enumer -type=Framing
+type Framing uint8
+
+const (
+	// FramingNewline terminates each record with a newline if it doesn't already end with one.
+	FramingNewline Framing = iota
+	// FramingLength precedes each record with its length as a 32-bit big-endian integer.
+	FramingLength
+)
+
+var _ io.WriteCloser = &Network{}
+
+// NetworkOptions configures a Network writer.
+type NetworkOptions struct {
+	// Framing specifies how records are delimited.
+	// If not set defaults to FramingNewline.
+	Framing Framing
+
+	// BufferSize is the maximum number of bytes of framed records held in memory
+	// while waiting to be written (e.g. while disconnected).
+	// Records that would exceed this size are dropped.
+	// If not set defaults to the value of writer.DefaultBufferSize (= 1MiB).
+	BufferSize int
+
+	// DialTimeout limits each connection attempt.
+	// If not set defaults to the value of writer.DefaultDialTimeout (= 5s).
+	DialTimeout time.Duration
+
+	// WriteTimeout limits each write to the connection.
+	// A write that times out is treated as a broken connection.
+	// If not set defaults to the value of writer.DefaultWriteTimeout (= 5s).
+	WriteTimeout time.Duration
+
+	// CloseTimeout limits the time Close waits for buffered records to be written.
+	// If not set defaults to the value of writer.DefaultCloseTimeout (= 5s).
+	CloseTimeout time.Duration
+
+	// MinBackoff is the delay before the first reconnection attempt after a failure.
+	// The delay doubles after each consecutive failure up to MaxBackoff.
+	// If not set defaults to the value of writer.DefaultMinBackoff (= 100ms).
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between reconnection attempts.
+	// If not set defaults to the value of writer.DefaultMaxBackoff (= 30s).
+	MaxBackoff time.Duration
+
+	// MaxAttempts is the maximum number of failed writes of a single record
+	// after which the record is dropped so that it doesn't block later records.
+	// Failures to connect are not counted.
+	// If not set defaults to the value of writer.DefaultMaxAttempts (= 10).
+	MaxAttempts int
+
+	// DatagramSize is the maximum size of a framed record for datagram networks (udp, unixgram).
+	// Larger records are rejected by Write.
+	// If not set defaults to the value of writer.DefaultDatagramSize (= 65507).
+	DatagramSize int
+}
+
+// NetworkStats contains counters for a Network writer.
+type NetworkStats struct {
+	// Written is the number of records written to the connection.
+	Written uint64
+
+	// Dropped is the number of records dropped because the buffer was full,
+	// they were too large for a datagram, they could not be written after MaxAttempts tries,
+	// or they were still buffered when Close timed out.
+	Dropped uint64
+
+	// Retries is the number of failed connection attempts and failed writes.
+	Retries uint64
+
+	// Connects is the number of successful connections.
+	Connects uint64
+
+	// Buffered is the number of records currently waiting to be written.
+	Buffered int
+}
+
+// Network is an io.Writer that sends records to a TCP, UDP, or unix domain socket.
+// All methods are safe for concurrent use.
+//
+// Each call to Write is a single record, which matches slog handlers that
+// write each log record with a single call (e.g. flash.Handler).
+// Records are buffered in memory and written by a background goroutine so that
+// Write never blocks on the network.
+// The connection is made (and remade after any failure) by the background goroutine
+// with exponential backoff, during which records accumulate in the buffer.
+// A record that fails to be written is retried on the next connection,
+// so records may occasionally be duplicated but are otherwise written in order.
+// A record is dropped if it fails to be written NetworkOptions.MaxAttempts times
+// or if it is too large to be sent (EMSGSIZE).
+//
+// For datagram networks (udp, unixgram) each record is sent as a single datagram
+// and records larger than NetworkOptions.DatagramSize are rejected.
+type Network struct {
+	network  string
+	address  string
+	options  NetworkOptions
+	datagram bool
+
+	mutex    sync.Mutex
+	ready    *sync.Cond
+	queue    [][]byte
+	buffered int
+	closed   bool
+	conn     net.Conn
+	stats    NetworkStats
+
+	// ctx is canceled to abort the background goroutine when Close times out.
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewNetwork returns a new Network writer for the specified network and address
+// (see net.Dial for supported values, e.g. "tcp" and "localhost:5170").
+// If the options argument is nil default values are used.
+//
+// The connection is made in the background, so the destination need not be available yet.
+// The Close method should be called before the program exits.
+func NewNetwork(network, address string, options *NetworkOptions) (*Network, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported network '%s'", network)
+	}
+	n := &Network{
+		network:  network,
+		address:  address,
+		datagram: network == "unixgram" || strings.HasPrefix(network, "udp"),
+		done:     make(chan struct{}),
+	}
+	if options != nil {
+		n.options = *options
+	}
+	n.fixOptions()
+	n.ready = sync.NewCond(&n.mutex)
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	go n.run()
+	return n, nil
+}
+
+// Write supplies the required io.Writer interface method.
+// The record is added to the buffer to be written in the background.
+// If the buffer is full the record is dropped without returning an error
+// so that logging can continue, see Stats for the number of dropped records.
+// For datagram networks a record that is too large is dropped and an error is returned.
+func (n *Network) Write(p []byte) (int, error) {
+	frame := n.frame(p)
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.closed {
+		return 0, ErrClosed
+	}
+	if n.datagram && len(frame) > n.options.DatagramSize {
+		n.stats.Dropped++
+		return 0, fmt.Errorf("record size %d exceeds datagram size %d", len(frame), n.options.DatagramSize)
+	}
+	if n.buffered+len(frame) > n.options.BufferSize {
+		n.stats.Dropped++
+		return len(p), nil
+	}
+	n.queue = append(n.queue, frame)
+	n.buffered += len(frame)
+	n.ready.Signal()
+	return len(p), nil
+}
+
+// Close waits up to NetworkOptions.CloseTimeout for buffered records to be written,
+// then closes the connection.
+// Records still buffered after the timeout are dropped and an error is returned.
+func (n *Network) Close() error {
+	n.mutex.Lock()
+	if n.closed {
+		n.mutex.Unlock()
+		return nil
+	}
+	n.closed = true
+	n.ready.Broadcast()
+	n.mutex.Unlock()
+
+	var err error
+	defer n.cancel()
+	timer := time.NewTimer(n.options.CloseTimeout)
+	defer timer.Stop()
+	select {
+	case <-n.done:
+	case <-timer.C:
+		n.abort()
+		<-n.done
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if len(n.queue) > 0 {
+		err = fmt.Errorf("close %s %s: %d records not written", n.network, n.address, len(n.queue))
+		n.stats.Dropped += uint64(len(n.queue))
+		n.queue = nil
+		n.buffered = 0
+	}
+	if n.conn != nil {
+		err = errors.Join(err, n.conn.Close())
+		n.conn = nil
+	}
+	return err
+}
+
+// Stats returns the current counters.
+func (n *Network) Stats() NetworkStats {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	stats := n.stats
+	stats.Buffered = len(n.queue)
+	return stats
+}
+
+// -----------------------------------------------------------------------------
+
+// fixOptions sets default values for any options that are not set.
+func (n *Network) fixOptions() {
+	if n.options.BufferSize < 1 {
+		n.options.BufferSize = DefaultBufferSize
+	}
+	if n.options.DialTimeout <= 0 {
+		n.options.DialTimeout = DefaultDialTimeout
+	}
+	if n.options.WriteTimeout <= 0 {
+		n.options.WriteTimeout = DefaultWriteTimeout
+	}
+	if n.options.CloseTimeout <= 0 {
+		n.options.CloseTimeout = DefaultCloseTimeout
+	}
+	if n.options.MinBackoff <= 0 {
+		n.options.MinBackoff = DefaultMinBackoff
+	}
+	if n.options.MaxBackoff <= 0 {
+		n.options.MaxBackoff = DefaultMaxBackoff
+	}
+	if n.options.MaxBackoff < n.options.MinBackoff {
+		n.options.MaxBackoff = n.options.MinBackoff
+	}
+	if n.options.MaxAttempts < 1 {
+		n.options.MaxAttempts = DefaultMaxAttempts
+	}
+	if n.options.DatagramSize < 1 {
+		n.options.DatagramSize = DefaultDatagramSize
+	}
+}
+
+// frame returns a copy of the record with framing applied.
+func (n *Network) frame(p []byte) []byte {
+	switch n.options.Framing {
+	case FramingLength:
+		frame := make([]byte, 4, len(p)+4)
+		binary.BigEndian.PutUint32(frame, uint32(len(p)))
+		return append(frame, p...)
+	default:
+		frame := make([]byte, len(p), len(p)+1)
+		copy(frame, p)
+		if len(p) == 0 || p[len(p)-1] != '\n' {
+			frame = append(frame, '\n')
+		}
+		return frame
+	}
+}
+
+// abort stops the background goroutine, interrupting any connection attempt,
+// backoff delay, or write in progress.
+func (n *Network) abort() {
+	n.cancel()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.conn != nil {
+		_ = n.conn.SetWriteDeadline(time.Now())
+	}
+	n.ready.Broadcast()
+}
+
+// run writes buffered records, connecting as necessary, until closed or aborted.
+func (n *Network) run() {
+	defer close(n.done)
+	backoff := n.options.MinBackoff
+	// attempts is the number of failed writes of the first buffered record.
+	attempts := 0
+	for {
+		frame := n.next()
+		if frame == nil {
+			return
+		}
+		conn, err := n.connect()
+		if err == nil {
+			_ = conn.SetWriteDeadline(time.Now().Add(n.options.WriteTimeout))
+			if _, err = conn.Write(frame); err == nil {
+				backoff = n.options.MinBackoff
+				attempts = 0
+				n.remove(true)
+				continue
+			}
+			attempts++
+			if errors.Is(err, syscall.EMSGSIZE) {
+				// The record can never be written but the connection is still usable.
+				attempts = 0
+				n.remove(false)
+				continue
+			}
+			if attempts >= n.options.MaxAttempts {
+				attempts = 0
+				n.remove(false)
+			}
+		}
+		n.failed()
+		// Wait before trying again.
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-n.ctx.Done():
+			timer.Stop()
+			return
+		}
+		backoff = min(2*backoff, n.options.MaxBackoff)
+	}
+}
+
+// next waits for and returns the next buffered record without removing it.
+// The result is nil when the writer is closed with an empty buffer or aborted.
+func (n *Network) next() []byte {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for len(n.queue) < 1 && !n.closed && n.ctx.Err() == nil {
+		n.ready.Wait()
+	}
+	if len(n.queue) < 1 || n.ctx.Err() != nil {
+		return nil
+	}
+	return n.queue[0]
+}
+
+// connect returns the current connection, dialing a new one if necessary.
+func (n *Network) connect() (net.Conn, error) {
+	n.mutex.Lock()
+	conn := n.conn
+	n.mutex.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+	dialer := &net.Dialer{Timeout: n.options.DialTimeout}
+	conn, err := dialer.DialContext(n.ctx, n.network, n.address)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s %s: %w", n.network, n.address, err)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.conn = conn
+	n.stats.Connects++
+	return conn, nil
+}
+
+// remove the first buffered record after it has been written or dropped.
+func (n *Network) remove(written bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.buffered -= len(n.queue[0])
+	n.queue[0] = nil
+	n.queue = n.queue[1:]
+	if written {
+		n.stats.Written++
+	} else {
+		n.stats.Dropped++
+	}
+}
+
+// failed discards the connection after a connection or write failure.
+func (n *Network) failed() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.stats.Retries++
+	if n.conn != nil {
+		_ = n.conn.Close()
+		n.conn = nil
+	}
+}
@@ -0,0 +1,240 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastOptions returns options with short delays for testing.
+func fastOptions() *NetworkOptions {
+	return &NetworkOptions{
+		MinBackoff:   time.Millisecond,
+		MaxBackoff:   10 * time.Millisecond,
+		CloseTimeout: 5 * time.Second,
+	}
+}
+
+// accept returns a channel that receives everything read from each accepted connection.
+func accept(t *testing.T, listener net.Listener) chan []byte {
+	received := make(chan []byte, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(received)
+				return
+			}
+			go func() {
+				data, _ := io.ReadAll(conn)
+				received <- data
+			}()
+		}
+	}()
+	t.Cleanup(func() { _ = listener.Close() })
+	return received
+}
+
+func receive(t *testing.T, received chan []byte) string {
+	select {
+	case data := <-received:
+		return string(data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for data")
+		return ""
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func TestNetwork_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := accept(t, listener)
+	n, err := NewNetwork("tcp", listener.Addr().String(), fastOptions())
+	require.NoError(t, err)
+	for _, record := range []string{"one\n", "two", "three\n"} {
+		size, err := n.Write([]byte(record))
+		require.NoError(t, err)
+		assert.Equal(t, len(record), size)
+	}
+	require.NoError(t, n.Close())
+	assert.Equal(t, "one\ntwo\nthree\n", receive(t, received))
+	assert.Equal(t, NetworkStats{Written: 3, Connects: 1}, n.Stats())
+	_, err = n.Write([]byte("closed"))
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.NoError(t, n.Close())
+}
+
+func TestNetwork_UnixLength(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	received := accept(t, listener)
+	options := fastOptions()
+	options.Framing = FramingLength
+	n, err := NewNetwork("unix", path, options)
+	require.NoError(t, err)
+	records := []string{"alpha\n", "bravo", ""}
+	for _, record := range records {
+		_, err := n.Write([]byte(record))
+		require.NoError(t, err)
+	}
+	require.NoError(t, n.Close())
+	reader := bufio.NewReader(strings.NewReader(receive(t, received)))
+	for _, record := range records {
+		var length uint32
+		require.NoError(t, binary.Read(reader, binary.BigEndian, &length))
+		frame := make([]byte, length)
+		_, err := io.ReadFull(reader, frame)
+		require.NoError(t, err)
+		assert.Equal(t, record, string(frame))
+	}
+}
+
+func TestNetwork_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	n, err := NewNetwork("udp", conn.LocalAddr().String(), fastOptions())
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := n.Write([]byte("datagram " + strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	datagram := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for i := 0; i < 3; i++ {
+		size, _, err := conn.ReadFrom(datagram)
+		require.NoError(t, err)
+		assert.Equal(t, "datagram "+strconv.Itoa(i)+"\n", string(datagram[:size]))
+	}
+	require.NoError(t, n.Close())
+}
+
+func TestNetwork_Reconnect(t *testing.T) {
+	// Nothing is listening at first so records are buffered.
+	path := filepath.Join(t.TempDir(), "log.sock")
+	n, err := NewNetwork("unix", path, fastOptions())
+	require.NoError(t, err)
+	_, err = n.Write([]byte("early"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return n.Stats().Retries > 2 }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, 1, n.Stats().Buffered)
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	received := accept(t, listener)
+	_, err = n.Write([]byte("late"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return n.Stats().Written == 2 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, n.Close())
+	assert.Equal(t, "early\nlate\n", receive(t, received))
+	stats := n.Stats()
+	assert.Equal(t, uint64(1), stats.Connects)
+	assert.Zero(t, stats.Dropped)
+}
+
+func TestNetwork_Overflow(t *testing.T) {
+	options := fastOptions()
+	options.BufferSize = 10
+	options.CloseTimeout = 10 * time.Millisecond
+	n, err := NewNetwork("unix", filepath.Join(t.TempDir(), "missing.sock"), options)
+	require.NoError(t, err)
+	for _, record := range []string{"1234", "5678", "9012"} {
+		size, err := n.Write([]byte(record))
+		require.NoError(t, err)
+		assert.Equal(t, 4, size)
+	}
+	stats := n.Stats()
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, 2, stats.Buffered)
+	// Close times out with records still buffered.
+	start := time.Now()
+	assert.ErrorContains(t, n.Close(), "2 records not written")
+	assert.Less(t, time.Since(start), time.Second)
+	stats = n.Stats()
+	assert.Equal(t, uint64(3), stats.Dropped)
+	assert.Zero(t, stats.Buffered)
+}
+
+func TestNetwork_DatagramSize(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	options := fastOptions()
+	options.DatagramSize = 10
+	n, err := NewNetwork("udp", conn.LocalAddr().String(), options)
+	require.NoError(t, err)
+	size, err := n.Write([]byte("0123456789"))
+	assert.ErrorContains(t, err, "record size 11 exceeds datagram size 10")
+	assert.Zero(t, size)
+	_, err = n.Write([]byte("small"))
+	require.NoError(t, err)
+	require.NoError(t, n.Close())
+	stats := n.Stats()
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, uint64(1), stats.Written)
+}
+
+func TestNetwork_MessageSize(t *testing.T) {
+	// A datagram larger than the socket buffer fails with EMSGSIZE and is dropped.
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	options := fastOptions()
+	options.DatagramSize = 1 << 24
+	n, err := NewNetwork("unixgram", path, options)
+	require.NoError(t, err)
+	_, err = n.Write([]byte(strings.Repeat("x", 1<<23)))
+	require.NoError(t, err)
+	_, err = n.Write([]byte("after"))
+	require.NoError(t, err)
+	datagram := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	size, _, err := conn.ReadFrom(datagram)
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(datagram[:size]))
+	require.NoError(t, n.Close())
+	stats := n.Stats()
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, uint64(1), stats.Written)
+}
+
+func TestNetwork_MaxAttempts(t *testing.T) {
+	// The receiver never reads so writes time out once its queue is full.
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	options := fastOptions()
+	options.WriteTimeout = 5 * time.Millisecond
+	options.MaxAttempts = 2
+	options.CloseTimeout = 10 * time.Millisecond
+	n, err := NewNetwork("unixgram", path, options)
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		_, err = n.Write([]byte("record " + strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return n.Stats().Dropped > 2 }, 5*time.Second, time.Millisecond)
+	stats := n.Stats()
+	assert.Greater(t, stats.Written, uint64(0))
+	assert.GreaterOrEqual(t, stats.Retries, 2*stats.Dropped)
+	_ = n.Close()
+}
+
+func TestNetwork_Unsupported(t *testing.T) {
+	_, err := NewNetwork("ip", "127.0.0.1", nil)
+	assert.ErrorContains(t, err, "unsupported network 'ip'")
+}
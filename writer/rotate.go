@@ -0,0 +1,399 @@
+package writer
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFileMode = 0644
+
+	// backupTimeFormat is used to name rotated files.
+	// It sorts properly as a string and contains no characters that are bad in file names.
+	backupTimeFormat = "20060102T150405.000"
+
+	gzipExtension = ".gz"
+)
+
+// ErrClosed is returned when writing to a Rotator or Network after its Close method has been called.
+var ErrClosed = errors.New("writer closed")
+
+var _ io.WriteCloser = &Rotator{}
+
+// RotateOptions configures a Rotator.
+type RotateOptions struct {
+	// MaxSize is the size in bytes at which the file is rotated.
+	// A single write is never split, so files may be slightly larger than this.
+	// If not set (or zero) there is no size-based rotation.
+	MaxSize int64
+
+	// Interval is the time after opening the file at which it is rotated.
+	// If not set (or zero) there is no time-based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the maximum number of rotated files to keep.
+	// If not set (or zero) all rotated files are kept.
+	MaxBackups int
+
+	// Compress rotated files with gzip.
+	// Compression is done in a background goroutine.
+	Compress bool
+
+	// FileMode is used when creating the log file.
+	// If not set defaults to the value of writer.DefaultFileMode (= 0644).
+	FileMode os.FileMode
+}
+
+// Rotator is an io.Writer that writes to a file that is rotated by size and/or time.
+// All methods are safe for concurrent use.
+//
+// Rotated files are renamed by adding a timestamp before the file extension,
+// for example "app.log" becomes "app-20240102T030405.000.log".
+type Rotator struct {
+	path    string
+	options RotateOptions
+
+	mutex sync.Mutex
+	// file is nil if it could not be reopened after a failure.
+	file   *os.File
+	size   int64
+	opened time.Time
+	closed bool
+
+	// maintenance serializes compression and cleanup of backup files.
+	maintenance sync.Mutex
+	waitGroup   sync.WaitGroup
+	errLock     sync.Mutex
+	err         error
+
+	// Replaceable for testing.
+	now    func() time.Time
+	rename func(oldPath, newPath string) error
+}
+
+// NewRotator returns a new Rotator for the file at the specified path.
+// The file is opened immediately, appending if it already exists.
+// If the options argument is nil the file is never rotated.
+func NewRotator(path string, options *RotateOptions) (*Rotator, error) {
+	r := &Rotator{
+		path:   path,
+		now:    time.Now,
+		rename: os.Rename,
+	}
+	if options != nil {
+		r.options = *options
+	}
+	if r.options.FileMode == 0 {
+		r.options.FileMode = DefaultFileMode
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write supplies the required io.Writer interface method.
+// The file is rotated before writing if the write would exceed the maximum size
+// or if the rotation interval has passed.
+func (r *Rotator) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return 0, ErrClosed
+	}
+	if r.file == nil {
+		// Retry after an earlier failure to reopen the file.
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.rotationDue(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("write %s: %w", r.path, err)
+	}
+	return n, nil
+}
+
+// Rotate forces rotation of the file.
+func (r *Rotator) Rotate() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	return r.rotate()
+}
+
+// Reopen closes and reopens the file without rotating it.
+// This supports external log rotation which renames the file and then signals the program.
+func (r *Rotator) Reopen() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	if err := r.closeFile(); err != nil {
+		return errors.Join(err, r.open())
+	}
+	return r.open()
+}
+
+// ReopenOn calls Reopen whenever one of the specified signals is received.
+// Usually this would be syscall.SIGHUP on systems that support it.
+// Reopen errors are reported via the result of Close.
+// Call the returned function to stop listening for the signals.
+func (r *Rotator) ReopenOn(signals ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := r.Reopen(); err != nil && !errors.Is(err, ErrClosed) {
+					r.setError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for any background compression to finish.
+// The result includes the first error from background compression or cleanup, if any.
+func (r *Rotator) Close() error {
+	r.mutex.Lock()
+	var err error
+	if !r.closed {
+		r.closed = true
+		err = r.closeFile()
+	}
+	r.mutex.Unlock()
+	r.waitGroup.Wait()
+	r.errLock.Lock()
+	defer r.errLock.Unlock()
+	err = errors.Join(err, r.err)
+	r.err = nil
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+// open opens the file, appending if it already exists.
+// Must be called with the mutex held.
+func (r *Rotator) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, r.options.FileMode)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat %s: %w", r.path, err)
+	}
+	r.file = file
+	r.size = info.Size()
+	r.opened = r.now()
+	return nil
+}
+
+// rotationDue returns true if the file should be rotated before the next write.
+// Must be called with the mutex held.
+func (r *Rotator) rotationDue(length int64) bool {
+	if r.options.MaxSize > 0 && r.size > 0 && r.size+length > r.options.MaxSize {
+		return true
+	}
+	return r.options.Interval > 0 && r.now().Sub(r.opened) >= r.options.Interval
+}
+
+// closeFile closes the current file, if any.
+// Must be called with the mutex held.
+func (r *Rotator) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return fmt.Errorf("close %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// rotate renames the current file and opens a new one.
+// If rotation fails the original file is reopened so that writing can continue.
+// Must be called with the mutex held.
+func (r *Rotator) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return errors.Join(err, r.open())
+	}
+	backup := r.backupName(r.now())
+	if err := r.rename(r.path, backup); err != nil {
+		return errors.Join(fmt.Errorf("rename %s: %w", r.path, err), r.open())
+	}
+	if err := r.open(); err != nil {
+		// Move the original file back to continue writing to it.
+		if renameErr := r.rename(backup, r.path); renameErr != nil {
+			return errors.Join(err, fmt.Errorf("rename %s: %w", backup, renameErr))
+		}
+		return errors.Join(err, r.open())
+	}
+	if r.options.Compress || r.options.MaxBackups > 0 {
+		r.waitGroup.Add(1)
+		go r.maintain(backup)
+	}
+	return nil
+}
+
+// backupName returns a name for a rotated file that does not yet exist.
+func (r *Rotator) backupName(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	stamp := t.Format(backupTimeFormat)
+	for i := 0; ; i++ {
+		name := prefix + stamp
+		if i > 0 {
+			name += "_" + strconv.Itoa(i)
+		}
+		path := filepath.Join(dir, name+ext)
+		if !exists(path) && !exists(path+gzipExtension) {
+			return path
+		}
+	}
+}
+
+// nameParts returns the directory, backup file name prefix, and extension of the file.
+func (r *Rotator) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(r.path)
+	base := filepath.Base(r.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// maintain compresses the newly rotated file and removes old backups.
+func (r *Rotator) maintain(backup string) {
+	defer r.waitGroup.Done()
+	r.maintenance.Lock()
+	defer r.maintenance.Unlock()
+	if r.options.Compress {
+		if err := compress(backup, r.options.FileMode); err != nil {
+			r.setError(err)
+		}
+	}
+	if r.options.MaxBackups > 0 {
+		if err := r.cleanup(); err != nil {
+			r.setError(err)
+		}
+	}
+}
+
+// cleanup removes the oldest backup files beyond MaxBackups.
+func (r *Rotator) cleanup() error {
+	backups, err := r.Backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for len(backups) > r.options.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			errs = append(errs, fmt.Errorf("remove backup: %w", err))
+		}
+		backups = backups[1:]
+	}
+	return errors.Join(errs...)
+}
+
+// Backups returns the paths of rotated files, oldest first.
+func (r *Rotator) Backups() ([]string, error) {
+	dir, prefix, ext := r.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read directory %s: %w", dir, err)
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, gzipExtension), ext)
+		stamp = strings.TrimPrefix(stamp, prefix)
+		if index := strings.IndexByte(stamp, '_'); index >= 0 {
+			stamp = stamp[:index]
+		}
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+	// Timestamps sort properly as strings, ignore the gzip extension.
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], gzipExtension) < strings.TrimSuffix(backups[j], gzipExtension)
+	})
+	return backups, nil
+}
+
+func (r *Rotator) setError(err error) {
+	r.errLock.Lock()
+	defer r.errLock.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// compress replaces the specified file with a gzip compressed version.
+// Files that no longer exist (e.g. already removed by cleanup) are ignored.
+func compress(path string, mode os.FileMode) error {
+	source, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = source.Close() }()
+	target, err := os.OpenFile(path+gzipExtension, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("create %s%s: %w", path, gzipExtension, err)
+	}
+	zipper := gzip.NewWriter(target)
+	_, err = io.Copy(zipper, source)
+	err = errors.Join(err, zipper.Close(), target.Close())
+	if err != nil {
+		_ = os.Remove(path + gzipExtension)
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,237 @@
+package writer
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock provides a manually advanced time source.
+type clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestRotator(t *testing.T, options *RotateOptions) (*Rotator, *clock, string) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	r, err := NewRotator(path, options)
+	require.NoError(t, err)
+	clk := &clock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	r.now = clk.Now
+	r.opened = clk.Now()
+	return r, clk, path
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func readGzip(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// -----------------------------------------------------------------------------
+
+func TestRotator_NoRotation(t *testing.T) {
+	r, _, path := newTestRotator(t, nil)
+	for i := 0; i < 100; i++ {
+		_, err := r.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Close())
+	assert.Equal(t, strings.Repeat("line\n", 100), readFile(t, path))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+	_, err = r.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestRotator_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+	r, err := NewRotator(path, &RotateOptions{MaxSize: 10})
+	require.NoError(t, err)
+	_, err = r.Write([]byte("new\n"))
+	require.NoError(t, err)
+	_, err = r.Write([]byte("newer\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "newer\n", readFile(t, path))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "old\nnew\n", readFile(t, backups[0]))
+}
+
+func TestRotator_Size(t *testing.T) {
+	r, clk, path := newTestRotator(t, &RotateOptions{MaxSize: 12})
+	for _, line := range []string{"alpha\n", "bravo\n", "charlie\n", "delta\n"} {
+		_, err := r.Write([]byte(line))
+		require.NoError(t, err)
+		clk.advance(time.Millisecond)
+	}
+	require.NoError(t, r.Close())
+	assert.Equal(t, "delta\n", readFile(t, path))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "test-20240102T030405.002.log"), backups[0])
+	assert.Equal(t, "alpha\nbravo\n", readFile(t, backups[0]))
+	assert.Equal(t, "charlie\n", readFile(t, backups[1]))
+}
+
+func TestRotator_Interval(t *testing.T) {
+	r, clk, path := newTestRotator(t, &RotateOptions{Interval: time.Hour})
+	_, err := r.Write([]byte("alpha\n"))
+	require.NoError(t, err)
+	clk.advance(30 * time.Minute)
+	_, err = r.Write([]byte("bravo\n"))
+	require.NoError(t, err)
+	clk.advance(30 * time.Minute)
+	_, err = r.Write([]byte("charlie\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "charlie\n", readFile(t, path))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "alpha\nbravo\n", readFile(t, backups[0]))
+}
+
+func TestRotator_SameTime(t *testing.T) {
+	r, _, _ := newTestRotator(t, nil)
+	for i := 0; i < 3; i++ {
+		_, err := r.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Rotate())
+	}
+	require.NoError(t, r.Close())
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	assert.True(t, strings.HasSuffix(backups[0], "test-20240102T030405.000.log"))
+	assert.True(t, strings.HasSuffix(backups[1], "test-20240102T030405.000_1.log"))
+	assert.True(t, strings.HasSuffix(backups[2], "test-20240102T030405.000_2.log"))
+}
+
+func TestRotator_MaxBackupsCompress(t *testing.T) {
+	r, clk, path := newTestRotator(t, &RotateOptions{MaxBackups: 2, Compress: true})
+	for _, line := range []string{"alpha\n", "bravo\n", "charlie\n", "delta\n"} {
+		_, err := r.Write([]byte(line))
+		require.NoError(t, err)
+		require.NoError(t, r.Rotate())
+		clk.advance(time.Second)
+	}
+	require.NoError(t, r.Close())
+	assert.Equal(t, "", readFile(t, path))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	for i, expected := range []string{"charlie\n", "delta\n"} {
+		assert.True(t, strings.HasSuffix(backups[i], ".log.gz"))
+		assert.Equal(t, expected, readGzip(t, backups[i]))
+	}
+}
+
+func TestRotator_Reopen(t *testing.T) {
+	r, _, path := newTestRotator(t, nil)
+	_, err := r.Write([]byte("alpha\n"))
+	require.NoError(t, err)
+	// External rotation renames the file and then calls Reopen.
+	moved := path + ".1"
+	require.NoError(t, os.Rename(path, moved))
+	require.NoError(t, r.Reopen())
+	_, err = r.Write([]byte("bravo\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "alpha\n", readFile(t, moved))
+	assert.Equal(t, "bravo\n", readFile(t, path))
+	assert.ErrorIs(t, r.Reopen(), ErrClosed)
+	assert.ErrorIs(t, r.Rotate(), ErrClosed)
+}
+
+func TestRotator_RenameFails(t *testing.T) {
+	r, clk, path := newTestRotator(t, &RotateOptions{Interval: time.Hour})
+	_, err := r.Write([]byte("alpha\n"))
+	require.NoError(t, err)
+	r.rename = func(oldPath, newPath string) error {
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: os.ErrPermission}
+	}
+	clk.advance(time.Hour)
+	_, err = r.Write([]byte("bravo\n"))
+	assert.ErrorIs(t, err, os.ErrPermission)
+	assert.ErrorIs(t, r.Rotate(), os.ErrPermission)
+	// The original file has been reopened so writing continues.
+	_, err = r.Write([]byte("charlie\n"))
+	require.NoError(t, err)
+	r.rename = os.Rename
+	require.NoError(t, r.Rotate())
+	_, err = r.Write([]byte("delta\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "delta\n", readFile(t, path))
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "test-*.log"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "alpha\ncharlie\n", readFile(t, backups[0]))
+}
+
+func TestRotator_Concurrent(t *testing.T) {
+	r, _, _ := newTestRotator(t, &RotateOptions{MaxSize: 1000})
+	line := []byte(strings.Repeat("x", 99) + "\n")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := r.Write(line)
+				assert.NoError(t, err)
+				if j == 50 {
+					assert.NoError(t, r.Reopen())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, r.Close())
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	var total int
+	for _, backup := range append(backups, r.path) {
+		content := readFile(t, backup)
+		assert.LessOrEqual(t, len(content), 1000)
+		total += strings.Count(content, "\n")
+	}
+	assert.Equal(t, 1000, total)
+}
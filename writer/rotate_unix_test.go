@@ -0,0 +1,46 @@
+//go:build unix
+
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotator_ReopenOn(t *testing.T) {
+	r, _, path := newTestRotator(t, nil)
+	stop := r.ReopenOn(syscall.SIGHUP)
+	defer stop()
+	moved := path + ".1"
+	require.NoError(t, os.Rename(path, moved))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	stop()
+	require.NoError(t, r.Close())
+}
+
+func TestRotator_ReadOnlyDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for root")
+	}
+	r, _, path := newTestRotator(t, nil)
+	dir := filepath.Dir(path)
+	_, err := r.Write([]byte("alpha\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Chmod(dir, 0555))
+	defer func() { _ = os.Chmod(dir, 0755) }()
+	assert.ErrorIs(t, r.Rotate(), os.ErrPermission)
+	_, err = r.Write([]byte("bravo\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "alpha\nbravo\n", readFile(t, path))
+}